@@ -0,0 +1,217 @@
+package dynamodb
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const errCodeThrottlingException = "ThrottlingException"
+
+// CapacityHook is called on every consumed capacity and throttling event.
+// It can be used to send metrics to CloudWatch, Prometheus, etc.
+// The hook is called concurrently from the workers of BatchGetAll, BatchGet and ParallelScan,
+// so it must be safe for concurrent use.
+type CapacityHook func(CapacityEvent)
+
+// CapacityEvent is an event of consumed capacity or throttling.
+type CapacityEvent struct {
+	Operation   string
+	TableName   string
+	IsWrite     bool
+	IsThrottled bool
+	// consumed capacity of the operation. (empty when IsThrottled is true)
+	Capacity ConsumedCapacity
+	// error of the throttled request. (nil when the items are unprocessed on batch operations)
+	Error error
+}
+
+// CapacityUsage is the accumulated consumed capacity.
+type CapacityUsage struct {
+	ReadCapacityUnits  float64
+	WriteCapacityUnits float64
+	Requests           int64
+	Throttles          int64
+}
+
+// CapacityMeter counts consumed capacity by table and index.
+// Table names include the prefix.
+type CapacityMeter struct {
+	mu      sync.Mutex
+	tables  map[string]*CapacityUsage
+	indexes map[string]map[string]*CapacityUsage
+}
+
+// NewCapacityMeter returns initialized *CapacityMeter.
+func NewCapacityMeter() *CapacityMeter {
+	return &CapacityMeter{
+		tables:  make(map[string]*CapacityUsage),
+		indexes: make(map[string]map[string]*CapacityUsage),
+	}
+}
+
+// GetTableUsage returns the consumed capacity of the table including the indexes.
+func (m *CapacityMeter) GetTableUsage(tableName string) CapacityUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if u, ok := m.tables[tableName]; ok {
+		return *u
+	}
+	return CapacityUsage{}
+}
+
+// GetIndexUsage returns the consumed capacity of the index.
+func (m *CapacityMeter) GetIndexUsage(tableName, indexName string) CapacityUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if u, ok := m.indexes[tableName][indexName]; ok {
+		return *u
+	}
+	return CapacityUsage{}
+}
+
+// GetAllTableUsage returns the consumed capacity of all tables.
+func (m *CapacityMeter) GetAllTableUsage() map[string]CapacityUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]CapacityUsage, len(m.tables))
+	for name, u := range m.tables {
+		result[name] = *u
+	}
+	return result
+}
+
+// Reset clears all of the counters.
+func (m *CapacityMeter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tables = make(map[string]*CapacityUsage)
+	m.indexes = make(map[string]map[string]*CapacityUsage)
+}
+
+func (m *CapacityMeter) addCapacity(cc ConsumedCapacity, isWrite bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u := m.getTable(cc.TableName)
+	u.Requests++
+	u.add(cc.CapacityUnits, isWrite)
+	for name, c := range cc.GlobalSecondaryIndexes {
+		m.getIndex(cc.TableName, name).add(c.CapacityUnits, isWrite)
+	}
+	for name, c := range cc.LocalSecondaryIndexes {
+		m.getIndex(cc.TableName, name).add(c.CapacityUnits, isWrite)
+	}
+}
+
+func (m *CapacityMeter) addThrottle(tableName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.getTable(tableName).Throttles++
+}
+
+func (m *CapacityMeter) getTable(tableName string) *CapacityUsage {
+	u, ok := m.tables[tableName]
+	if !ok {
+		u = &CapacityUsage{}
+		m.tables[tableName] = u
+	}
+	return u
+}
+
+func (m *CapacityMeter) getIndex(tableName, indexName string) *CapacityUsage {
+	indexes, ok := m.indexes[tableName]
+	if !ok {
+		indexes = make(map[string]*CapacityUsage)
+		m.indexes[tableName] = indexes
+	}
+	u, ok := indexes[indexName]
+	if !ok {
+		u = &CapacityUsage{}
+		indexes[indexName] = u
+	}
+	return u
+}
+
+func (u *CapacityUsage) add(units float64, isWrite bool) {
+	if isWrite {
+		u.WriteCapacityUnits += units
+		return
+	}
+	u.ReadCapacityUnits += units
+}
+
+// recordCapacity adds the consumed capacity into the meter and calls the hook.
+func (svc *DynamoDB) recordCapacity(operation string, isWrite bool, list ...*SDK.ConsumedCapacity) []ConsumedCapacity {
+	var result []ConsumedCapacity
+	for _, v := range list {
+		if v == nil {
+			continue
+		}
+
+		cc := newConsumedCapacity(v)
+		result = append(result, cc)
+		svc.capacityMeter.addCapacity(cc, isWrite)
+		if svc.capacityHook != nil {
+			svc.capacityHook(CapacityEvent{
+				Operation: operation,
+				TableName: cc.TableName,
+				IsWrite:   isWrite,
+				Capacity:  cc,
+			})
+		}
+	}
+	return result
+}
+
+// recordError records the throttling event when the error is caused by throttling.
+func (svc *DynamoDB) recordError(operation, tableName string, isWrite bool, err error) {
+	if isThrottlingError(err) {
+		svc.recordThrottle(operation, tableName, isWrite, err)
+	}
+}
+
+// recordThrottle adds the throttling event into the meter and calls the hook.
+func (svc *DynamoDB) recordThrottle(operation, tableName string, isWrite bool, err error) {
+	svc.capacityMeter.addThrottle(tableName)
+	if svc.capacityHook != nil {
+		svc.capacityHook(CapacityEvent{
+			Operation:   operation,
+			TableName:   tableName,
+			IsWrite:     isWrite,
+			IsThrottled: true,
+			Error:       err,
+		})
+	}
+}
+
+// isThrottlingError checks if the error is caused by throttling or not.
+func isThrottlingError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+
+	switch aerr.Code() {
+	case SDK.ErrCodeProvisionedThroughputExceededException,
+		SDK.ErrCodeRequestLimitExceeded,
+		errCodeThrottlingException:
+		return true
+	}
+	return false
+}
+
+// WriteResult is struct for result of Put and BatchPut operations.
+type WriteResult struct {
+	ConsumedCapacity []ConsumedCapacity
+}
+
+// ConsumedCapacityUnits returns total consumed capacity units.
+func (r WriteResult) ConsumedCapacityUnits() float64 {
+	n := 0.0
+	for _, cc := range r.ConsumedCapacity {
+		n += cc.CapacityUnits
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+// testThrottledClient returns throttling errors on PutItem.
+type testThrottledClient struct {
+	*MemoryClient
+}
+
+func (c testThrottledClient) PutItem(in *SDK.PutItemInput) (*SDK.PutItemOutput, error) {
+	return nil, awserr.New(SDK.ErrCodeProvisionedThroughputExceededException, "throttled", nil)
+}
+
+func TestCapacityMeter(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+
+	var events []CapacityEvent
+	svc.SetCapacityHook(func(e CapacityEvent) {
+		events = append(events, e)
+	})
+
+	putMemoryTestItem(tbl, 1, 1, "a", "x")
+	putMemoryTestItem(tbl, 1, 2, "a", "y")
+	res, err := tbl.PutWithResult()
+	assert.NoError(err)
+	assert.Len(res.ConsumedCapacity, 2)
+	assert.Equal(2.0, res.ConsumedCapacityUnits())
+
+	putMemoryTestItem(tbl, 2, 1, "b", "")
+	res, err = tbl.BatchPutWithResult()
+	assert.NoError(err)
+	assert.Equal(1.0, res.ConsumedCapacityUnits())
+
+	cond := tbl.NewConditionList()
+	cond.AndEQ("id", 1)
+	cond.SetConsistent(true)
+	qr, err := tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(2.0, qr.ConsumedCapacity.CapacityUnits)
+	assert.Equal(2.0, qr.ConsumedCapacity.Table.CapacityUnits)
+
+	cond = tbl.NewConditionList()
+	cond.SetIndex("gsi-index")
+	cond.AndEQ("group", "a")
+	qr, err = tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(1.0, qr.ConsumedCapacity.GlobalSecondaryIndexes["gsi-index"].CapacityUnits)
+
+	_, err = tbl.GetOne(1, 1)
+	assert.NoError(err)
+
+	meter := svc.GetCapacityMeter()
+	usage := meter.GetTableUsage("mem_table")
+	assert.Equal(3.0, usage.WriteCapacityUnits)
+	assert.Equal(3.5, usage.ReadCapacityUnits)
+	assert.Equal(int64(6), usage.Requests)
+	assert.Equal(1.0, meter.GetIndexUsage("mem_table", "gsi-index").ReadCapacityUnits)
+	assert.Equal(CapacityUsage{}, meter.GetIndexUsage("mem_table", "lsi-index"))
+	assert.Len(meter.GetAllTableUsage(), 1)
+
+	assert.Len(events, 6)
+	assert.Equal("PutItem", events[0].Operation)
+	assert.True(events[0].IsWrite)
+	assert.Equal("Query", events[3].Operation)
+	assert.False(events[3].IsWrite)
+
+	meter.Reset()
+	assert.Equal(CapacityUsage{}, meter.GetTableUsage("mem_table"))
+}
+
+func TestCapacityThrottle(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+	svc.client = testThrottledClient{svc.client.(*MemoryClient)}
+
+	var events []CapacityEvent
+	svc.SetCapacityHook(func(e CapacityEvent) {
+		events = append(events, e)
+	})
+
+	putMemoryTestItem(tbl, 1, 1, "", "")
+	assert.Error(tbl.Put())
+
+	assert.Equal(int64(1), svc.GetCapacityMeter().GetTableUsage("mem_table").Throttles)
+	assert.Len(events, 1)
+	assert.True(events[0].IsThrottled)
+	assert.Error(events[0].Error)
+
+	assert.True(isThrottlingError(awserr.New(SDK.ErrCodeRequestLimitExceeded, "", nil)))
+	assert.False(isThrottlingError(awserr.New(SDK.ErrCodeConditionalCheckFailedException, "", nil)))
+	assert.False(isThrottlingError(nil))
+}
@@ -3,9 +3,13 @@ package dynamodb
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	SDKStreams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
 
 	"github.com/evalphobia/aws-sdk-go-wrapper/config"
 	"github.com/evalphobia/aws-sdk-go-wrapper/log"
@@ -19,10 +23,19 @@ const (
 
 // DynamoDB has DynamoDB client and table list.
 type DynamoDB struct {
-	client *SDK.DynamoDB
+	client        dynamodbiface.DynamoDBAPI
+	streamsClient dynamodbstreamsiface.DynamoDBStreamsAPI
 
-	logger log.Logger
-	prefix string
+	logger              log.Logger
+	prefix              string
+	batchMaxRetry       int
+	batchGetWorkers     int
+	parallelScanWorkers int
+	tableWaitInterval   time.Duration
+	tableWaitTimeout    time.Duration
+
+	capacityMeter *CapacityMeter
+	capacityHook  CapacityHook
 
 	tablesMu    sync.RWMutex
 	tables      map[string]*Table
@@ -43,19 +56,50 @@ func New(conf config.Config) (*DynamoDB, error) {
 
 // NewFromSession returns initialized *DynamoDB from aws.Session.
 func NewFromSession(sess *session.Session) *DynamoDB {
+	svc := NewWithClient(SDK.New(sess))
+	svc.streamsClient = SDKStreams.New(sess)
+	return svc
+}
+
+// NewInMemory returns initialized *DynamoDB with in-memory backend.
+func NewInMemory() *DynamoDB {
+	return NewWithClient(NewMemoryClient())
+}
+
+// NewWithClient returns initialized *DynamoDB from the client of DynamoDB API.
+// (e.g. *MemoryClient for unit tests)
+func NewWithClient(client dynamodbiface.DynamoDBAPI) *DynamoDB {
 	return &DynamoDB{
-		client:      SDK.New(sess),
-		logger:      log.DefaultLogger,
-		tables:      make(map[string]*Table),
-		writeTables: make(map[string]struct{}),
+		client:              client,
+		logger:              log.DefaultLogger,
+		batchMaxRetry:       defaultBatchMaxRetry,
+		batchGetWorkers:     defaultBatchGetWorkers,
+		parallelScanWorkers: defaultParallelScanWorkers,
+		tableWaitInterval:   defaultTableWaitInterval,
+		tableWaitTimeout:    defaultTableWaitTimeout,
+		capacityMeter:       NewCapacityMeter(),
+		tables:              make(map[string]*Table),
+		writeTables:         make(map[string]struct{}),
 	}
 }
 
 // GetClient gets aws client.
+// It returns nil when the client is not *SDK.DynamoDB.
 func (svc *DynamoDB) GetClient() *SDK.DynamoDB {
+	client, _ := svc.client.(*SDK.DynamoDB)
+	return client
+}
+
+// GetClientAPI gets the client of DynamoDB API.
+func (svc *DynamoDB) GetClientAPI() dynamodbiface.DynamoDBAPI {
 	return svc.client
 }
 
+// SetStreamsClient sets the client of DynamoDB Streams API.
+func (svc *DynamoDB) SetStreamsClient(client dynamodbstreamsiface.DynamoDBStreamsAPI) {
+	svc.streamsClient = client
+}
+
 // SetLogger sets logger.
 func (svc *DynamoDB) SetLogger(logger log.Logger) {
 	svc.logger = logger
@@ -66,6 +110,37 @@ func (svc *DynamoDB) SetPrefix(prefix string) {
 	svc.prefix = prefix
 }
 
+// TableNameWithPrefix returns the table name with prefix.
+func (svc *DynamoDB) TableNameWithPrefix(name string) string {
+	return svc.prefix + name
+}
+
+// SetBatchMaxRetry sets max retry count for unprocessed items on batch operations.
+func (svc *DynamoDB) SetBatchMaxRetry(n int) {
+	svc.batchMaxRetry = n
+}
+
+// SetBatchGetWorkers sets max number of concurrent requests on BatchGetAll and BatchGet.
+func (svc *DynamoDB) SetBatchGetWorkers(n int) {
+	svc.batchGetWorkers = n
+}
+
+// SetParallelScanWorkers sets max number of concurrent segment scans on ParallelScan.
+func (svc *DynamoDB) SetParallelScanWorkers(n int) {
+	svc.parallelScanWorkers = n
+}
+
+// SetCapacityHook sets the hook called on consumed capacity and throttling events.
+// The hook must be safe for concurrent use. (see CapacityHook)
+func (svc *DynamoDB) SetCapacityHook(hook CapacityHook) {
+	svc.capacityHook = hook
+}
+
+// GetCapacityMeter returns the meter of consumed capacity.
+func (svc *DynamoDB) GetCapacityMeter() *CapacityMeter {
+	return svc.capacityMeter
+}
+
 // ===================
 // Table Operation
 // ===================
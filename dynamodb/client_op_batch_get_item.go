@@ -1,17 +1,23 @@
 package dynamodb
 
 import (
+	"fmt"
+	"sort"
+	"sync"
+
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
+const defaultBatchGetWorkers = 4
+
 // BatchGetAll executes batch_get_item operation
+// The keys are split into chunks of 100 keys and the chunks are executed concurrently.
+// UnprocessedKeys are retried with exponential backoff, and the keys still unprocessed after all of the retries
+// are returned in the response with the error.
 func (svc *DynamoDB) BatchGetAll(in BatchGetAllRequest) (*BatchGetAllResponse, error) {
-	o, err := svc.client.BatchGetItem(in.ToInput())
-	if err != nil {
-		return nil, err
-	}
-	return newBatchGetItemResponse(o), nil
+	o, err := svc.batchGetItem(in.ToInput())
+	return newBatchGetItemResponse(o), err
 }
 
 type BatchGetAllRequest struct {
@@ -80,3 +86,161 @@ func (r BatchGetAllResponse) UnmarshalWithTagName(v interface{}, structTag strin
 	}
 	return nil
 }
+
+// batchGetItem executes BatchGetItem operations by chunks and merges the outputs.
+func (svc *DynamoDB) batchGetItem(in *SDK.BatchGetItemInput) (*SDK.BatchGetItemOutput, error) {
+	chunks := splitBatchGetItems(in.RequestItems, batchGetItemMax)
+	result := &SDK.BatchGetItemOutput{
+		Responses:       make(map[string][]map[string]*SDK.AttributeValue),
+		UnprocessedKeys: make(map[string]*SDK.KeysAndAttributes),
+	}
+	if len(chunks) == 0 {
+		return result, nil
+	}
+
+	workers := svc.batchGetWorkers
+	if workers < 1 || workers > len(chunks) {
+		workers = len(chunks)
+	}
+	chunkCh := make(chan map[string]*SDK.KeysAndAttributes, len(chunks))
+	for _, c := range chunks {
+		chunkCh <- c
+	}
+	close(chunkCh)
+
+	var mu sync.Mutex
+	errList := newErrors()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for requestItems := range chunkCh {
+				out, err := svc.batchGetChunk(requestItems, in.ReturnConsumedCapacity)
+
+				mu.Lock()
+				mergeBatchGetItemOutput(result, out)
+				if err != nil {
+					errList.Add(err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if errList.HasError() {
+		svc.Errorf("errors on `BatchGetItem` operations; errors=[%s];", errList.Error())
+		return result, errList
+	}
+	return result, nil
+}
+
+// batchGetChunk executes BatchGetItem operation and retries UnprocessedKeys with exponential backoff.
+// The returned output contains the keys which are not processed after all of the retries.
+func (svc *DynamoDB) batchGetChunk(requestItems map[string]*SDK.KeysAndAttributes, returnCapacity *string) (*SDK.BatchGetItemOutput, error) {
+	result := &SDK.BatchGetItemOutput{
+		Responses: make(map[string][]map[string]*SDK.AttributeValue),
+	}
+	maxRetry := svc.batchMaxRetry
+	for retry := 0; ; retry++ {
+		out, err := svc.client.BatchGetItem(&SDK.BatchGetItemInput{
+			RequestItems:           requestItems,
+			ReturnConsumedCapacity: returnCapacity,
+		})
+		if err != nil {
+			for tableName := range requestItems {
+				svc.recordError("BatchGetItem", tableName, false, err)
+			}
+			result.UnprocessedKeys = requestItems
+			return result, err
+		}
+		svc.recordCapacity("BatchGetItem", false, out.ConsumedCapacity...)
+
+		out.UnprocessedKeys = removeEmptyKeysAndAttributes(out.UnprocessedKeys)
+		result.UnprocessedKeys = out.UnprocessedKeys
+		out.UnprocessedKeys = nil
+		mergeBatchGetItemOutput(result, out)
+
+		requestItems = result.UnprocessedKeys
+		if len(requestItems) == 0 {
+			return result, nil
+		}
+		unprocessed := 0
+		for tableName, ka := range requestItems {
+			unprocessed += len(ka.Keys)
+			// unprocessed keys are caused by exceeding the throughput.
+			svc.recordThrottle("BatchGetItem", tableName, false, nil)
+		}
+		if retry >= maxRetry {
+			return result, fmt.Errorf("error on `BatchGetItem`; unprocessed keys remain after retries; retry=%d; unprocessed=%d", retry, unprocessed)
+		}
+
+		svc.Infof("retry on `BatchGetItem` operation; retry=%d; unprocessed=%d;", retry+1, unprocessed)
+		waitBackoff(retry)
+	}
+}
+
+// splitBatchGetItems splits the keys of the tables into chunks which have at most max keys.
+func splitBatchGetItems(requestItems map[string]*SDK.KeysAndAttributes, max int) []map[string]*SDK.KeysAndAttributes {
+	tableNames := make([]string, 0, len(requestItems))
+	for name := range requestItems {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	var chunks []map[string]*SDK.KeysAndAttributes
+	var chunk map[string]*SDK.KeysAndAttributes
+	size := 0
+	for _, name := range tableNames {
+		ka := requestItems[name]
+		keys := ka.Keys
+		for len(keys) != 0 {
+			if chunk == nil || size == max {
+				chunk = make(map[string]*SDK.KeysAndAttributes)
+				chunks = append(chunks, chunk)
+				size = 0
+			}
+
+			n := max - size
+			if n > len(keys) {
+				n = len(keys)
+			}
+			part := *ka
+			part.Keys = keys[:n]
+			chunk[name] = &part
+			size += n
+			keys = keys[n:]
+		}
+	}
+	return chunks
+}
+
+// mergeBatchGetItemOutput merges the responses, unprocessed keys and consumed capacity into the result.
+func mergeBatchGetItemOutput(result, out *SDK.BatchGetItemOutput) {
+	if out == nil {
+		return
+	}
+	for name, items := range out.Responses {
+		result.Responses[name] = append(result.Responses[name], items...)
+	}
+	for name, ka := range out.UnprocessedKeys {
+		if v, ok := result.UnprocessedKeys[name]; ok {
+			merged := *v
+			merged.Keys = append(append([]map[string]*SDK.AttributeValue{}, v.Keys...), ka.Keys...)
+			result.UnprocessedKeys[name] = &merged
+			continue
+		}
+		result.UnprocessedKeys[name] = ka
+	}
+	result.ConsumedCapacity = append(result.ConsumedCapacity, out.ConsumedCapacity...)
+}
+
+func removeEmptyKeysAndAttributes(m map[string]*SDK.KeysAndAttributes) map[string]*SDK.KeysAndAttributes {
+	for name, ka := range m {
+		if ka == nil || len(ka.Keys) == 0 {
+			delete(m, name)
+		}
+	}
+	return m
+}
@@ -0,0 +1,159 @@
+package dynamodb
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// testUnprocessedClient returns some of the keys as UnprocessedKeys on the first request.
+type testUnprocessedClient struct {
+	*MemoryClient
+
+	mu      sync.Mutex
+	calls   int
+	maxKeys int
+	seen    map[string]bool
+	always  bool
+}
+
+func (c *testUnprocessedClient) BatchGetItem(in *SDK.BatchGetItemInput) (*SDK.BatchGetItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+
+	processed := make(map[string]*SDK.KeysAndAttributes)
+	unprocessed := make(map[string]*SDK.KeysAndAttributes)
+	total := 0
+	for name, ka := range in.RequestItems {
+		total += len(ka.Keys)
+		for _, key := range ka.Keys {
+			id := *key["id"].N
+			target := processed
+			if (c.always || !c.seen[id]) && len(id)%2 == 0 {
+				target = unprocessed
+			}
+			c.seen[id] = true
+			if target[name] == nil {
+				v := *ka
+				v.Keys = nil
+				target[name] = &v
+			}
+			target[name].Keys = append(target[name].Keys, key)
+		}
+	}
+	if total > c.maxKeys {
+		c.maxKeys = total
+	}
+
+	out := &SDK.BatchGetItemOutput{Responses: map[string][]map[string]*SDK.AttributeValue{}}
+	if len(processed) != 0 {
+		var err error
+		out, err = c.MemoryClient.BatchGetItem(&SDK.BatchGetItemInput{
+			RequestItems:           processed,
+			ReturnConsumedCapacity: in.ReturnConsumedCapacity,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	out.UnprocessedKeys = unprocessed
+	return out, nil
+}
+
+func TestBatchGetAll(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	for i := 1; i <= 250; i++ {
+		putMemoryTestItem(tbl, i, 1, "", "")
+	}
+	assert.NoError(tbl.BatchPut())
+
+	svc := tbl.service
+	client := &testUnprocessedClient{
+		MemoryClient: svc.client.(*MemoryClient),
+		seen:         make(map[string]bool),
+	}
+	svc.client = client
+	svc.SetBatchGetWorkers(2)
+
+	keys := make([]map[string]AttributeValue, 0, 260)
+	for i := 1; i <= 260; i++ {
+		keys = append(keys, map[string]AttributeValue{
+			"id":   {Number: strconv.Itoa(i)},
+			"time": {Number: "1"},
+		})
+	}
+
+	res, err := tbl.BatchGet(BatchGetRequest{
+		RequestItems:           KeysAndAttributes{Keys: keys},
+		ReturnConsumedCapacity: SDK.ReturnConsumedCapacityTotal,
+	})
+	assert.NoError(err)
+	assert.Len(res.Responses, 250, "the keys of missing items are ignored")
+	assert.Empty(res.UnprocessedKeys.Keys)
+	assert.Equal(batchGetItemMax, client.maxKeys)
+	assert.True(client.calls > 3, "unprocessed keys should be retried")
+	assert.NotEmpty(res.ConsumedCapacity)
+
+	var items []struct {
+		ID int `dynamodb:"id"`
+	}
+	assert.NoError(res.Unmarshal(&items))
+	ids := make([]int, len(items))
+	for i, v := range items {
+		ids[i] = v.ID
+	}
+	sort.Ints(ids)
+	assert.Equal(1, ids[0])
+	assert.Equal(250, ids[249])
+
+	// unprocessed keys remain after the retries.
+	client.always = true
+	svc.SetBatchMaxRetry(1)
+	all, err := svc.BatchGetAll(BatchGetAllRequest{
+		RequestItems: map[string]KeysAndAttributes{
+			"mem_table": {Keys: keys[:20]},
+		},
+	})
+	assert.Error(err)
+	assert.Len(all.Responses["mem_table"], 9)
+	assert.Len(all.UnprocessedKeys["mem_table"].Keys, 11)
+
+	var tableItems map[string][]struct {
+		ID int `dynamodb:"id"`
+	}
+	assert.NoError(all.Unmarshal(&tableItems))
+	assert.Len(tableItems["mem_table"], 9)
+}
+
+func TestSplitBatchGetItems(t *testing.T) {
+	assert := assert.New(t)
+
+	newKeys := func(n int) *SDK.KeysAndAttributes {
+		ka := &SDK.KeysAndAttributes{ProjectionExpression: pointers.String("id")}
+		for i := 0; i < n; i++ {
+			ka.Keys = append(ka.Keys, map[string]*SDK.AttributeValue{"id": {N: pointers.String(strconv.Itoa(i))}})
+		}
+		return ka
+	}
+
+	chunks := splitBatchGetItems(map[string]*SDK.KeysAndAttributes{
+		"a": newKeys(150),
+		"b": newKeys(70),
+	}, 100)
+	assert.Len(chunks, 3)
+	assert.Len(chunks[0]["a"].Keys, 100)
+	assert.Len(chunks[1]["a"].Keys, 50)
+	assert.Len(chunks[1]["b"].Keys, 50)
+	assert.Len(chunks[2]["b"].Keys, 20)
+	assert.Equal("id", *chunks[2]["b"].ProjectionExpression)
+
+	assert.Empty(splitBatchGetItems(nil, 100))
+}
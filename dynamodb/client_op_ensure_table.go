@@ -0,0 +1,597 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const (
+	defaultTableWaitInterval = 5 * time.Second
+	defaultTableWaitTimeout  = 30 * time.Minute
+)
+
+// types of SchemaChange.
+const (
+	SchemaChangeCreateTable         = "CreateTable"
+	SchemaChangeUpdateBillingMode   = "UpdateBillingMode"
+	SchemaChangeUpdateThroughput    = "UpdateThroughput"
+	SchemaChangeDeleteGSI           = "DeleteGSI"
+	SchemaChangeCreateGSI           = "CreateGSI"
+	SchemaChangeUpdateGSIThroughput = "UpdateGSIThroughput"
+	SchemaChangeUpdateStream        = "UpdateStream"
+	SchemaChangeUpdateSSE           = "UpdateSSE"
+	SchemaChangeUpdateTTL           = "UpdateTTL"
+	SchemaChangeUpdatePITR          = "UpdatePITR"
+)
+
+// SchemaChange is a planned change of the table on EnsureTable.
+type SchemaChange struct {
+	Type   string
+	Table  string
+	Target string
+	From   string
+	To     string
+
+	createTable *SDK.CreateTableInput
+	updateTable *SDK.UpdateTableInput
+	updateTTL   *SDK.UpdateTimeToLiveInput
+	updatePITR  *SDK.UpdateContinuousBackupsInput
+}
+
+// String returns the summary of the change.
+func (c SchemaChange) String() string {
+	s := fmt.Sprintf("%s; table=%s;", c.Type, c.Table)
+	if c.Target != "" {
+		s += fmt.Sprintf(" target=%s;", c.Target)
+	}
+	if c.From != "" || c.To != "" {
+		s += fmt.Sprintf(" from=%s; to=%s;", c.From, c.To)
+	}
+	return s
+}
+
+// EnsureTable compares the table design with the existing table, and creates or updates the table.
+// It creates the table when it does not exist, and adds or removes GSIs, updates throughput, billing mode,
+// stream, server-side encryption, TTL and point-in-time recovery. It waits for the table to be ACTIVE after each change.
+// Key schema and LSI cannot be changed on the existing table.
+func (svc *DynamoDB) EnsureTable(design *TableDesign) ([]SchemaChange, error) {
+	changes, err := svc.planTable(design)
+	if err != nil {
+		svc.Errorf("error on `EnsureTable` operation; table=%s; error=%s;", svc.prefix+design.GetName(), err.Error())
+		return nil, err
+	}
+
+	for i, c := range changes {
+		if err := svc.applySchemaChange(c); err != nil {
+			svc.Errorf("error on `EnsureTable` operation; change=[%s]; error=%s;", c.String(), err.Error())
+			return changes[:i], err
+		}
+		svc.Infof("success on `EnsureTable` operation; change=[%s];", c.String())
+	}
+
+	if tbl := svc.GetCachedTable(design.GetName()); tbl != nil && len(changes) != 0 {
+		if _, err := tbl.RefreshDesign(); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// EnsureTableDryRun returns the planned changes of EnsureTable without changing the table.
+func (svc *DynamoDB) EnsureTableDryRun(design *TableDesign) ([]SchemaChange, error) {
+	changes, err := svc.planTable(design)
+	if err != nil {
+		svc.Errorf("error on `EnsureTableDryRun` operation; table=%s; error=%s;", svc.prefix+design.GetName(), err.Error())
+		return nil, err
+	}
+	return changes, nil
+}
+
+// SetTableWaitInterval sets polling interval to wait for the table to be ACTIVE.
+func (svc *DynamoDB) SetTableWaitInterval(d time.Duration) {
+	svc.tableWaitInterval = d
+}
+
+// SetTableWaitTimeout sets timeout to wait for the table to be ACTIVE.
+func (svc *DynamoDB) SetTableWaitTimeout(d time.Duration) {
+	svc.tableWaitTimeout = d
+}
+
+// planTable returns the changes to make the table the same as the design.
+func (svc *DynamoDB) planTable(design *TableDesign) ([]SchemaChange, error) {
+	if design.HashKey == nil {
+		return nil, fmt.Errorf("cannot find hashkey in TableDesign")
+	}
+
+	tableName := svc.prefix + design.GetName()
+	out, err := svc.client.DescribeTable(&SDK.DescribeTableInput{
+		TableName: pointers.String(tableName),
+	})
+	switch {
+	case isResourceNotFoundError(err):
+		return planCreateTable(tableName, design), nil
+	case err != nil:
+		return nil, err
+	}
+
+	current := out.Table
+	if err := validateTableSchema(current, design); err != nil {
+		return nil, err
+	}
+
+	changes := planTableCapacity(tableName, current, design)
+	changes = append(changes, planGSI(tableName, current, design)...)
+	changes = append(changes, planStream(tableName, current, design)...)
+	changes = append(changes, planSSE(tableName, current, design)...)
+
+	if design.GetTTL() != nil {
+		ttl, err := svc.client.DescribeTimeToLive(&SDK.DescribeTimeToLiveInput{
+			TableName: pointers.String(tableName),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ttlChanges, err := planTTL(tableName, ttl.TimeToLiveDescription, design)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, ttlChanges...)
+	}
+
+	if design.GetPITR() != nil {
+		backups, err := svc.client.DescribeContinuousBackups(&SDK.DescribeContinuousBackupsInput{
+			TableName: pointers.String(tableName),
+		})
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, planPITR(tableName, backups.ContinuousBackupsDescription, design)...)
+	}
+	return changes, nil
+}
+
+func planCreateTable(tableName string, design *TableDesign) []SchemaChange {
+	in := design.CreateTableInput()
+	in.TableName = pointers.String(tableName)
+	changes := []SchemaChange{{
+		Type:        SchemaChangeCreateTable,
+		Table:       tableName,
+		createTable: in,
+	}}
+
+	if ttl := design.GetTTL(); ttl != nil && *ttl.Enabled {
+		changes = append(changes, newTTLChange(tableName, ttl, "", *ttl.AttributeName))
+	}
+	if pitr := design.GetPITR(); pitr != nil && *pitr.PointInTimeRecoveryEnabled {
+		changes = append(changes, newPITRChange(tableName, pitr))
+	}
+	return changes
+}
+
+// validateTableSchema checks unchangeable key schema and LSI.
+func validateTableSchema(current *SDK.TableDescription, design *TableDesign) error {
+	hashKey, rangeKey := keySchemaNames(current.KeySchema)
+	if hashKey != design.GetHashKeyName() || rangeKey != design.GetRangeKeyName() {
+		return fmt.Errorf("key schema cannot be changed; current=[%s, %s]; design=[%s, %s];",
+			hashKey, rangeKey, design.GetHashKeyName(), design.GetRangeKeyName())
+	}
+
+	var currentLSI, designLSI []string
+	for _, lsi := range current.LocalSecondaryIndexes {
+		currentLSI = append(currentLSI, *lsi.IndexName)
+	}
+	for _, lsi := range design.ListLSI() {
+		designLSI = append(designLSI, *lsi.IndexName)
+	}
+	sort.Strings(currentLSI)
+	sort.Strings(designLSI)
+	if fmt.Sprint(currentLSI) != fmt.Sprint(designLSI) {
+		return fmt.Errorf("LSI cannot be changed; current=%v; design=%v;", currentLSI, designLSI)
+	}
+	return nil
+}
+
+// planTableCapacity returns the change of billing mode or throughput.
+func planTableCapacity(tableName string, current *SDK.TableDescription, design *TableDesign) []SchemaChange {
+	currentMode := currentBillingMode(current)
+	tp := newProvisionedThroughput(design.GetReadCapacity(), design.GetWriteCapacity())
+
+	if currentMode != design.GetBillingMode() {
+		in := &SDK.UpdateTableInput{
+			TableName:   pointers.String(tableName),
+			BillingMode: pointers.String(design.GetBillingMode()),
+		}
+		if !design.IsPayPerRequest() {
+			// throughput of the table and existing GSIs are required for PROVISIONED.
+			in.ProvisionedThroughput = tp
+			for _, gsi := range current.GlobalSecondaryIndexes {
+				d := findDesignGSI(design, *gsi.IndexName)
+				if d == nil || !isSameKeySchema(gsi.KeySchema, d.KeySchema) {
+					continue
+				}
+				// the GSI without throughput in the design uses the throughput of the table.
+				gsiTP := d.ProvisionedThroughput
+				if !hasThroughput(gsiTP) {
+					gsiTP = tp
+				}
+				in.GlobalSecondaryIndexUpdates = append(in.GlobalSecondaryIndexUpdates, &SDK.GlobalSecondaryIndexUpdate{
+					Update: &SDK.UpdateGlobalSecondaryIndexAction{
+						IndexName:             gsi.IndexName,
+						ProvisionedThroughput: gsiTP,
+					},
+				})
+			}
+		}
+		return []SchemaChange{{
+			Type:        SchemaChangeUpdateBillingMode,
+			Table:       tableName,
+			From:        currentMode,
+			To:          design.GetBillingMode(),
+			updateTable: in,
+		}}
+	}
+
+	if design.IsPayPerRequest() || isSameThroughput(current.ProvisionedThroughput, tp) {
+		return nil
+	}
+	return []SchemaChange{{
+		Type:  SchemaChangeUpdateThroughput,
+		Table: tableName,
+		From:  formatThroughputDescription(current.ProvisionedThroughput),
+		To:    formatThroughput(tp),
+		updateTable: &SDK.UpdateTableInput{
+			TableName:             pointers.String(tableName),
+			ProvisionedThroughput: tp,
+		},
+	}}
+}
+
+// planGSI returns the changes of GSIs.
+// The GSI which key schema is changed is deleted and created again.
+// The throughput of the GSI is not updated when the design does not specify it,
+// and the GSI is created with the throughput of the table.
+func planGSI(tableName string, current *SDK.TableDescription, design *TableDesign) []SchemaChange {
+	isBillingChanged := currentBillingMode(current) != design.GetBillingMode()
+
+	var deletes, creates, updates []SchemaChange
+	existing := make(map[string]struct{})
+	for _, gsi := range current.GlobalSecondaryIndexes {
+		d := findDesignGSI(design, *gsi.IndexName)
+		switch {
+		case d == nil || !isSameKeySchema(gsi.KeySchema, d.KeySchema):
+			deletes = append(deletes, SchemaChange{
+				Type:   SchemaChangeDeleteGSI,
+				Table:  tableName,
+				Target: *gsi.IndexName,
+				updateTable: &SDK.UpdateTableInput{
+					TableName: pointers.String(tableName),
+					GlobalSecondaryIndexUpdates: []*SDK.GlobalSecondaryIndexUpdate{{
+						Delete: &SDK.DeleteGlobalSecondaryIndexAction{IndexName: gsi.IndexName},
+					}},
+				},
+			})
+			continue
+		case design.IsPayPerRequest(),
+			isBillingChanged,
+			!hasThroughput(d.ProvisionedThroughput),
+			isSameThroughput(gsi.ProvisionedThroughput, d.ProvisionedThroughput):
+		default:
+			updates = append(updates, SchemaChange{
+				Type:   SchemaChangeUpdateGSIThroughput,
+				Table:  tableName,
+				Target: *gsi.IndexName,
+				From:   formatThroughputDescription(gsi.ProvisionedThroughput),
+				To:     formatThroughput(d.ProvisionedThroughput),
+				updateTable: &SDK.UpdateTableInput{
+					TableName: pointers.String(tableName),
+					GlobalSecondaryIndexUpdates: []*SDK.GlobalSecondaryIndexUpdate{{
+						Update: &SDK.UpdateGlobalSecondaryIndexAction{
+							IndexName:             gsi.IndexName,
+							ProvisionedThroughput: d.ProvisionedThroughput,
+						},
+					}},
+				},
+			})
+		}
+		existing[*gsi.IndexName] = struct{}{}
+	}
+
+	for _, gsi := range design.ListGSI() {
+		if _, ok := existing[*gsi.IndexName]; ok {
+			continue
+		}
+
+		action := &SDK.CreateGlobalSecondaryIndexAction{
+			IndexName:             gsi.IndexName,
+			KeySchema:             gsi.KeySchema,
+			Projection:            gsi.Projection,
+			ProvisionedThroughput: gsi.ProvisionedThroughput,
+		}
+		switch {
+		case design.IsPayPerRequest():
+			action.ProvisionedThroughput = nil
+		case !hasThroughput(action.ProvisionedThroughput):
+			action.ProvisionedThroughput = newProvisionedThroughput(design.GetReadCapacity(), design.GetWriteCapacity())
+		}
+		creates = append(creates, SchemaChange{
+			Type:   SchemaChangeCreateGSI,
+			Table:  tableName,
+			Target: *gsi.IndexName,
+			updateTable: &SDK.UpdateTableInput{
+				TableName:            pointers.String(tableName),
+				AttributeDefinitions: design.AttributeList(),
+				GlobalSecondaryIndexUpdates: []*SDK.GlobalSecondaryIndexUpdate{{
+					Create: action,
+				}},
+			},
+		})
+	}
+
+	changes := append(deletes, creates...)
+	return append(changes, updates...)
+}
+
+// planStream returns the changes of DynamoDB Streams.
+// The stream is disabled once to change the view type.
+func planStream(tableName string, current *SDK.TableDescription, design *TableDesign) []SchemaChange {
+	spec := design.GetStream()
+	if spec == nil {
+		return nil
+	}
+
+	currentType := ""
+	if s := current.StreamSpecification; s != nil && s.StreamEnabled != nil && *s.StreamEnabled {
+		currentType = *s.StreamViewType
+	}
+	designType := ""
+	if *spec.StreamEnabled {
+		designType = *spec.StreamViewType
+	}
+	if currentType == designType {
+		return nil
+	}
+
+	var changes []SchemaChange
+	if currentType != "" {
+		changes = append(changes, newStreamChange(tableName, currentType, "", &SDK.StreamSpecification{
+			StreamEnabled: pointers.Bool(false),
+		}))
+	}
+	if designType != "" {
+		changes = append(changes, newStreamChange(tableName, "", designType, spec))
+	}
+	return changes
+}
+
+func newStreamChange(tableName, from, to string, spec *SDK.StreamSpecification) SchemaChange {
+	return SchemaChange{
+		Type:  SchemaChangeUpdateStream,
+		Table: tableName,
+		From:  from,
+		To:    to,
+		updateTable: &SDK.UpdateTableInput{
+			TableName:           pointers.String(tableName),
+			StreamSpecification: spec,
+		},
+	}
+}
+
+// planSSE returns the change of server-side encryption.
+func planSSE(tableName string, current *SDK.TableDescription, design *TableDesign) []SchemaChange {
+	spec := design.GetSSE()
+	if spec == nil {
+		return nil
+	}
+
+	isEnabled := current.SSEDescription != nil && current.SSEDescription.Status != nil && isSSEEnabledStatus(*current.SSEDescription.Status)
+	if isEnabled == *spec.Enabled {
+		return nil
+	}
+	return []SchemaChange{{
+		Type:  SchemaChangeUpdateSSE,
+		Table: tableName,
+		From:  fmt.Sprint(isEnabled),
+		To:    fmt.Sprint(*spec.Enabled),
+		updateTable: &SDK.UpdateTableInput{
+			TableName:        pointers.String(tableName),
+			SSESpecification: spec,
+		},
+	}}
+}
+
+// planTTL returns the changes of TTL.
+// The attribute cannot be changed in one run, since DynamoDB rejects UpdateTimeToLive for about an hour after the last one.
+func planTTL(tableName string, current *SDK.TimeToLiveDescription, design *TableDesign) ([]SchemaChange, error) {
+	spec := design.GetTTL()
+	if spec == nil {
+		return nil, nil
+	}
+
+	currentAttr := ""
+	if current != nil && current.TimeToLiveStatus != nil && current.AttributeName != nil {
+		switch *current.TimeToLiveStatus {
+		case SDK.TimeToLiveStatusEnabled, SDK.TimeToLiveStatusEnabling:
+			currentAttr = *current.AttributeName
+		}
+	}
+	designAttr := ""
+	if *spec.Enabled {
+		designAttr = *spec.AttributeName
+	}
+
+	switch {
+	case currentAttr == designAttr:
+		return nil, nil
+	case currentAttr != "" && designAttr != "":
+		return nil, fmt.Errorf("TTL attribute cannot be changed in one run; disable TTL by Table.DisableTTL() and run EnsureTable again after the TTL is disabled, it can take up to one hour; current=%s; design=%s;", currentAttr, designAttr)
+	case designAttr == "":
+		return []SchemaChange{newTTLChange(tableName, &SDK.TimeToLiveSpecification{
+			AttributeName: pointers.String(currentAttr),
+			Enabled:       pointers.Bool(false),
+		}, currentAttr, "")}, nil
+	}
+	return []SchemaChange{newTTLChange(tableName, spec, "", designAttr)}, nil
+}
+
+func newTTLChange(tableName string, spec *SDK.TimeToLiveSpecification, from, to string) SchemaChange {
+	return SchemaChange{
+		Type:  SchemaChangeUpdateTTL,
+		Table: tableName,
+		From:  from,
+		To:    to,
+		updateTTL: &SDK.UpdateTimeToLiveInput{
+			TableName:               pointers.String(tableName),
+			TimeToLiveSpecification: spec,
+		},
+	}
+}
+
+// planPITR returns the change of point-in-time recovery.
+func planPITR(tableName string, current *SDK.ContinuousBackupsDescription, design *TableDesign) []SchemaChange {
+	spec := design.GetPITR()
+	if spec == nil {
+		return nil
+	}
+
+	isEnabled := false
+	if current != nil && current.PointInTimeRecoveryDescription != nil {
+		isEnabled = NewPointInTimeRecoveryDescription(current.PointInTimeRecoveryDescription).IsEnabled()
+	}
+	if isEnabled == *spec.PointInTimeRecoveryEnabled {
+		return nil
+	}
+	c := newPITRChange(tableName, spec)
+	c.From = fmt.Sprint(isEnabled)
+	return []SchemaChange{c}
+}
+
+func newPITRChange(tableName string, spec *SDK.PointInTimeRecoverySpecification) SchemaChange {
+	return SchemaChange{
+		Type:  SchemaChangeUpdatePITR,
+		Table: tableName,
+		To:    fmt.Sprint(*spec.PointInTimeRecoveryEnabled),
+		updatePITR: &SDK.UpdateContinuousBackupsInput{
+			TableName:                        pointers.String(tableName),
+			PointInTimeRecoverySpecification: spec,
+		},
+	}
+}
+
+// applySchemaChange executes the change and waits for the table to be ACTIVE.
+func (svc *DynamoDB) applySchemaChange(c SchemaChange) error {
+	var err error
+	switch {
+	case c.createTable != nil:
+		_, err = svc.client.CreateTable(c.createTable)
+	case c.updateTable != nil:
+		_, err = svc.client.UpdateTable(c.updateTable)
+	case c.updateTTL != nil:
+		_, err = svc.client.UpdateTimeToLive(c.updateTTL)
+	case c.updatePITR != nil:
+		_, err = svc.client.UpdateContinuousBackups(c.updatePITR)
+	}
+	if err != nil {
+		return err
+	}
+	return svc.waitTableActive(c.Table)
+}
+
+// waitTableActive waits for the table and all of the GSIs to be ACTIVE.
+func (svc *DynamoDB) waitTableActive(tableName string) error {
+	deadline := time.Now().Add(svc.tableWaitTimeout)
+	for {
+		out, err := svc.client.DescribeTable(&SDK.DescribeTableInput{
+			TableName: pointers.String(tableName),
+		})
+		if err != nil {
+			return err
+		}
+		if isTableActive(out.Table) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout to wait for the table to be ACTIVE; table=%s; status=%s;", tableName, *out.Table.TableStatus)
+		}
+		time.Sleep(svc.tableWaitInterval)
+	}
+}
+
+func isTableActive(desc *SDK.TableDescription) bool {
+	if desc.TableStatus == nil || *desc.TableStatus != SDK.TableStatusActive {
+		return false
+	}
+	for _, gsi := range desc.GlobalSecondaryIndexes {
+		if gsi.IndexStatus != nil && *gsi.IndexStatus != SDK.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
+
+func isResourceNotFoundError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == SDK.ErrCodeResourceNotFoundException
+}
+
+// currentBillingMode returns the billing mode of the table.
+func currentBillingMode(desc *SDK.TableDescription) string {
+	if desc.BillingModeSummary == nil || desc.BillingModeSummary.BillingMode == nil {
+		return BillingModeProvisioned
+	}
+	return *desc.BillingModeSummary.BillingMode
+}
+
+func findDesignGSI(design *TableDesign, name string) *SDK.GlobalSecondaryIndex {
+	for _, gsi := range design.ListGSI() {
+		if *gsi.IndexName == name {
+			return gsi
+		}
+	}
+	return nil
+}
+
+func isSameKeySchema(a, b []*SDK.KeySchemaElement) bool {
+	hashA, rangeA := keySchemaNames(a)
+	hashB, rangeB := keySchemaNames(b)
+	return hashA == hashB && rangeA == rangeB
+}
+
+func isSameThroughput(current *SDK.ProvisionedThroughputDescription, tp *SDK.ProvisionedThroughput) bool {
+	if current == nil || tp == nil {
+		return current == nil && tp == nil
+	}
+	return pointerInt64(current.ReadCapacityUnits) == pointerInt64(tp.ReadCapacityUnits) &&
+		pointerInt64(current.WriteCapacityUnits) == pointerInt64(tp.WriteCapacityUnits)
+}
+
+// hasThroughput checks if the read and write capacity units are specified or not.
+func hasThroughput(tp *SDK.ProvisionedThroughput) bool {
+	return tp != nil && pointerInt64(tp.ReadCapacityUnits) > 0 && pointerInt64(tp.WriteCapacityUnits) > 0
+}
+
+func formatThroughput(tp *SDK.ProvisionedThroughput) string {
+	if tp == nil {
+		return ""
+	}
+	return fmt.Sprintf("r=%d,w=%d", pointerInt64(tp.ReadCapacityUnits), pointerInt64(tp.WriteCapacityUnits))
+}
+
+func formatThroughputDescription(tp *SDK.ProvisionedThroughputDescription) string {
+	if tp == nil {
+		return ""
+	}
+	return fmt.Sprintf("r=%d,w=%d", pointerInt64(tp.ReadCapacityUnits), pointerInt64(tp.WriteCapacityUnits))
+}
+
+func pointerInt64(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
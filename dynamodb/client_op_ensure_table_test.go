@@ -0,0 +1,205 @@
+package dynamodb
+
+import (
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func getEnsureTableDesign() *TableDesign {
+	design := NewTableDesignWithHashKeyN("ensure_table", "id")
+	design.AddRangeKeyN("time")
+	design.AddLSIS("lsi-index", "lsi_key")
+	design.AddGSISN("gsi-index", "group", "time")
+	return design
+}
+
+func changeTypes(changes []SchemaChange) []string {
+	list := make([]string, len(changes))
+	for i, c := range changes {
+		list[i] = c.Type
+	}
+	return list
+}
+
+func TestEnsureTable(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := NewInMemory()
+	svc.SetTableWaitInterval(0)
+
+	// dry run does not create the table.
+	design := getEnsureTableDesign()
+	design.SetTTL("expired_at")
+	changes, err := svc.EnsureTableDryRun(design)
+	assert.NoError(err)
+	assert.Equal([]string{SchemaChangeCreateTable, SchemaChangeUpdateTTL}, changeTypes(changes))
+	_, err = svc.DescribeTable("ensure_table")
+	assert.Error(err)
+
+	changes, err = svc.EnsureTable(design)
+	assert.NoError(err)
+	assert.Len(changes, 2)
+	tbl, err := svc.GetTable("ensure_table")
+	assert.NoError(err)
+	assert.True(tbl.GetDesign().HasGSI())
+
+	changes, err = svc.EnsureTable(design)
+	assert.NoError(err)
+	assert.Empty(changes, "same design should not have changes")
+
+	// add and remove GSI, and update throughput.
+	design = getEnsureTableDesign()
+	design.GSI = nil
+	design.SetThroughput(2, 3)
+	design.AddGSIS("new-index", "name")
+	changes, err = svc.EnsureTable(design)
+	assert.NoError(err)
+	assert.Equal([]string{SchemaChangeUpdateThroughput, SchemaChangeDeleteGSI, SchemaChangeCreateGSI}, changeTypes(changes))
+	assert.Equal("gsi-index", changes[1].Target)
+	assert.Equal("r=1,w=1", changes[0].From)
+	assert.Equal("r=2,w=3", changes[0].To)
+
+	desc, err := svc.DescribeTable("ensure_table")
+	assert.NoError(err)
+	assert.Len(desc.GlobalSecondaryIndexes, 1)
+	assert.Equal("new-index", desc.GlobalSecondaryIndexes[0].IndexName)
+	assert.Equal(int64(2), desc.ProvisionedThroughput.ReadCapacityUnits)
+
+	// TTL attribute cannot be changed in one run.
+	design.SetTTL("ttl")
+	_, err = svc.EnsureTableDryRun(design)
+	assert.Error(err)
+	assert.NoError(tbl.DisableTTL())
+
+	// billing mode, stream, SSE, TTL and PITR.
+	design.SetBillingMode(BillingModePayPerRequest)
+	design.SetStream(StreamViewTypeNewImage)
+	design.SetSSE(true)
+	design.SetPITR(true)
+	changes, err = svc.EnsureTable(design)
+	assert.NoError(err)
+	assert.Equal([]string{
+		SchemaChangeUpdateBillingMode,
+		SchemaChangeUpdateStream,
+		SchemaChangeUpdateSSE,
+		SchemaChangeUpdateTTL,
+		SchemaChangeUpdatePITR,
+	}, changeTypes(changes))
+	assert.Equal("", changes[3].From)
+	assert.Equal("ttl", changes[3].To)
+
+	design = tbl.GetDesign()
+	assert.True(design.IsPayPerRequest())
+	assert.Equal(StreamViewTypeNewImage, *design.GetStream().StreamViewType)
+	assert.True(*design.GetSSE().Enabled)
+	pitr, err := tbl.DescribePITR()
+	assert.NoError(err)
+	assert.True(pitr.IsEnabled())
+
+	// change the view type of the stream.
+	design = getEnsureTableDesign()
+	design.GSI = nil
+	design.AddGSIS("new-index", "name")
+	design.SetBillingMode(BillingModePayPerRequest)
+	design.SetStream(StreamViewTypeKeysOnly)
+	changes, err = svc.EnsureTableDryRun(design)
+	assert.NoError(err)
+	assert.Equal([]string{SchemaChangeUpdateStream, SchemaChangeUpdateStream}, changeTypes(changes))
+	assert.Equal(StreamViewTypeNewImage, changes[0].From)
+	assert.Equal(StreamViewTypeKeysOnly, changes[1].To)
+
+	// key schema and LSI cannot be changed.
+	design = NewTableDesignWithHashKeyN("ensure_table", "id")
+	_, err = svc.EnsureTable(design)
+	assert.Error(err)
+
+	design = getEnsureTableDesign()
+	design.LSI = nil
+	_, err = svc.EnsureTable(design)
+	assert.Error(err)
+}
+
+func TestPlanTableCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	current := &SDK.TableDescription{
+		BillingModeSummary: &SDK.BillingModeSummary{BillingMode: pointers.String(BillingModePayPerRequest)},
+		GlobalSecondaryIndexes: []*SDK.GlobalSecondaryIndexDescription{
+			{
+				IndexName: pointers.String("gsi-index"),
+				KeySchema: NewKeySchema(NewHashKeyElement("group"), NewRangeKeyElement("time")),
+			},
+			{
+				IndexName: pointers.String("own-index"),
+				KeySchema: NewKeySchema(NewHashKeyElement("name")),
+			},
+		},
+	}
+
+	// the GSI without throughput uses the throughput of the table on switching to PROVISIONED.
+	design := getEnsureTableDesign()
+	design.AddGSIS("own-index", "name")
+	design.GSI[0].ProvisionedThroughput = nil
+	design.GSI[1].ProvisionedThroughput = newProvisionedThroughput(7, 8)
+	design.SetThroughput(5, 6)
+	changes := planTableCapacity("ensure_table", current, design)
+	assert.Equal([]string{SchemaChangeUpdateBillingMode}, changeTypes(changes))
+
+	in := changes[0].updateTable
+	assert.Equal(BillingModeProvisioned, *in.BillingMode)
+	assert.Equal(int64(5), *in.ProvisionedThroughput.ReadCapacityUnits)
+	assert.Len(in.GlobalSecondaryIndexUpdates, 2)
+	gsiTP := in.GlobalSecondaryIndexUpdates[0].Update.ProvisionedThroughput
+	assert.Equal(int64(5), *gsiTP.ReadCapacityUnits)
+	assert.Equal(int64(6), *gsiTP.WriteCapacityUnits)
+	gsiTP = in.GlobalSecondaryIndexUpdates[1].Update.ProvisionedThroughput
+	assert.Equal(int64(7), *gsiTP.ReadCapacityUnits)
+	assert.Equal(int64(8), *gsiTP.WriteCapacityUnits)
+}
+
+func TestPlanGSIThroughput(t *testing.T) {
+	assert := assert.New(t)
+
+	current := &SDK.TableDescription{
+		BillingModeSummary: &SDK.BillingModeSummary{BillingMode: pointers.String(BillingModeProvisioned)},
+		GlobalSecondaryIndexes: []*SDK.GlobalSecondaryIndexDescription{{
+			IndexName: pointers.String("gsi-index"),
+			KeySchema: NewKeySchema(NewHashKeyElement("group"), NewRangeKeyElement("time")),
+			ProvisionedThroughput: &SDK.ProvisionedThroughputDescription{
+				ReadCapacityUnits:  pointers.Long64(3),
+				WriteCapacityUnits: pointers.Long64(3),
+			},
+		}},
+	}
+
+	// the throughput is not compared when the design does not specify it.
+	for _, tp := range []*SDK.ProvisionedThroughput{nil, newProvisionedThroughput(0, 0)} {
+		design := getEnsureTableDesign()
+		design.SetThroughput(5, 6)
+		design.GSI[0].ProvisionedThroughput = tp
+		assert.Empty(planGSI("ensure_table", current, design))
+	}
+
+	design := getEnsureTableDesign()
+	design.SetThroughput(5, 6)
+	design.GSI[0].ProvisionedThroughput = newProvisionedThroughput(4, 4)
+	changes := planGSI("ensure_table", current, design)
+	assert.Equal([]string{SchemaChangeUpdateGSIThroughput}, changeTypes(changes))
+	assert.Equal(int64(4), *changes[0].updateTable.GlobalSecondaryIndexUpdates[0].Update.ProvisionedThroughput.ReadCapacityUnits)
+
+	// the new GSI without throughput is created with the throughput of the table.
+	design = getEnsureTableDesign()
+	design.SetThroughput(5, 6)
+	design.GSI[0].ProvisionedThroughput = nil
+	assert.NoError(design.AddGSIS("own-index", "name"))
+	design.GSI[1].ProvisionedThroughput = nil
+	changes = planGSI("ensure_table", current, design)
+	assert.Equal([]string{SchemaChangeCreateGSI}, changeTypes(changes))
+	gsiTP := changes[0].updateTable.GlobalSecondaryIndexUpdates[0].Create.ProvisionedThroughput
+	assert.Equal(int64(5), *gsiTP.ReadCapacityUnits)
+	assert.Equal(int64(6), *gsiTP.WriteCapacityUnits)
+}
@@ -0,0 +1,192 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strings"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// max number of statements in a BatchExecuteStatement request.
+const batchExecuteStatementMax = 25
+
+// ExecuteStatement executes PartiQL statement by `ExecuteStatement` operation.
+// The params are bound to `?` placeholders in order, and all of the pages are fetched by NextToken.
+// The table prefix is not applied to the statement, so the table name must include the prefix.
+//     e.g. svc.ExecuteStatement(fmt.Sprintf(`SELECT * FROM "%s" WHERE id = ?`, svc.TableNameWithPrefix("users")), 1)
+func (svc *DynamoDB) ExecuteStatement(stmt string, params ...interface{}) (*QueryResult, error) {
+	in := &SDK.ExecuteStatementInput{
+		Statement:              pointers.String(stmt),
+		Parameters:             newStatementParameters(params),
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+	}
+
+	isWrite := isWriteStatement(stmt)
+	res := &QueryResult{}
+	for {
+		out, err := svc.client.ExecuteStatement(in)
+		if err != nil {
+			svc.recordError("ExecuteStatement", statementTableName(stmt), isWrite, err)
+			svc.Errorf("error on `ExecuteStatement` operation; statement=%s; error=%s;", stmt, err.Error())
+			return res, err
+		}
+
+		for _, cc := range svc.recordCapacity("ExecuteStatement", isWrite, out.ConsumedCapacity) {
+			res.ConsumedCapacity.TableName = cc.TableName
+			res.ConsumedCapacity.CapacityUnits += cc.CapacityUnits
+		}
+		res.Items = append(res.Items, out.Items...)
+		res.Count += int64(len(out.Items))
+		if out.NextToken == nil || *out.NextToken == "" {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	res.ScannedCount = res.Count
+	return res, nil
+}
+
+// BatchStatement is a PartiQL statement for `BatchExecuteStatement` operation.
+type BatchStatement struct {
+	Statement      string
+	Parameters     []interface{}
+	ConsistentRead bool
+}
+
+func (s BatchStatement) toRequest() *SDK.BatchStatementRequest {
+	r := &SDK.BatchStatementRequest{
+		Statement:  pointers.String(s.Statement),
+		Parameters: newStatementParameters(s.Parameters),
+	}
+	if s.ConsistentRead {
+		r.ConsistentRead = pointers.Bool(true)
+	}
+	return r
+}
+
+// BatchStatementResult is a result of a statement in `BatchExecuteStatement` operation.
+type BatchStatementResult struct {
+	TableName    string
+	Item         map[string]*SDK.AttributeValue
+	ErrorCode    string
+	ErrorMessage string
+}
+
+func newBatchStatementResult(r *SDK.BatchStatementResponse) BatchStatementResult {
+	res := BatchStatementResult{
+		TableName: stringValue(r.TableName),
+		Item:      r.Item,
+	}
+	if r.Error != nil {
+		res.ErrorCode = stringValue(r.Error.Code)
+		res.ErrorMessage = stringValue(r.Error.Message)
+	}
+	return res
+}
+
+// HasError checks if the statement is failed or not.
+func (r BatchStatementResult) HasError() bool {
+	return r.ErrorCode != ""
+}
+
+// ToMap converts the item to map.
+func (r BatchStatementResult) ToMap() map[string]interface{} {
+	return UnmarshalAttributeValue(r.Item)
+}
+
+// BatchExecuteStatement executes PartiQL statements by `BatchExecuteStatement` operation.
+// The statements are split into chunks of 25, and the results are returned in the same order as the statements.
+// A failed statement does not stop the others, and it is reported in the result and the error.
+// As well as ExecuteStatement, the table names in the statements must include the prefix.
+func (svc *DynamoDB) BatchExecuteStatement(statements []BatchStatement) ([]BatchStatementResult, error) {
+	results := make([]BatchStatementResult, 0, len(statements))
+	if len(statements) == 0 {
+		return results, nil
+	}
+
+	// all of the statements in a batch must be either reads or writes.
+	isWrite := isWriteStatement(statements[0].Statement)
+	errList := newErrors()
+	for start := 0; start < len(statements); start += batchExecuteStatementMax {
+		end := start + batchExecuteStatementMax
+		if end > len(statements) {
+			end = len(statements)
+		}
+
+		reqs := make([]*SDK.BatchStatementRequest, 0, end-start)
+		for _, s := range statements[start:end] {
+			reqs = append(reqs, s.toRequest())
+		}
+		out, err := svc.client.BatchExecuteStatement(&SDK.BatchExecuteStatementInput{
+			Statements:             reqs,
+			ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+		})
+		if err != nil {
+			svc.recordError("BatchExecuteStatement", statementTableName(statements[start].Statement), isWrite, err)
+			svc.Errorf("error on `BatchExecuteStatement` operation; error=%s;", err.Error())
+			return results, err
+		}
+
+		svc.recordCapacity("BatchExecuteStatement", isWrite, out.ConsumedCapacity...)
+		for i, r := range out.Responses {
+			res := newBatchStatementResult(r)
+			if res.HasError() {
+				errList.Add(fmt.Errorf("statement[%d] is failed; code=%s; message=%s;", start+i, res.ErrorCode, res.ErrorMessage))
+			}
+			results = append(results, res)
+		}
+	}
+
+	if errList.HasError() {
+		svc.Errorf("errors on `BatchExecuteStatement` operation; errors=[%s];", errList.Error())
+		return results, errList
+	}
+	return results, nil
+}
+
+// newStatementParameters converts the params into the AttributeValues for the placeholders.
+func newStatementParameters(params []interface{}) []*SDK.AttributeValue {
+	if len(params) == 0 {
+		return nil
+	}
+
+	list := make([]*SDK.AttributeValue, len(params))
+	for i, v := range params {
+		list[i] = createAttributeValue(v)
+	}
+	return list
+}
+
+// isWriteStatement checks if the statement is INSERT, UPDATE or DELETE.
+func isWriteStatement(stmt string) bool {
+	fields := strings.Fields(stmt)
+	if len(fields) == 0 {
+		return false
+	}
+	return !strings.EqualFold(fields[0], "SELECT")
+}
+
+// statementTableName returns the table name after FROM, INTO or UPDATE keyword.
+// It returns empty string when the table name is not found.
+func statementTableName(stmt string) string {
+	fields := strings.Fields(stmt)
+	for i := 0; i < len(fields)-1; i++ {
+		switch strings.ToUpper(fields[i]) {
+		case "FROM", "INTO", "UPDATE":
+		default:
+			continue
+		}
+
+		name := fields[i+1]
+		if strings.HasPrefix(name, `"`) {
+			if end := strings.Index(name[1:], `"`); end >= 0 {
+				return name[1 : end+1]
+			}
+			return strings.Trim(name, `"`)
+		}
+		return strings.SplitN(name, ".", 2)[0]
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteStatement(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+
+	_, err := svc.ExecuteStatement(`INSERT INTO "mem_table" VALUE {'id': ?, 'time': ?, 'group': ?, 'user-name': 'alice'}`, 1, 1, "a")
+	assert.NoError(err)
+	_, err = svc.ExecuteStatement(`INSERT INTO "mem_table" VALUE {'id': 1, 'time': 2, 'group': 'b', 'score': 10}`)
+	assert.NoError(err)
+	_, err = svc.ExecuteStatement(`INSERT INTO "mem_table" VALUE {'id': 1, 'time': 2}`)
+	assert.Error(err, "duplicate primary key")
+
+	res, err := svc.ExecuteStatement(`SELECT * FROM "mem_table" WHERE id = ?`, 1)
+	assert.NoError(err)
+	assert.EqualValues(2, res.Count)
+	list := res.ToSliceMap()
+	assert.Equal("alice", list[0]["user-name"])
+	assert.Equal("b", list[1]["group"])
+
+	res, err = svc.ExecuteStatement(`SELECT "time", "user-name" FROM "mem_table" WHERE id = ? AND "time" >= ?`, 1, 2)
+	assert.NoError(err)
+	assert.Equal([]map[string]interface{}{{"time": 2}}, res.ToSliceMap())
+
+	_, err = svc.ExecuteStatement(`UPDATE "mem_table" SET score = score + ? SET "user-name" = ? WHERE id = ? AND "time" = ?`, 5, "bob", 1, 2)
+	assert.NoError(err)
+	_, err = svc.ExecuteStatement(`UPDATE "mem_table" REMOVE "user-name" WHERE id = 1 AND "time" = 1`)
+	assert.NoError(err)
+	_, err = svc.ExecuteStatement(`UPDATE "mem_table" SET score = 1 WHERE id = 1 AND "time" = 3`)
+	assert.Error(err, "item does not exist")
+	_, err = svc.ExecuteStatement(`UPDATE "mem_table" SET score = 1 WHERE id = 1`)
+	assert.Error(err, "key is missing")
+
+	var items []struct {
+		ID       int    `dynamodb:"id"`
+		Time     int    `dynamodb:"time"`
+		Score    int    `dynamodb:"score"`
+		UserName string `dynamodb:"user-name"`
+	}
+	res, err = svc.ExecuteStatement(`SELECT * FROM "mem_table" WHERE id = 1`)
+	assert.NoError(err)
+	assert.NoError(res.Unmarshal(&items))
+	assert.Len(items, 2)
+	assert.Equal("", items[0].UserName)
+	assert.Equal(15, items[1].Score)
+	assert.Equal("bob", items[1].UserName)
+
+	_, err = svc.ExecuteStatement(`DELETE FROM "mem_table" WHERE id = ? AND "time" = ? AND score > ?`, 1, 2, 100)
+	assert.Error(err, "condition is not matched")
+	_, err = svc.ExecuteStatement(`DELETE FROM "mem_table" WHERE id = ? AND "time" = ?`, 1, 2)
+	assert.NoError(err)
+	res, err = svc.ExecuteStatement(`SELECT * FROM "mem_table"`)
+	assert.NoError(err)
+	assert.EqualValues(1, res.Count)
+
+	_, err = svc.ExecuteStatement(`SELECT * FROM "mem_table" WHERE id = ?`)
+	assert.Error(err, "parameter is missing")
+	_, err = svc.ExecuteStatement(`SELECT * FROM "unknown_table"`)
+	assert.Error(err)
+}
+
+func TestExecuteStatementPagination(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+
+	total := memStatementPageSize*2 + 10
+	for i := 1; i <= total; i++ {
+		putMemoryTestItem(tbl, i, 1, "", "")
+	}
+	assert.NoError(tbl.BatchPut())
+
+	res, err := svc.ExecuteStatement(`SELECT id FROM "mem_table"`)
+	assert.NoError(err)
+	assert.EqualValues(total, res.Count)
+	assert.Len(res.Items, total)
+
+	list := res.ToSliceMap()
+	for i, item := range list {
+		assert.Equal(i+1, item["id"])
+	}
+}
+
+func TestBatchExecuteStatement(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	assert.NoError(tbl.Put())
+
+	results, err := svc.BatchExecuteStatement([]BatchStatement{
+		{Statement: `INSERT INTO "mem_table" VALUE {'id': ?, 'time': ?}`, Parameters: []interface{}{2, 1}},
+		{Statement: `INSERT INTO "mem_table" VALUE {'id': ?, 'time': ?}`, Parameters: []interface{}{1, 1}},
+		{Statement: `UPDATE "mem_table" SET "group" = ? WHERE id = ? AND "time" = ?`, Parameters: []interface{}{"z", 1, 1}},
+		{Statement: `SELECT * FROM "mem_table" WHERE id = ? AND "time" = ?`, Parameters: []interface{}{1, 1}, ConsistentRead: true},
+		{Statement: `SELECT * FROM "mem_table" WHERE id = ?`, Parameters: []interface{}{1}},
+	})
+	assert.Error(err)
+	assert.Len(results, 5)
+
+	assert.False(results[0].HasError())
+	assert.Equal("mem_table", results[0].TableName)
+	assert.True(results[1].HasError())
+	assert.Equal("DuplicateItem", results[1].ErrorCode)
+	assert.False(results[2].HasError())
+	assert.False(results[3].HasError())
+	assert.Equal("z", results[3].ToMap()["group"])
+	assert.True(results[4].HasError(), "key is required on batch select")
+	assert.Equal("ValidationError", results[4].ErrorCode)
+
+	statements := make([]BatchStatement, batchExecuteStatementMax+5)
+	for i := range statements {
+		statements[i] = BatchStatement{
+			Statement:  `SELECT * FROM "mem_table" WHERE id = ? AND "time" = ?`,
+			Parameters: []interface{}{2, 1},
+		}
+	}
+	results, err = svc.BatchExecuteStatement(statements)
+	assert.NoError(err)
+	assert.Len(results, len(statements))
+	for _, r := range results {
+		assert.Equal(2, r.ToMap()["id"])
+	}
+}
+
+func TestExecuteStatementCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := NewInMemory()
+	svc.SetPrefix("test_")
+	design := NewTableDesignWithHashKeyN("users", "id")
+	assert.NoError(svc.CreateTable(design))
+
+	var events []CapacityEvent
+	svc.SetCapacityHook(func(e CapacityEvent) {
+		events = append(events, e)
+	})
+
+	name := svc.TableNameWithPrefix("users")
+	assert.Equal("test_users", name)
+	_, err := svc.ExecuteStatement(`INSERT INTO "`+name+`" VALUE {'id': ?}`, 1)
+	assert.NoError(err)
+	_, err = svc.ExecuteStatement(`INSERT INTO "`+name+`" VALUE {'id': ?}`, 2)
+	assert.NoError(err)
+	res, err := svc.ExecuteStatement(`SELECT * FROM "` + name + `"`)
+	assert.NoError(err)
+	assert.EqualValues(2, res.Count)
+	assert.Equal("test_users", res.ConsumedCapacity.TableName)
+	assert.Equal(1.0, res.ConsumedCapacity.CapacityUnits)
+
+	_, err = svc.BatchExecuteStatement([]BatchStatement{
+		{Statement: `SELECT * FROM "` + name + `" WHERE id = ?`, Parameters: []interface{}{1}, ConsistentRead: true},
+		{Statement: `SELECT * FROM "` + name + `" WHERE id = ?`, Parameters: []interface{}{2}},
+	})
+	assert.NoError(err)
+
+	usage := svc.GetCapacityMeter().GetTableUsage("test_users")
+	assert.Equal(int64(4), usage.Requests)
+	assert.Equal(2.0, usage.WriteCapacityUnits)
+	assert.Equal(2.5, usage.ReadCapacityUnits)
+
+	assert.Len(events, 4)
+	assert.Equal("ExecuteStatement", events[0].Operation)
+	assert.True(events[0].IsWrite)
+	assert.False(events[2].IsWrite)
+	assert.Equal("BatchExecuteStatement", events[3].Operation)
+	assert.False(events[3].IsWrite)
+}
+
+func TestStatementTableName(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		stmt     string
+		expected string
+	}{
+		{`SELECT * FROM "users" WHERE id = ?`, "users"},
+		{`select id from users.gsi-index`, "users"},
+		{`SELECT * FROM "users"."gsi-index"`, "users"},
+		{`INSERT INTO "users" VALUE {'id': 1}`, "users"},
+		{`UPDATE "users" SET name = ? WHERE id = ?`, "users"},
+		{`DELETE FROM users WHERE id = ?`, "users"},
+		{`EXISTS`, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.expected, statementTableName(tt.stmt), tt.stmt)
+	}
+
+	assert.False(isWriteStatement(` select * from "users"`))
+	assert.True(isWriteStatement(`DELETE FROM "users" WHERE id = 1`))
+}
@@ -0,0 +1,465 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// operation types in transaction.
+const (
+	TransactOperationPut            = "Put"
+	TransactOperationUpdate         = "Update"
+	TransactOperationDelete         = "Delete"
+	TransactOperationConditionCheck = "ConditionCheck"
+	TransactOperationGet            = "Get"
+
+	cancellationReasonNone                   = "None"
+	cancellationReasonConditionalCheckFailed = "ConditionalCheckFailed"
+)
+
+// Transact returns initialized *Transaction for `TransactWriteItems` operation.
+func (svc *DynamoDB) Transact() *Transaction {
+	return &Transaction{
+		service: svc,
+	}
+}
+
+// Transaction is a builder for `TransactWriteItems` operation.
+// Operations can be added across multiple tables and are committed atomically.
+type Transaction struct {
+	service    *DynamoDB
+	operations []*transactWriteOperation
+
+	clientRequestToken string
+	errList            []error
+}
+
+// SetClientRequestToken sets idempotency token.
+func (tx *Transaction) SetClientRequestToken(token string) {
+	tx.clientRequestToken = token
+}
+
+// Put adds Put operation of the item.
+// Conditions of the PutItem are converted into ConditionExpression.
+// When the version attribute is set, the condition of the version is added and the version of the item is incremented.
+func (tx *Transaction) Put(tbl *Table, item *PutItem) {
+	in := &SDK.PutItemInput{
+		TableName: pointers.String(tbl.nameWithPrefix),
+		Item:      item.data,
+	}
+	if err := tbl.validatePutItem(in); err != nil {
+		tx.errList = append(tx.errList, err)
+		return
+	}
+
+	isVersioned := tbl.applyVersion(item)
+	conds := item.toItemConditions("pc")
+	tx.operations = append(tx.operations, &transactWriteOperation{
+		operation:   TransactOperationPut,
+		table:       tbl,
+		item:        item.data,
+		conditions:  &conds,
+		isVersioned: isVersioned,
+	})
+}
+
+// Update adds Update operation.
+// The condition of UpdateItem.ExpectVersion is checked as well as UpdateItem.Exec.
+func (tx *Transaction) Update(u *UpdateItem) {
+	if !u.HasAction() {
+		tx.errList = append(tx.errList, fmt.Errorf("action is missing in Update; table=%s;", u.table.nameWithPrefix))
+		return
+	}
+
+	tx.operations = append(tx.operations, &transactWriteOperation{
+		operation:   TransactOperationUpdate,
+		table:       u.table,
+		key:         u.key,
+		update:      u,
+		isVersioned: u.isVersioned,
+	})
+}
+
+// Delete adds Delete operation and returns *ItemConditions to add conditions on the operation.
+func (tx *Transaction) Delete(tbl *Table, hashValue interface{}, rangeValue ...interface{}) *ItemConditions {
+	return tx.addKeyOperation(TransactOperationDelete, "dc", tbl, hashValue, rangeValue...)
+}
+
+// ConditionCheck adds ConditionCheck operation and returns *ItemConditions to add conditions on the operation.
+func (tx *Transaction) ConditionCheck(tbl *Table, hashValue interface{}, rangeValue ...interface{}) *ItemConditions {
+	return tx.addKeyOperation(TransactOperationConditionCheck, "cc", tbl, hashValue, rangeValue...)
+}
+
+func (tx *Transaction) addKeyOperation(operation, prefix string, tbl *Table, hashValue interface{}, rangeValue ...interface{}) *ItemConditions {
+	conds := newItemConditions(prefix)
+	tx.operations = append(tx.operations, &transactWriteOperation{
+		operation:  operation,
+		table:      tbl,
+		key:        tbl.design.keyAttributeValue(hashValue, rangeValue...),
+		conditions: &conds,
+	})
+	return &conds
+}
+
+// Len returns count of the operations.
+func (tx *Transaction) Len() int {
+	return len(tx.operations)
+}
+
+// TransactWriteItemsInput creates *SDK.TransactWriteItemsInput from the transaction.
+func (tx *Transaction) TransactWriteItemsInput() *SDK.TransactWriteItemsInput {
+	items := make([]*SDK.TransactWriteItem, len(tx.operations))
+	for i, op := range tx.operations {
+		items[i] = op.toSDK()
+	}
+
+	in := &SDK.TransactWriteItemsInput{
+		TransactItems: items,
+	}
+	if tx.clientRequestToken != "" {
+		in.ClientRequestToken = pointers.String(tx.clientRequestToken)
+	}
+	return in
+}
+
+// Commit executes `TransactWriteItems` operation.
+// When the transaction is canceled, *TransactionCanceledError is returned.
+// It returns ErrVersionConflict when all of the failed operations are caused by the version conflict.
+// After the commit, the written items are removed from read cache of the tables.
+func (tx *Transaction) Commit() error {
+	svc := tx.service
+	if len(tx.errList) != 0 {
+		errList := newErrors()
+		for _, err := range tx.errList {
+			errList.Add(err)
+		}
+		svc.Errorf("error on `Commit`; errors=[%s];", errList.Error())
+		return errList
+	}
+	if tx.Len() == 0 {
+		err := fmt.Errorf("operation is missing, you must specify at least one operation")
+		svc.Errorf("error on `Commit`; error=%s;", err.Error())
+		return err
+	}
+
+	_, err := svc.client.TransactWriteItems(tx.TransactWriteItemsInput())
+	if err != nil {
+		err = newTransactionCanceledError(err, tx.operationInfo())
+		if tx.isVersionConflict(err) {
+			err = ErrVersionConflict
+		}
+		svc.Errorf("error on `TransactWriteItems` operation; error=%s;", err.Error())
+		return err
+	}
+	tx.invalidateCache()
+	return nil
+}
+
+// invalidateCache removes the written items from read cache of the tables.
+func (tx *Transaction) invalidateCache() {
+	for _, op := range tx.operations {
+		switch op.operation {
+		case TransactOperationPut:
+			op.table.invalidateCache(op.item)
+		case TransactOperationUpdate, TransactOperationDelete:
+			op.table.invalidateCache(op.key)
+		}
+	}
+}
+
+// isVersionConflict checks if all of the failed operations are caused by the condition of the version.
+func (tx *Transaction) isVersionConflict(err error) bool {
+	txErr, ok := err.(*TransactionCanceledError)
+	if !ok {
+		return false
+	}
+
+	failed := txErr.FailedReasons()
+	if len(failed) == 0 {
+		return false
+	}
+	for _, r := range failed {
+		if r.Code != cancellationReasonConditionalCheckFailed || r.Index >= len(tx.operations) || !tx.operations[r.Index].isVersioned {
+			return false
+		}
+	}
+	return true
+}
+
+func (tx *Transaction) operationInfo() []transactOperationInfo {
+	list := make([]transactOperationInfo, len(tx.operations))
+	for i, op := range tx.operations {
+		list[i] = transactOperationInfo{
+			operation: op.operation,
+			tableName: op.table.nameWithPrefix,
+		}
+	}
+	return list
+}
+
+// transactWriteOperation contains an operation of `TransactWriteItems`.
+type transactWriteOperation struct {
+	operation  string
+	table      *Table
+	key        map[string]*SDK.AttributeValue
+	item       map[string]*SDK.AttributeValue
+	update     *UpdateItem
+	conditions *ItemConditions
+	// true when the operation has the condition of the version.
+	isVersioned bool
+}
+
+func (op *transactWriteOperation) toSDK() *SDK.TransactWriteItem {
+	tableName := pointers.String(op.table.nameWithPrefix)
+	switch op.operation {
+	case TransactOperationPut:
+		return &SDK.TransactWriteItem{
+			Put: &SDK.Put{
+				TableName:                 tableName,
+				Item:                      op.item,
+				ConditionExpression:       op.conditions.FormatCondition(),
+				ExpressionAttributeNames:  op.conditions.FormatNames(),
+				ExpressionAttributeValues: op.conditions.FormatValues(),
+			},
+		}
+	case TransactOperationUpdate:
+		in := op.update.UpdateItemInput()
+		return &SDK.TransactWriteItem{
+			Update: &SDK.Update{
+				TableName:                 tableName,
+				Key:                       in.Key,
+				UpdateExpression:          in.UpdateExpression,
+				ConditionExpression:       in.ConditionExpression,
+				ExpressionAttributeNames:  in.ExpressionAttributeNames,
+				ExpressionAttributeValues: in.ExpressionAttributeValues,
+			},
+		}
+	case TransactOperationDelete:
+		return &SDK.TransactWriteItem{
+			Delete: &SDK.Delete{
+				TableName:                 tableName,
+				Key:                       op.key,
+				ConditionExpression:       op.conditions.FormatCondition(),
+				ExpressionAttributeNames:  op.conditions.FormatNames(),
+				ExpressionAttributeValues: op.conditions.FormatValues(),
+			},
+		}
+	case TransactOperationConditionCheck:
+		return &SDK.TransactWriteItem{
+			ConditionCheck: &SDK.ConditionCheck{
+				TableName:                 tableName,
+				Key:                       op.key,
+				ConditionExpression:       op.conditions.FormatCondition(),
+				ExpressionAttributeNames:  op.conditions.FormatNames(),
+				ExpressionAttributeValues: op.conditions.FormatValues(),
+			},
+		}
+	}
+	return nil
+}
+
+// TransactGet returns initialized *TransactGet for `TransactGetItems` operation.
+func (svc *DynamoDB) TransactGet() *TransactGet {
+	return &TransactGet{
+		service: svc,
+	}
+}
+
+// TransactGet is a builder for `TransactGetItems` operation.
+type TransactGet struct {
+	service *DynamoDB
+	items   []*SDK.TransactGetItem
+	tables  []string
+}
+
+// Get adds Get operation for the item of given keys.
+func (tx *TransactGet) Get(tbl *Table, hashValue interface{}, rangeValue ...interface{}) {
+	tx.items = append(tx.items, &SDK.TransactGetItem{
+		Get: &SDK.Get{
+			TableName: pointers.String(tbl.nameWithPrefix),
+			Key:       tbl.design.keyAttributeValue(hashValue, rangeValue...),
+		},
+	})
+	tx.tables = append(tx.tables, tbl.nameWithPrefix)
+}
+
+// Len returns count of the operations.
+func (tx *TransactGet) Len() int {
+	return len(tx.items)
+}
+
+// Exec executes `TransactGetItems` operation.
+// The result items are in the same order as added operations.
+func (tx *TransactGet) Exec() (*TransactGetResult, error) {
+	svc := tx.service
+	if tx.Len() == 0 {
+		err := fmt.Errorf("operation is missing, you must specify at least one operation")
+		svc.Errorf("error on `TransactGet`; error=%s;", err.Error())
+		return nil, err
+	}
+
+	out, err := svc.client.TransactGetItems(&SDK.TransactGetItemsInput{
+		TransactItems: tx.items,
+	})
+	if err != nil {
+		info := make([]transactOperationInfo, len(tx.tables))
+		for i, name := range tx.tables {
+			info[i] = transactOperationInfo{
+				operation: TransactOperationGet,
+				tableName: name,
+			}
+		}
+		err = newTransactionCanceledError(err, info)
+		svc.Errorf("error on `TransactGetItems` operation; error=%s;", err.Error())
+		return nil, err
+	}
+
+	items := make([]map[string]*SDK.AttributeValue, len(out.Responses))
+	for i, res := range out.Responses {
+		if res != nil {
+			items[i] = res.Item
+		}
+	}
+	return &TransactGetResult{
+		Items: items,
+	}, nil
+}
+
+// TransactGetResult is struct for result of `TransactGetItems` operation.
+type TransactGetResult struct {
+	Items []map[string]*SDK.AttributeValue
+}
+
+// ToSliceMap converts result to slice of map.
+// The element is nil when the item does not exist.
+func (r TransactGetResult) ToSliceMap() []map[string]interface{} {
+	m := make([]map[string]interface{}, len(r.Items))
+	for i, item := range r.Items {
+		if len(item) == 0 {
+			continue
+		}
+		m[i] = UnmarshalAttributeValue(item)
+	}
+	return m
+}
+
+// Unmarshal unmarshals given slice pointer sturct from DynamoDB item result to mapping.
+// The struct tag `dynamodb:""` is used to unmarshal.
+func (r TransactGetResult) Unmarshal(v interface{}) error {
+	return r.UnmarshalWithTagName(v, defaultResultTag)
+}
+
+// UnmarshalWithTagName unmarshals given slice pointer sturct and tag name from DynamoDB item result to mapping.
+func (r TransactGetResult) UnmarshalWithTagName(v interface{}, structTag string) error {
+	decoder := dynamodbattribute.NewDecoder()
+	decoder.TagKey = structTag
+
+	items := make([]*SDK.AttributeValue, len(r.Items))
+	for i, m := range r.Items {
+		items[i] = &SDK.AttributeValue{M: m}
+	}
+	return decoder.Decode(&SDK.AttributeValue{L: items}, v)
+}
+
+// transactOperationInfo contains information of an operation to map cancellation reasons.
+type transactOperationInfo struct {
+	operation string
+	tableName string
+}
+
+// TransactionCanceledError is an error for canceled transaction.
+type TransactionCanceledError struct {
+	Err     error
+	Reasons []TransactionCancelReason
+}
+
+// TransactionCancelReason contains the reason of cancellation for each operation.
+type TransactionCancelReason struct {
+	Index     int
+	Operation string
+	TableName string
+	Code      string
+	Message   string
+	Item      map[string]interface{}
+}
+
+// IsFailed checks if the operation caused the cancellation or not.
+func (r TransactionCancelReason) IsFailed() bool {
+	return r.Code != "" && r.Code != cancellationReasonNone
+}
+
+// newTransactionCanceledError maps cancellation reasons to the operations.
+// It returns the original error when the error is not `TransactionCanceledException`.
+func newTransactionCanceledError(err error, info []transactOperationInfo) error {
+	canceled, ok := err.(*SDK.TransactionCanceledException)
+	if !ok {
+		aerr, ok := err.(awserr.Error)
+		if !ok || aerr.Code() != SDK.ErrCodeTransactionCanceledException {
+			return err
+		}
+		return &TransactionCanceledError{Err: err}
+	}
+
+	reasons := make([]TransactionCancelReason, len(canceled.CancellationReasons))
+	for i, r := range canceled.CancellationReasons {
+		reason := TransactionCancelReason{
+			Index: i,
+		}
+		if i < len(info) {
+			reason.Operation = info[i].operation
+			reason.TableName = info[i].tableName
+		}
+		if r != nil {
+			if r.Code != nil {
+				reason.Code = *r.Code
+			}
+			if r.Message != nil {
+				reason.Message = *r.Message
+			}
+			if len(r.Item) != 0 {
+				reason.Item = UnmarshalAttributeValue(r.Item)
+			}
+		}
+		reasons[i] = reason
+	}
+	return &TransactionCanceledError{
+		Err:     err,
+		Reasons: reasons,
+	}
+}
+
+// Error returns error message with failed reasons.
+func (e *TransactionCanceledError) Error() string {
+	failed := e.FailedReasons()
+	if len(failed) == 0 {
+		return e.Err.Error()
+	}
+
+	list := make([]string, len(failed))
+	for i, r := range failed {
+		list[i] = fmt.Sprintf("index=%d; operation=%s; table=%s; code=%s;", r.Index, r.Operation, r.TableName, r.Code)
+	}
+	return fmt.Sprintf("%s; reasons=[%s]", e.Err.Error(), strings.Join(list, " "))
+}
+
+// Unwrap returns the original error.
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.Err
+}
+
+// FailedReasons returns the reasons of operations which caused the cancellation.
+func (e *TransactionCanceledError) FailedReasons() []TransactionCancelReason {
+	var list []TransactionCancelReason
+	for _, r := range e.Reasons {
+		if r.IsFailed() {
+			list = append(list, r)
+		}
+	}
+	return list
+}
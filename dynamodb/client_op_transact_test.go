@@ -0,0 +1,165 @@
+package dynamodb
+
+import (
+	"errors"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func TestTransactWriteItemsInput(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := getTestClient(t)
+	tbl := getUpdateTestTable()
+	tbl.service = svc
+	tbl.nameWithPrefix = "testprefix_foo_table"
+
+	tx := svc.Transact()
+	tx.SetClientRequestToken("token")
+
+	item := NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 2)
+	item.AddConditionNotExist("id")
+	tx.Put(tbl, item)
+
+	u := tbl.Update(1, 3)
+	u.Decrement("balance", 10)
+	u.AddConditionGE("balance", 10)
+	tx.Update(u)
+
+	del := tx.Delete(tbl, 1, 4)
+	del.AddConditionEQ("status", "done")
+	check := tx.ConditionCheck(tbl, 1, 5)
+	check.AddConditionExist("id")
+	assert.Equal(4, tx.Len())
+
+	in := tx.TransactWriteItemsInput()
+	assert.Equal("token", *in.ClientRequestToken)
+	assert.Len(in.TransactItems, 4)
+
+	put := in.TransactItems[0].Put
+	assert.Equal("testprefix_foo_table", *put.TableName)
+	assert.Equal("attribute_not_exists(#pc0)", *put.ConditionExpression)
+	assert.Equal("id", *put.ExpressionAttributeNames["#pc0"])
+	assert.Nil(put.ExpressionAttributeValues)
+
+	update := in.TransactItems[1].Update
+	assert.Equal("SET #u0 = if_not_exists(#u0, :u0) - :u1", *update.UpdateExpression)
+	assert.Equal("#uc0 >= :uc0", *update.ConditionExpression)
+	assert.Equal("balance", *update.ExpressionAttributeNames["#uc0"])
+	assert.Equal("10", *update.ExpressionAttributeValues[":uc0"].N)
+	assert.Equal("3", *update.Key["time"].N)
+
+	deleteOp := in.TransactItems[2].Delete
+	assert.Equal("#dc0 = :dc0", *deleteOp.ConditionExpression)
+	assert.Equal("done", *deleteOp.ExpressionAttributeValues[":dc0"].S)
+	assert.Equal("4", *deleteOp.Key["time"].N)
+
+	cc := in.TransactItems[3].ConditionCheck
+	assert.Equal("attribute_exists(#cc0)", *cc.ConditionExpression)
+
+	// validation error
+	tx = svc.Transact()
+	item = NewPutItem()
+	item.AddAttribute("id", 1)
+	tx.Put(tbl, item)
+	tx.Update(tbl.Update(1, 1))
+	assert.Equal(0, tx.Len())
+	assert.Error(tx.Commit())
+
+	assert.Error(svc.Transact().Commit(), "empty transaction should be error")
+}
+
+func TestNewTransactionCanceledError(t *testing.T) {
+	assert := assert.New(t)
+
+	info := []transactOperationInfo{
+		{operation: TransactOperationPut, tableName: "table_a"},
+		{operation: TransactOperationUpdate, tableName: "table_b"},
+	}
+
+	err := errors.New("other error")
+	assert.Equal(err, newTransactionCanceledError(err, info))
+
+	canceled := &SDK.TransactionCanceledException{
+		Message_: pointers.String("Transaction cancelled"),
+		CancellationReasons: []*SDK.CancellationReason{
+			{Code: pointers.String("None")},
+			{
+				Code:    pointers.String("ConditionalCheckFailed"),
+				Message: pointers.String("The conditional request failed"),
+				Item: map[string]*SDK.AttributeValue{
+					"id": {N: pointers.String("1")},
+				},
+			},
+		},
+	}
+	result := newTransactionCanceledError(canceled, info)
+	txErr, ok := result.(*TransactionCanceledError)
+	assert.True(ok)
+	assert.Equal(canceled, txErr.Unwrap())
+	assert.Len(txErr.Reasons, 2)
+	assert.False(txErr.Reasons[0].IsFailed())
+	assert.Equal(TransactOperationPut, txErr.Reasons[0].Operation)
+
+	failed := txErr.FailedReasons()
+	assert.Len(failed, 1)
+	assert.Equal(1, failed[0].Index)
+	assert.Equal(TransactOperationUpdate, failed[0].Operation)
+	assert.Equal("table_b", failed[0].TableName)
+	assert.Equal("ConditionalCheckFailed", failed[0].Code)
+	assert.Equal(1, failed[0].Item["id"])
+	assert.Contains(txErr.Error(), "index=1; operation=Update; table=table_b; code=ConditionalCheckFailed;")
+}
+
+func TestTransactionCommit(t *testing.T) {
+	assert := assert.New(t)
+	resetTestTable(t)
+	tbl := getTestTable(t)
+	svc := tbl.service
+	putTestTable(tbl, 100, 1)
+
+	tx := svc.Transact()
+	u := tbl.Update(100, 1)
+	u.Increment("count", 1)
+	tx.Update(u)
+	item := NewPutItem()
+	item.AddAttribute("id", 100)
+	item.AddAttribute("time", 2)
+	tx.Put(tbl, item)
+	assert.NoError(tx.Commit())
+
+	get := svc.TransactGet()
+	get.Get(tbl, 100, 1)
+	get.Get(tbl, 100, 2)
+	get.Get(tbl, 100, 3)
+	res, err := get.Exec()
+	assert.NoError(err)
+	list := res.ToSliceMap()
+	assert.Len(list, 3)
+	assert.Equal(1, list[0]["count"])
+	assert.Equal(2, list[1]["time"])
+	assert.Nil(list[2])
+
+	tx = svc.Transact()
+	item = NewPutItem()
+	item.AddAttribute("id", 100)
+	item.AddAttribute("time", 3)
+	tx.Put(tbl, item)
+	check := tx.ConditionCheck(tbl, 100, 1)
+	check.AddConditionEQ("count", 99)
+	err = tx.Commit()
+	assert.Error(err)
+	txErr, ok := err.(*TransactionCanceledError)
+	if assert.True(ok) {
+		failed := txErr.FailedReasons()
+		if assert.Len(failed, 1) {
+			assert.Equal(TransactOperationConditionCheck, failed[0].Operation)
+		}
+	}
+}
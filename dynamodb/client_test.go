@@ -1,6 +1,7 @@
 package dynamodb
 
 import (
+	"os"
 	"testing"
 	"time"
 
@@ -23,7 +24,16 @@ func getTestConfig() config.Config {
 	}
 }
 
+// testMemoryClient is shared by the test clients like DynamoDB Local with `-sharedDb`.
+var testMemoryClient = NewMemoryClient()
+
+// getTestClient returns *DynamoDB with in-memory backend.
+// When `DYNAMODB_TEST_LOCAL` is set, DynamoDB Local on docker-compose is used instead.
 func getTestClient(t *testing.T) *DynamoDB {
+	if os.Getenv("DYNAMODB_TEST_LOCAL") == "" {
+		return NewWithClient(testMemoryClient)
+	}
+
 	svc, err := New(getTestConfig())
 	if err != nil {
 		t.Errorf("error on create client; error=%s;", err.Error())
@@ -37,9 +47,9 @@ func TestNew(t *testing.T) {
 
 	svc, err := New(getTestConfig())
 	assert.NoError(err)
-	assert.NotNil(svc.client)
-	assert.Equal("dynamodb", svc.client.ServiceName)
-	assert.Equal(defaultEndpoint, svc.client.Endpoint)
+	assert.NotNil(svc.GetClient())
+	assert.Equal("dynamodb", svc.GetClient().ServiceName)
+	assert.Equal(defaultEndpoint, svc.GetClient().Endpoint)
 
 	region := "us-west-1"
 	svc, err = New(config.Config{
@@ -47,7 +57,7 @@ func TestNew(t *testing.T) {
 	})
 	assert.NoError(err)
 	expectedEndpoint := "https://dynamodb." + region + ".amazonaws.com"
-	assert.Equal(expectedEndpoint, svc.client.Endpoint)
+	assert.Equal(expectedEndpoint, svc.GetClient().Endpoint)
 }
 
 func TestSetLogger(t *testing.T) {
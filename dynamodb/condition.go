@@ -1,12 +1,9 @@
 package dynamodb
 
 import (
-	"fmt"
-	"strings"
+	"sort"
 
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
-
-	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
 )
 
 // ConditionList contains multiple condition.
@@ -14,6 +11,7 @@ type ConditionList struct {
 	keyAttributes map[string]string
 	conditions    map[string]*Condition
 	filters       map[string]*Condition
+	filterExprs   []Expr
 
 	index        string
 	limit        int64
@@ -38,7 +36,7 @@ func (c *ConditionList) HasCondition() bool {
 
 // HasFilter checks if at least one filter is set or not.
 func (c *ConditionList) HasFilter() bool {
-	return len(c.filters) != 0
+	return len(c.filters) != 0 || len(c.filterExprs) != 0
 }
 
 // HasIndex checks if the index is set or not.
@@ -52,6 +50,7 @@ func (c *ConditionList) HasLimit() bool {
 }
 
 // SetLimit sets limit number.
+// It is the max number of items evaluated on each request, so QueryIter and ScanIter use it as the page size.
 func (c *ConditionList) SetLimit(i int64) {
 	c.limit = i
 }
@@ -106,6 +105,11 @@ func (c *ConditionList) AndBETWEEN(key string, from, to interface{}) {
 	c.setCondition(conditionBETWEEN, key, from, to)
 }
 
+// AndBeginsWith adds begins_with condition.
+func (c *ConditionList) AndBeginsWith(key string, prefix interface{}) {
+	c.setCondition(conditionBeginsWith, key, prefix)
+}
+
 func (c *ConditionList) setCondition(condition, key string, val interface{}, subVal ...interface{}) {
 	if _, ok := c.conditions[key]; ok {
 		return
@@ -154,91 +158,84 @@ func (c *ConditionList) setFilter(condition, key string, val interface{}, subVal
 	}
 
 	cond := newCondition(condition, key, val)
-	cond.isFilter = true
 	if len(subVal) == 1 {
 		cond.SubValue = subVal[0]
 	}
 	c.filters[key] = cond
 }
 
+// Filter adds filter expression.
+// All of the filters are combined with AND.
+func (c *ConditionList) Filter(e Expr) {
+	if e.IsEmpty() {
+		return
+	}
+	c.filterExprs = append(c.filterExprs, e)
+}
+
 // FormatCondition returns string pointer for KeyConditionExpression.
 func (c *ConditionList) FormatCondition() *string {
-	return c.formatCondition(c.conditions)
+	condition, _, _ := c.build()
+	return condition
 }
 
-// FormatFilter returns string pointer for KeyConditionExpression.
+// FormatFilter returns string pointer for FilterExpression.
 func (c *ConditionList) FormatFilter() *string {
-	return c.formatCondition(c.filters)
-}
-
-// formatCondition returns string pointer for ConditionExpression and FilterExpression.
-func (c *ConditionList) formatCondition(conditions map[string]*Condition) *string {
-	max := len(conditions)
-	if max == 0 {
-		return nil
-	}
-
-	i := 1
-	expression := make([]string, 0, max)
-	for key, cond := range conditions {
-		exp := cond.expression(key)
-
-		// add space unless final expression
-		if i < max {
-			exp = exp + " " + cond.operator()
-		}
-		expression = append(expression, exp)
-		i++
-	}
-	e := strings.Join(expression, " ")
-	return &e
+	_, filter, _ := c.build()
+	return filter
 }
 
 // FormatValues returns the parameter for ExpressionAttributeValues.
 func (c *ConditionList) FormatValues() map[string]*SDK.AttributeValue {
-	attrs := c.keyAttributes
-	m := make(map[string]*SDK.AttributeValue)
-
-	for k, cond := range c.getMergedConditions() {
-		typ, ok := attrs[k]
-		if !ok {
-			continue
-		}
-		key := cond.valueName()
-		m[key] = newAttributeValue(typ, cond.Value)
-		// BETWEEN
-		if cond.SubValue != nil {
-			sub := cond.subValueName()
-			m[sub] = newAttributeValue(typ, cond.SubValue)
-		}
-	}
-	return m
+	_, _, b := c.build()
+	return b.Values()
 }
 
 // FormatNames returns the parameter for ExpressionAttributeNames.
 func (c *ConditionList) FormatNames() map[string]*string {
-	attrs := c.keyAttributes
-	m := make(map[string]*string)
+	_, _, b := c.build()
+	return b.Names()
+}
 
-	for _, cond := range c.getMergedConditions() {
-		if _, ok := attrs[cond.Key]; !ok {
-			continue
-		}
-		m[cond.keyName()] = pointers.String(cond.Key)
+// build returns KeyConditionExpression, FilterExpression and the builder which contains names and values.
+func (c *ConditionList) build() (condition, filter *string, b *exprBuilder) {
+	b = newExprBuilder("c", c.keyAttributes)
+	if e := conditionsToExpr(c.conditions); !e.IsEmpty() {
+		s := e.build(b)
+		condition = &s
 	}
-	return m
-}
 
-func (c *ConditionList) getMergedConditions() map[string]*Condition {
-	list := make(map[string]*Condition)
-	for k, v := range c.filters {
-		list[k] = v
+	filters := append([]Expr{conditionsToExpr(c.filters)}, c.filterExprs...)
+	if e := ExprAnd(filters...); !e.IsEmpty() {
+		s := e.build(b)
+		filter = &s
 	}
+	return condition, filter, b
+}
 
-	for k, v := range c.conditions {
-		list[k] = v
+// conditionsToExpr combines conditions into an expression in order of the key name.
+func conditionsToExpr(conditions map[string]*Condition) Expr {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
 	}
-	return list
+	sort.Strings(keys)
+
+	var result Expr
+	var prev *Condition
+	for _, key := range keys {
+		cond := conditions[key]
+		switch {
+		case prev == nil:
+			result = cond.toExpr()
+		case prev.OR:
+			result = ExprOr(result, cond.toExpr())
+		default:
+			result = ExprAnd(result, cond.toExpr())
+		}
+		prev = cond
+	}
+	return result
 }
 
 // AddKeyAttribute adds to attribute to keyAttributes.
@@ -253,7 +250,6 @@ type Condition struct {
 	Value     interface{}
 	SubValue  interface{}
 	OR        bool
-	isFilter  bool
 }
 
 // newCondition returns initialized *Condition.
@@ -265,45 +261,14 @@ func newCondition(condition, key string, val interface{}) *Condition {
 	}
 }
 
-func (c *Condition) expression(key string) (expression string) {
-	switch {
-	case c.Condition == conditionBETWEEN:
-		return fmt.Sprintf("%s BETWEEN %s AND %s", c.keyName(), c.valueName(), c.subValueName())
-	default:
-		return fmt.Sprintf("%s %s %s", c.keyName(), c.Condition, c.valueName())
-	}
-}
-
-func (c *Condition) operator() string {
-	if c.OR {
-		return conditionOR
-	}
-	return conditionAND
-}
-
-func (c *Condition) keyName() string {
-	switch {
-	case c.isFilter:
-		return fmt.Sprintf("#f_%s", c.Key)
-	default:
-		return fmt.Sprintf("#c_%s", c.Key)
-	}
-}
-
-func (c *Condition) valueName() string {
-	switch {
-	case c.isFilter:
-		return fmt.Sprintf(":f_%s", c.Key)
-	default:
-		return fmt.Sprintf(":c_%s", c.Key)
-	}
-}
-
-func (c *Condition) subValueName() string {
-	switch {
-	case c.isFilter:
-		return fmt.Sprintf(":fs_%s", c.Key)
+// toExpr converts the condition to Expr.
+func (c *Condition) toExpr() Expr {
+	switch c.Condition {
+	case conditionBETWEEN:
+		return ExprBetween(c.Key, c.Value, c.SubValue)
+	case conditionBeginsWith:
+		return ExprBeginsWith(c.Key, c.Value)
 	default:
-		return fmt.Sprintf(":cs_%s", c.Key)
+		return newCompareExpr(c.Condition, c.Key, c.Value)
 	}
 }
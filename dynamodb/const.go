@@ -19,14 +19,16 @@ const (
 	AttributeTypeNumberSet = "NS"
 	AttributeTypeBinarySet = "BS"
 
-	conditionEQ      = "="
-	conditionLE      = "<="
-	conditionLT      = "<"
-	conditionGE      = ">="
-	conditionGT      = ">"
-	conditionBETWEEN = SDK.ComparisonOperatorBetween
-	conditionOR      = SDK.ConditionalOperatorOr
-	conditionAND     = SDK.ConditionalOperatorAnd
+	conditionEQ         = "="
+	conditionNE         = "<>"
+	conditionLE         = "<="
+	conditionLT         = "<"
+	conditionGE         = ">="
+	conditionGT         = ">"
+	conditionBETWEEN    = SDK.ComparisonOperatorBetween
+	conditionBeginsWith = SDK.ComparisonOperatorBeginsWith
+	conditionOR         = SDK.ConditionalOperatorOr
+	conditionAND        = SDK.ConditionalOperatorAnd
 
 	// comparison operators
 	ComparisonOperatorEQ = SDK.ComparisonOperatorEq
@@ -41,4 +43,31 @@ const (
 	KeyTypeRange = SDK.KeyTypeRange
 
 	SelectCount = SDK.SelectCount
+
+	// billing mode of the table.
+	BillingModeProvisioned   = SDK.BillingModeProvisioned
+	BillingModePayPerRequest = SDK.BillingModePayPerRequest
+
+	// view types of DynamoDB Streams.
+	StreamViewTypeKeysOnly        = SDK.StreamViewTypeKeysOnly
+	StreamViewTypeNewImage        = SDK.StreamViewTypeNewImage
+	StreamViewTypeOldImage        = SDK.StreamViewTypeOldImage
+	StreamViewTypeNewAndOldImages = SDK.StreamViewTypeNewAndOldImages
+
+	// status of Time to Live.
+	TTLStatusEnabled   = SDK.TimeToLiveStatusEnabled
+	TTLStatusEnabling  = SDK.TimeToLiveStatusEnabling
+	TTLStatusDisabled  = SDK.TimeToLiveStatusDisabled
+	TTLStatusDisabling = SDK.TimeToLiveStatusDisabling
+
+	// status of point-in-time recovery.
+	PITRStatusEnabled  = SDK.PointInTimeRecoveryStatusEnabled
+	PITRStatusDisabled = SDK.PointInTimeRecoveryStatusDisabled
+
+	// return values for UpdateItem.
+	ReturnValueNone       = SDK.ReturnValueNone
+	ReturnValueAllOld     = SDK.ReturnValueAllOld
+	ReturnValueUpdatedOld = SDK.ReturnValueUpdatedOld
+	ReturnValueAllNew     = SDK.ReturnValueAllNew
+	ReturnValueUpdatedNew = SDK.ReturnValueUpdatedNew
 )
@@ -0,0 +1,498 @@
+package dynamodb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const defaultTypeAttribute = "_type"
+
+// EntityRegistry maps Go types to the key templates for single-table design.
+// The key template contains attribute names of the struct in braces. e.g.) `USER#{id}`, `ORDER#{date}#{id}`
+// The entity name is saved into the type attribute to decode the items of the item collection.
+type EntityRegistry struct {
+	table         *Table
+	typeAttribute string
+
+	entities map[string]*Entity
+	types    map[reflect.Type]*Entity
+}
+
+// NewEntityRegistry returns initialized *EntityRegistry.
+// The hash key and the range key of the table must be String type.
+func NewEntityRegistry(tbl *Table) *EntityRegistry {
+	return &EntityRegistry{
+		table:         tbl,
+		typeAttribute: defaultTypeAttribute,
+		entities:      make(map[string]*Entity),
+		types:         make(map[reflect.Type]*Entity),
+	}
+}
+
+// SetTypeAttribute sets the attribute name of the type discriminator. (default: `_type`)
+func (r *EntityRegistry) SetTypeAttribute(name string) {
+	r.typeAttribute = name
+}
+
+// Register registers the struct type with the entity name and the key templates.
+// rangeTemplate must be empty when the table does not have the range key.
+func (r *EntityRegistry) Register(name string, v interface{}, hashTemplate, rangeTemplate string) error {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch {
+	case typ == nil || typ.Kind() != reflect.Struct:
+		return fmt.Errorf("entity must be a struct; name=%s; type=%T;", name, v)
+	case r.entities[name] != nil:
+		return fmt.Errorf("entity name is already registered; name=%s;", name)
+	case r.types[typ] != nil:
+		return fmt.Errorf("entity type is already registered; name=%s; type=%s;", name, typ.String())
+	case (rangeTemplate == "") != (r.table.design.GetRangeKeyName() == ""):
+		return fmt.Errorf("range key template does not match the table schema; name=%s; table=%s;", name, r.table.nameWithPrefix)
+	}
+
+	design := r.table.design
+	attrs := design.GetKeyAttributes()
+	for _, key := range []string{design.GetHashKeyName(), design.GetRangeKeyName()} {
+		if key != "" && attrs[key] != "S" {
+			return fmt.Errorf("key attribute must be String type; name=%s; table=%s; key=%s;", name, r.table.nameWithPrefix, key)
+		}
+	}
+
+	e := &Entity{
+		name:   name,
+		typ:    typ,
+		fields: structAttributeKinds(typ, defaultResultTag),
+	}
+	var err error
+	if e.hashKey, err = parseKeyTemplate(hashTemplate, e.fields); err != nil {
+		return err
+	}
+	if rangeTemplate != "" {
+		if e.rangeKey, err = parseKeyTemplate(rangeTemplate, e.fields); err != nil {
+			return err
+		}
+	}
+
+	r.entities[name] = e
+	r.types[typ] = e
+	return nil
+}
+
+// GetEntity returns the registered entity of the value.
+func (r *EntityRegistry) GetEntity(v interface{}) (*Entity, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	e, ok := r.types[typ]
+	if !ok {
+		return nil, fmt.Errorf("entity is not registered; type=%T;", v)
+	}
+	return e, nil
+}
+
+// Put puts the struct with the keys built from the templates and the type attribute.
+func (r *EntityRegistry) Put(v interface{}) error {
+	e, err := r.GetEntity(v)
+	if err != nil {
+		return err
+	}
+
+	item, err := NewPutItemFromStruct(v)
+	if err != nil {
+		return err
+	}
+	hashValue, rangeValue, err := e.buildKeys(item.data)
+	if err != nil {
+		return err
+	}
+
+	design := r.table.design
+	item.data[design.GetHashKeyName()] = &SDK.AttributeValue{S: pointers.String(hashValue)}
+	if e.rangeKey != nil {
+		item.data[design.GetRangeKeyName()] = &SDK.AttributeValue{S: pointers.String(rangeValue)}
+	}
+	item.data[r.typeAttribute] = &SDK.AttributeValue{S: pointers.String(e.name)}
+
+	if err := r.table.putItem(item); err != nil {
+		return err
+	}
+	setStructVersion(v, item)
+	return nil
+}
+
+// Get gets the item into the struct pointer by the keys built from the fields of the struct.
+// It returns false when the item does not exist.
+func (r *EntityRegistry) Get(v interface{}) (bool, error) {
+	key, err := r.Key(v)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := r.table.getItem(&SDK.GetItemInput{
+		TableName:              pointers.String(r.table.nameWithPrefix),
+		Key:                    key,
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+	})
+	switch {
+	case err != nil:
+		return false, err
+	case out.Item == nil:
+		return false, nil
+	}
+
+	e, _ := r.GetEntity(v)
+	return true, r.decodeInto(e, out.Item, v)
+}
+
+// Delete deletes the item by the keys built from the fields of the struct.
+func (r *EntityRegistry) Delete(v interface{}) error {
+	key, err := r.Key(v)
+	if err != nil {
+		return err
+	}
+
+	return r.table.deleteItem(&SDK.DeleteItemInput{
+		TableName: pointers.String(r.table.nameWithPrefix),
+		Key:       key,
+	})
+}
+
+// Key returns the primary key attributes built from the fields of the struct.
+func (r *EntityRegistry) Key(v interface{}) (map[string]*SDK.AttributeValue, error) {
+	e, err := r.GetEntity(v)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := NewPutItemFromStruct(v)
+	if err != nil {
+		return nil, err
+	}
+	hashValue, rangeValue, err := e.buildKeys(item.data)
+	if err != nil {
+		return nil, err
+	}
+
+	design := r.table.design
+	key := map[string]*SDK.AttributeValue{
+		design.GetHashKeyName(): {S: pointers.String(hashValue)},
+	}
+	if e.rangeKey != nil {
+		key[design.GetRangeKeyName()] = &SDK.AttributeValue{S: pointers.String(rangeValue)}
+	}
+	return key, nil
+}
+
+// NewCollectionCondition returns the condition to query the item collection of the hash key.
+// The range key is filtered by begins_with when rangePrefix is not empty.
+func (r *EntityRegistry) NewCollectionCondition(hashValue, rangePrefix string) *ConditionList {
+	cond := r.table.NewConditionList()
+	cond.AndEQ(r.table.design.GetHashKeyName(), hashValue)
+	if rangePrefix != "" {
+		cond.AndBeginsWith(r.table.design.GetRangeKeyName(), rangePrefix)
+	}
+	return cond
+}
+
+// QueryCollection queries all of the items in the item collection and decodes them into the registered types.
+func (r *EntityRegistry) QueryCollection(hashValue, rangePrefix string) (*Collection, error) {
+	return r.QueryCollectionWithCondition(r.NewCollectionCondition(hashValue, rangePrefix))
+}
+
+// QueryCollectionWithCondition queries all of the items by the condition and decodes them into the registered types.
+func (r *EntityRegistry) QueryCollectionWithCondition(cond *ConditionList) (*Collection, error) {
+	it := r.table.QueryIter(cond)
+	var items []map[string]*SDK.AttributeValue
+	for it.Next() {
+		items = append(items, it.RawItem())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return r.Decode(items)
+}
+
+// Decode decodes the items into the registered types by the type attribute.
+// The items of unknown types are kept as map in the collection.
+func (r *EntityRegistry) Decode(items []map[string]*SDK.AttributeValue) (*Collection, error) {
+	c := &Collection{}
+	for _, item := range items {
+		typeValue := item[r.typeAttribute]
+		var e *Entity
+		if typeValue != nil && typeValue.S != nil {
+			e = r.entities[*typeValue.S]
+		}
+		if e == nil {
+			c.Unknown = append(c.Unknown, UnmarshalAttributeValue(item))
+			continue
+		}
+
+		v := reflect.New(e.typ).Interface()
+		if err := r.decodeInto(e, item, v); err != nil {
+			return nil, err
+		}
+		c.Items = append(c.Items, v)
+	}
+	return c, nil
+}
+
+// decodeInto decodes the item into the struct pointer.
+// The fields which are not saved as attributes are parsed from the keys.
+// It returns an error when the key attribute is missing in the item.
+func (r *EntityRegistry) decodeInto(e *Entity, item map[string]*SDK.AttributeValue, v interface{}) error {
+	design := r.table.design
+	hashValue, err := r.keyValue(e, item, design.GetHashKeyName())
+	if err != nil {
+		return err
+	}
+	rangeValue := ""
+	if e.rangeKey != nil {
+		if rangeValue, err = r.keyValue(e, item, design.GetRangeKeyName()); err != nil {
+			return err
+		}
+	}
+
+	values, err := e.ParseKeys(hashValue, rangeValue)
+	if err != nil {
+		return err
+	}
+	m := make(map[string]*SDK.AttributeValue, len(item)+len(values))
+	for k, av := range item {
+		m[k] = av
+	}
+	for name, value := range values {
+		if _, ok := m[name]; ok {
+			continue
+		}
+		m[name] = e.attributeValue(name, value)
+	}
+
+	decoder := dynamodbattribute.NewDecoder()
+	decoder.TagKey = defaultResultTag
+	return decoder.Decode(&SDK.AttributeValue{M: m}, v)
+}
+
+// keyValue returns the string value of the key attribute in the item.
+func (r *EntityRegistry) keyValue(e *Entity, item map[string]*SDK.AttributeValue, key string) (string, error) {
+	av, ok := item[key]
+	if !ok || av == nil || av.S == nil {
+		return "", fmt.Errorf("key attribute is missing in the item; name=%s; table=%s; key=%s;", e.name, r.table.nameWithPrefix, key)
+	}
+	return *av.S, nil
+}
+
+// Collection is decoded items of the item collection.
+type Collection struct {
+	// pointers of the registered structs.
+	Items []interface{}
+	// items of unknown types.
+	Unknown []map[string]interface{}
+}
+
+// Unmarshal sets the items of the element type into the slice pointer.
+//
+//	e.g. err = c.Unmarshal(&[]*User{})
+func (c *Collection) Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("value must be a pointer of slice; type=%T;", v)
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	for _, item := range c.Items {
+		iv := reflect.ValueOf(item)
+		switch {
+		case iv.Type() == elemType:
+			slice = reflect.Append(slice, iv)
+		case iv.Elem().Type() == elemType:
+			slice = reflect.Append(slice, iv.Elem())
+		}
+	}
+	rv.Elem().Set(slice)
+	return nil
+}
+
+// Entity is a registered type of EntityRegistry.
+type Entity struct {
+	name     string
+	typ      reflect.Type
+	fields   map[string]reflect.Kind
+	hashKey  *keyTemplate
+	rangeKey *keyTemplate
+}
+
+// GetName returns the entity name.
+func (e *Entity) GetName() string {
+	return e.name
+}
+
+// ParseKeys parses the key values and returns the attributes in the key templates.
+func (e *Entity) ParseKeys(hashValue, rangeValue string) (map[string]string, error) {
+	values := make(map[string]string)
+	if err := e.hashKey.parse(hashValue, values); err != nil {
+		return nil, err
+	}
+	if e.rangeKey != nil {
+		if err := e.rangeKey.parse(rangeValue, values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// buildKeys builds the key values from the attributes.
+func (e *Entity) buildKeys(item map[string]*SDK.AttributeValue) (hashValue, rangeValue string, err error) {
+	if hashValue, err = e.hashKey.build(item); err != nil {
+		return "", "", err
+	}
+	if e.rangeKey != nil {
+		if rangeValue, err = e.rangeKey.build(item); err != nil {
+			return "", "", err
+		}
+	}
+	return hashValue, rangeValue, nil
+}
+
+// attributeValue converts the parsed key value into the attribute value of the field type.
+func (e *Entity) attributeValue(name, value string) *SDK.AttributeValue {
+	switch e.fields[name] {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &SDK.AttributeValue{N: pointers.String(value)}
+	}
+	return &SDK.AttributeValue{S: pointers.String(value)}
+}
+
+// keyTemplate is a template of the key value.
+type keyTemplate struct {
+	raw      string
+	segments []keySegment
+}
+
+// keySegment is a literal string or an attribute name of the key template.
+type keySegment struct {
+	literal string
+	name    string
+}
+
+func parseKeyTemplate(raw string, fields map[string]reflect.Kind) (*keyTemplate, error) {
+	t := &keyTemplate{raw: raw}
+	rest := raw
+	for rest != "" {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			t.segments = append(t.segments, keySegment{literal: rest})
+			break
+		}
+		if start > 0 {
+			t.segments = append(t.segments, keySegment{literal: rest[:start]})
+		}
+
+		end := strings.Index(rest, "}")
+		if end < start {
+			return nil, fmt.Errorf("invalid key template; template=%s;", raw)
+		}
+		name := rest[start+1 : end]
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("unknown attribute in key template; template=%s; attribute=%s;", raw, name)
+		}
+		if n := len(t.segments); n != 0 && t.segments[n-1].name != "" {
+			return nil, fmt.Errorf("attributes must be separated by literal in key template; template=%s;", raw)
+		}
+		t.segments = append(t.segments, keySegment{name: name})
+		rest = rest[end+1:]
+	}
+	if len(t.segments) == 0 {
+		return nil, fmt.Errorf("empty key template")
+	}
+	return t, nil
+}
+
+// build builds the key value from the attributes.
+func (t *keyTemplate) build(item map[string]*SDK.AttributeValue) (string, error) {
+	var b strings.Builder
+	for _, s := range t.segments {
+		if s.name == "" {
+			b.WriteString(s.literal)
+			continue
+		}
+
+		v := item[s.name]
+		switch {
+		case v == nil:
+			return "", fmt.Errorf("missing attribute for key template; template=%s; attribute=%s;", t.raw, s.name)
+		case v.S != nil:
+			b.WriteString(*v.S)
+		case v.N != nil:
+			b.WriteString(*v.N)
+		default:
+			return "", fmt.Errorf("attribute for key template must be String or Number; template=%s; attribute=%s;", t.raw, s.name)
+		}
+	}
+	return b.String(), nil
+}
+
+// parse parses the key value and sets the attributes into values.
+func (t *keyTemplate) parse(key string, values map[string]string) error {
+	pos := 0
+	for i, s := range t.segments {
+		if s.name == "" {
+			if !strings.HasPrefix(key[pos:], s.literal) {
+				return fmt.Errorf("key does not match the template; template=%s; key=%s;", t.raw, key)
+			}
+			pos += len(s.literal)
+			continue
+		}
+
+		if i == len(t.segments)-1 {
+			values[s.name] = key[pos:]
+			return nil
+		}
+		n := strings.Index(key[pos:], t.segments[i+1].literal)
+		if n < 0 {
+			return fmt.Errorf("key does not match the template; template=%s; key=%s;", t.raw, key)
+		}
+		values[s.name] = key[pos : pos+n]
+		pos += n
+	}
+	if pos != len(key) {
+		return fmt.Errorf("key does not match the template; template=%s; key=%s;", t.raw, key)
+	}
+	return nil
+}
+
+// structAttributeKinds returns the attribute names and the kinds of the struct fields.
+func structAttributeKinds(typ reflect.Type, structTag string) map[string]reflect.Kind {
+	fields := make(map[string]reflect.Kind)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get(structTag), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		kind := f.Type.Kind()
+		if kind == reflect.Ptr {
+			kind = f.Type.Elem().Kind()
+		}
+		fields[name] = kind
+	}
+	return fields
+}
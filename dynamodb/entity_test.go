@@ -0,0 +1,176 @@
+package dynamodb
+
+import (
+	"reflect"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+type testEntityUser struct {
+	ID   string `dynamodb:"id"`
+	Name string `dynamodb:"name"`
+}
+
+type testEntityOrder struct {
+	UserID string `dynamodb:"user_id"`
+	Date   string `dynamodb:"date"`
+	ID     int    `dynamodb:"id"`
+	Price  int    `dynamodb:"price"`
+}
+
+func getTestEntityRegistry(t *testing.T) *EntityRegistry {
+	svc := NewInMemory()
+	design := NewTableDesignWithHashKeyS("entity_table", "pk")
+	design.AddRangeKeyS("sk")
+	if err := svc.CreateTable(design); err != nil {
+		t.Fatalf("error on CreateTable; error=%s;", err.Error())
+	}
+	tbl, err := svc.GetTable("entity_table")
+	if err != nil {
+		t.Fatalf("error on GetTable; error=%s;", err.Error())
+	}
+
+	r := NewEntityRegistry(tbl)
+	if err := r.Register("user", testEntityUser{}, "USER#{id}", "PROFILE"); err != nil {
+		t.Fatalf("error on Register; error=%s;", err.Error())
+	}
+	if err := r.Register("order", &testEntityOrder{}, "USER#{user_id}", "ORDER#{date}#{id}"); err != nil {
+		t.Fatalf("error on Register; error=%s;", err.Error())
+	}
+	return r
+}
+
+func TestEntityRegistry(t *testing.T) {
+	assert := assert.New(t)
+	r := getTestEntityRegistry(t)
+
+	assert.NoError(r.Put(&testEntityUser{ID: "u1", Name: "foo"}))
+	assert.NoError(r.Put(&testEntityOrder{UserID: "u1", Date: "2020-01-02", ID: 2, Price: 200}))
+	assert.NoError(r.Put(&testEntityOrder{UserID: "u1", Date: "2020-01-01", ID: 1, Price: 100}))
+	assert.NoError(r.Put(&testEntityOrder{UserID: "u2", Date: "2020-01-01", ID: 3, Price: 300}))
+
+	key, err := r.Key(&testEntityOrder{UserID: "u1", Date: "2020-01-01", ID: 1})
+	assert.NoError(err)
+	assert.Equal("USER#u1", *key["pk"].S)
+	assert.Equal("ORDER#2020-01-01#1", *key["sk"].S)
+
+	user := &testEntityUser{ID: "u1"}
+	ok, err := r.Get(user)
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal("foo", user.Name)
+
+	ok, err = r.Get(&testEntityUser{ID: "u3"})
+	assert.NoError(err)
+	assert.False(ok)
+
+	c, err := r.QueryCollection("USER#u1", "")
+	assert.NoError(err)
+	assert.Len(c.Items, 3)
+	assert.Empty(c.Unknown)
+
+	var orders []*testEntityOrder
+	assert.NoError(c.Unmarshal(&orders))
+	assert.Len(orders, 2)
+	assert.Equal(100, orders[0].Price, "should be sorted by the range key")
+	assert.Equal(200, orders[1].Price)
+
+	var users []testEntityUser
+	assert.NoError(c.Unmarshal(&users))
+	assert.Equal([]testEntityUser{{ID: "u1", Name: "foo"}}, users)
+
+	c, err = r.QueryCollection("USER#u1", "ORDER#2020-01-02")
+	assert.NoError(err)
+	assert.Len(c.Items, 1)
+
+	assert.NoError(r.Delete(&testEntityOrder{UserID: "u1", Date: "2020-01-02", ID: 2}))
+	c, err = r.QueryCollection("USER#u1", "ORDER#")
+	assert.NoError(err)
+	assert.Len(c.Items, 1)
+
+	assert.Error(r.Put(&testStructItem{}), "unregistered type")
+}
+
+func TestEntityRegistryVersion(t *testing.T) {
+	assert := assert.New(t)
+	r := getTestEntityRegistry(t)
+	r.table.GetDesign().SetVersionAttribute("version")
+
+	type versionedUser struct {
+		ID      string `dynamodb:"id"`
+		Name    string `dynamodb:"name"`
+		Version int    `dynamodb:"version"`
+	}
+	assert.NoError(r.Register("versioned_user", versionedUser{}, "VUSER#{id}", "PROFILE"))
+
+	u := &versionedUser{ID: "u1", Name: "foo"}
+	assert.NoError(r.Put(u))
+	assert.Equal(1, u.Version)
+	u.Name = "bar"
+	assert.NoError(r.Put(u))
+	assert.Equal(2, u.Version)
+
+	stale := &versionedUser{ID: "u1", Name: "baz", Version: 1}
+	assert.Equal(ErrVersionConflict, r.Put(stale))
+}
+
+func TestEntityRegistryDecodeMissingKey(t *testing.T) {
+	assert := assert.New(t)
+	r := getTestEntityRegistry(t)
+	e := r.entities["order"]
+
+	tests := []map[string]*SDK.AttributeValue{
+		{"sk": {S: pointers.String("ORDER#2020-01-01#1")}},
+		{"pk": {S: pointers.String("USER#u1")}},
+		{"pk": {N: pointers.String("1")}, "sk": {S: pointers.String("ORDER#2020-01-01#1")}},
+	}
+	for _, item := range tests {
+		err := r.decodeInto(e, item, &testEntityOrder{})
+		if assert.Error(err) {
+			assert.Contains(err.Error(), "key attribute is missing")
+		}
+	}
+
+	v := &testEntityOrder{}
+	assert.NoError(r.decodeInto(e, map[string]*SDK.AttributeValue{
+		"pk": {S: pointers.String("USER#u1")},
+		"sk": {S: pointers.String("ORDER#2020-01-01#1")},
+	}, v))
+	assert.Equal(testEntityOrder{UserID: "u1", Date: "2020-01-01", ID: 1}, *v)
+}
+
+func TestEntityRegistryRegister(t *testing.T) {
+	assert := assert.New(t)
+	r := getTestEntityRegistry(t)
+
+	assert.Error(r.Register("user", struct{ ID string }{}, "USER#{ID}", "X"), "duplicate name")
+	assert.Error(r.Register("user2", testEntityUser{}, "USER#{id}", "X"), "duplicate type")
+	assert.Error(r.Register("item", testStructItem{}, "ITEM#{unknown}", "X"), "unknown attribute")
+	assert.Error(r.Register("item", testStructItem{}, "ITEM#{id}", ""), "missing range template")
+	assert.Error(r.Register("item", map[string]interface{}{}, "ITEM", "X"), "not struct")
+
+	tbl := getMemoryTestTable(t)
+	assert.Error(NewEntityRegistry(tbl).Register("user", testEntityUser{}, "USER#{id}", "PROFILE"), "number key")
+}
+
+func TestKeyTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	fields := map[string]reflect.Kind{"date": reflect.String, "id": reflect.Int}
+	tmpl, err := parseKeyTemplate("ORDER#{date}#{id}", fields)
+	assert.NoError(err)
+
+	values := make(map[string]string)
+	assert.NoError(tmpl.parse("ORDER#2020-01-01#10", values))
+	assert.Equal(map[string]string{"date": "2020-01-01", "id": "10"}, values)
+	assert.Error(tmpl.parse("USER#2020-01-01#10", values))
+
+	_, err = parseKeyTemplate("{date}{id}", fields)
+	assert.Error(err, "attributes must be separated")
+	_, err = parseKeyTemplate("ORDER#{date", fields)
+	assert.Error(err)
+}
@@ -0,0 +1,380 @@
+package dynamodb
+
+import (
+	"fmt"
+	"strings"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// kinds of Expr.
+const (
+	exprKindCompare  = "compare"
+	exprKindBetween  = "between"
+	exprKindIn       = "in"
+	exprKindFunction = "function"
+	exprKindSize     = "size"
+	exprKindAnd      = "and"
+	exprKindOr       = "or"
+	exprKindNot      = "not"
+)
+
+// functions of condition expression.
+const (
+	exprFuncAttributeExists    = "attribute_exists"
+	exprFuncAttributeNotExists = "attribute_not_exists"
+	exprFuncAttributeType      = "attribute_type"
+	exprFuncBeginsWith         = "begins_with"
+	exprFuncContains           = "contains"
+)
+
+// Expr is a composable expression for KeyConditionExpression, FilterExpression and ConditionExpression.
+// The attribute path supports nested attributes. (e.g. `a.b[0].c`)
+type Expr struct {
+	kind     string
+	operator string
+	path     string
+	values   []interface{}
+	children []Expr
+}
+
+// ExprEQ creates `path = value` expression.
+func ExprEQ(path string, value interface{}) Expr {
+	return newCompareExpr(conditionEQ, path, value)
+}
+
+// ExprNE creates `path <> value` expression.
+func ExprNE(path string, value interface{}) Expr {
+	return newCompareExpr(conditionNE, path, value)
+}
+
+// ExprLT creates `path < value` expression.
+func ExprLT(path string, value interface{}) Expr {
+	return newCompareExpr(conditionLT, path, value)
+}
+
+// ExprLE creates `path <= value` expression.
+func ExprLE(path string, value interface{}) Expr {
+	return newCompareExpr(conditionLE, path, value)
+}
+
+// ExprGT creates `path > value` expression.
+func ExprGT(path string, value interface{}) Expr {
+	return newCompareExpr(conditionGT, path, value)
+}
+
+// ExprGE creates `path >= value` expression.
+func ExprGE(path string, value interface{}) Expr {
+	return newCompareExpr(conditionGE, path, value)
+}
+
+func newCompareExpr(operator, path string, value interface{}) Expr {
+	return Expr{
+		kind:     exprKindCompare,
+		operator: operator,
+		path:     path,
+		values:   []interface{}{value},
+	}
+}
+
+// ExprBetween creates `path BETWEEN from AND to` expression.
+func ExprBetween(path string, from, to interface{}) Expr {
+	return Expr{
+		kind:   exprKindBetween,
+		path:   path,
+		values: []interface{}{from, to},
+	}
+}
+
+// ExprIn creates `path IN (values...)` expression.
+// Without values, it creates always-false expression, because DynamoDB rejects `path IN ()`.
+func ExprIn(path string, values ...interface{}) Expr {
+	if len(values) == 0 {
+		return ExprAttributeExists(path).And(ExprAttributeNotExists(path))
+	}
+	return Expr{
+		kind:   exprKindIn,
+		path:   path,
+		values: values,
+	}
+}
+
+// ExprBeginsWith creates `begins_with(path, prefix)` expression.
+func ExprBeginsWith(path string, prefix interface{}) Expr {
+	return newFunctionExpr(exprFuncBeginsWith, path, prefix)
+}
+
+// ExprContains creates `contains(path, value)` expression.
+func ExprContains(path string, value interface{}) Expr {
+	return newFunctionExpr(exprFuncContains, path, value)
+}
+
+// ExprAttributeExists creates `attribute_exists(path)` expression.
+func ExprAttributeExists(path string) Expr {
+	return newFunctionExpr(exprFuncAttributeExists, path)
+}
+
+// ExprAttributeNotExists creates `attribute_not_exists(path)` expression.
+func ExprAttributeNotExists(path string) Expr {
+	return newFunctionExpr(exprFuncAttributeNotExists, path)
+}
+
+// ExprAttributeType creates `attribute_type(path, type)` expression.
+// typ is the attribute type. (e.g. `S`, `N`, `SS`, `M`)
+func ExprAttributeType(path, typ string) Expr {
+	return newFunctionExpr(exprFuncAttributeType, path, typ)
+}
+
+func newFunctionExpr(function, path string, values ...interface{}) Expr {
+	return Expr{
+		kind:     exprKindFunction,
+		operator: function,
+		path:     path,
+		values:   values,
+	}
+}
+
+// ExprSize returns SizeExpr to compare `size(path)`.
+func ExprSize(path string) SizeExpr {
+	return SizeExpr{
+		path: path,
+	}
+}
+
+// SizeExpr is a builder of `size(path)` comparison.
+type SizeExpr struct {
+	path string
+}
+
+// EQ creates `size(path) = value` expression.
+func (s SizeExpr) EQ(value interface{}) Expr {
+	return s.compare(conditionEQ, value)
+}
+
+// NE creates `size(path) <> value` expression.
+func (s SizeExpr) NE(value interface{}) Expr {
+	return s.compare(conditionNE, value)
+}
+
+// LT creates `size(path) < value` expression.
+func (s SizeExpr) LT(value interface{}) Expr {
+	return s.compare(conditionLT, value)
+}
+
+// LE creates `size(path) <= value` expression.
+func (s SizeExpr) LE(value interface{}) Expr {
+	return s.compare(conditionLE, value)
+}
+
+// GT creates `size(path) > value` expression.
+func (s SizeExpr) GT(value interface{}) Expr {
+	return s.compare(conditionGT, value)
+}
+
+// GE creates `size(path) >= value` expression.
+func (s SizeExpr) GE(value interface{}) Expr {
+	return s.compare(conditionGE, value)
+}
+
+func (s SizeExpr) compare(operator string, value interface{}) Expr {
+	return Expr{
+		kind:     exprKindSize,
+		operator: operator,
+		path:     s.path,
+		values:   []interface{}{value},
+	}
+}
+
+// ExprAnd combines expressions with AND.
+func ExprAnd(list ...Expr) Expr {
+	return newLogicalExpr(exprKindAnd, list)
+}
+
+// ExprOr combines expressions with OR.
+func ExprOr(list ...Expr) Expr {
+	return newLogicalExpr(exprKindOr, list)
+}
+
+// ExprNot negates the expression.
+func ExprNot(e Expr) Expr {
+	if e.IsEmpty() {
+		return e
+	}
+	return Expr{
+		kind:     exprKindNot,
+		children: []Expr{e},
+	}
+}
+
+func newLogicalExpr(kind string, list []Expr) Expr {
+	children := make([]Expr, 0, len(list))
+	for _, e := range list {
+		switch {
+		case e.IsEmpty():
+			continue
+		case e.kind == kind:
+			// flatten the same logical operator.
+			children = append(children, e.children...)
+		default:
+			children = append(children, e)
+		}
+	}
+
+	switch len(children) {
+	case 0:
+		return Expr{}
+	case 1:
+		return children[0]
+	}
+	return Expr{
+		kind:     kind,
+		children: children,
+	}
+}
+
+// And combines the expression and others with AND.
+func (e Expr) And(list ...Expr) Expr {
+	return ExprAnd(append([]Expr{e}, list...)...)
+}
+
+// Or combines the expression and others with OR.
+func (e Expr) Or(list ...Expr) Expr {
+	return ExprOr(append([]Expr{e}, list...)...)
+}
+
+// IsEmpty checks if the expression is empty or not.
+func (e Expr) IsEmpty() bool {
+	return e.kind == ""
+}
+
+// build returns expression string and registers names and values into the builder.
+func (e Expr) build(b *exprBuilder) string {
+	switch e.kind {
+	case exprKindCompare:
+		return fmt.Sprintf("%s %s %s", b.name(e.path), e.operator, b.value(e.path, e.values[0]))
+	case exprKindBetween:
+		return fmt.Sprintf("%s BETWEEN %s AND %s", b.name(e.path), b.value(e.path, e.values[0]), b.value(e.path, e.values[1]))
+	case exprKindIn:
+		list := make([]string, len(e.values))
+		for i, v := range e.values {
+			list[i] = b.value(e.path, v)
+		}
+		return fmt.Sprintf("%s IN (%s)", b.name(e.path), strings.Join(list, ", "))
+	case exprKindFunction:
+		if len(e.values) == 0 {
+			return fmt.Sprintf("%s(%s)", e.operator, b.name(e.path))
+		}
+		valuePath := e.path
+		if e.operator == exprFuncAttributeType {
+			valuePath = ""
+		}
+		return fmt.Sprintf("%s(%s, %s)", e.operator, b.name(e.path), b.value(valuePath, e.values[0]))
+	case exprKindSize:
+		return fmt.Sprintf("size(%s) %s %s", b.name(e.path), e.operator, b.value("", e.values[0]))
+	case exprKindAnd, exprKindOr:
+		operator := conditionAND
+		if e.kind == exprKindOr {
+			operator = conditionOR
+		}
+		list := make([]string, len(e.children))
+		for i, child := range e.children {
+			list[i] = child.buildChild(b)
+		}
+		return strings.Join(list, " "+operator+" ")
+	case exprKindNot:
+		return fmt.Sprintf("NOT (%s)", e.children[0].build(b))
+	}
+	return ""
+}
+
+// buildChild returns expression string with parentheses for logical expression.
+func (e Expr) buildChild(b *exprBuilder) string {
+	switch e.kind {
+	case exprKindAnd, exprKindOr, exprKindNot:
+		return "(" + e.build(b) + ")"
+	}
+	return e.build(b)
+}
+
+// exprBuilder registers placeholders for ExpressionAttributeNames and ExpressionAttributeValues.
+type exprBuilder struct {
+	prefix        string
+	keyAttributes map[string]string
+
+	nameIndex map[string]string
+	names     map[string]*string
+	values    map[string]*SDK.AttributeValue
+}
+
+func newExprBuilder(prefix string, keyAttributes map[string]string) *exprBuilder {
+	return &exprBuilder{
+		prefix:        prefix,
+		keyAttributes: keyAttributes,
+		nameIndex:     make(map[string]string),
+		names:         make(map[string]*string),
+		values:        make(map[string]*SDK.AttributeValue),
+	}
+}
+
+// name returns placeholder of the attribute path.
+// e.g.) `a.b[0]` => `#c0.#c1[0]`
+func (b *exprBuilder) name(path string) string {
+	segments := strings.Split(path, ".")
+	for i, seg := range segments {
+		attr, index := seg, ""
+		if pos := strings.Index(seg, "["); pos > 0 {
+			attr, index = seg[:pos], seg[pos:]
+		}
+		segments[i] = b.attributeName(attr) + index
+	}
+	return strings.Join(segments, ".")
+}
+
+func (b *exprBuilder) attributeName(attr string) string {
+	if placeholder, ok := b.nameIndex[attr]; ok {
+		return placeholder
+	}
+
+	placeholder := fmt.Sprintf("#%s%d", b.prefix, len(b.nameIndex))
+	b.nameIndex[attr] = placeholder
+	b.names[placeholder] = pointers.String(attr)
+	return placeholder
+}
+
+// value returns placeholder of the value.
+// When the path is a key attribute, the value is converted into the type of the key attribute.
+func (b *exprBuilder) value(path string, v interface{}) string {
+	placeholder := fmt.Sprintf(":%s%d", b.prefix, len(b.values))
+	b.values[placeholder] = b.attributeValue(path, v)
+	return placeholder
+}
+
+func (b *exprBuilder) attributeValue(path string, v interface{}) *SDK.AttributeValue {
+	if av, ok := v.(*SDK.AttributeValue); ok {
+		return av
+	}
+	if typ, ok := b.keyAttributes[path]; ok {
+		if av := newAttributeValue(typ, v); av != nil {
+			return av
+		}
+	}
+	return createAttributeValue(v)
+}
+
+// Names returns the parameter for ExpressionAttributeNames.
+func (b *exprBuilder) Names() map[string]*string {
+	if len(b.names) == 0 {
+		return nil
+	}
+	return b.names
+}
+
+// Values returns the parameter for ExpressionAttributeValues.
+func (b *exprBuilder) Values() map[string]*SDK.AttributeValue {
+	if len(b.values) == 0 {
+		return nil
+	}
+	return b.values
+}
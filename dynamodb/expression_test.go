@@ -0,0 +1,108 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprBuild(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		expr     Expr
+		expected string
+	}{
+		{ExprEQ("name", "foo"), "#c0 = :c0"},
+		{ExprNE("name", "foo"), "#c0 <> :c0"},
+		{ExprBetween("age", 10, 20), "#c0 BETWEEN :c0 AND :c1"},
+		{ExprIn("status", "a", "b", "c"), "#c0 IN (:c0, :c1, :c2)"},
+		{ExprIn("status"), "attribute_exists(#c0) AND attribute_not_exists(#c0)"},
+		{ExprBeginsWith("name", "fo"), "begins_with(#c0, :c0)"},
+		{ExprContains("tags", "x"), "contains(#c0, :c0)"},
+		{ExprAttributeExists("name"), "attribute_exists(#c0)"},
+		{ExprAttributeNotExists("name"), "attribute_not_exists(#c0)"},
+		{ExprAttributeType("name", "S"), "attribute_type(#c0, :c0)"},
+		{ExprSize("tags").GT(3), "size(#c0) > :c0"},
+		{ExprEQ("a.b[0].c", 1), "#c0.#c1[0].#c2 = :c0"},
+		{ExprEQ("a.b.a", 1), "#c0.#c1.#c0 = :c0"},
+		{ExprEQ("a", 1).And(ExprEQ("b", 2)), "#c0 = :c0 AND #c1 = :c1"},
+		{ExprEQ("a", 1).Or(ExprEQ("b", 2)).And(ExprEQ("c", 3)), "(#c0 = :c0 OR #c1 = :c1) AND #c2 = :c2"},
+		{ExprAnd(ExprEQ("a", 1), ExprAnd(ExprEQ("b", 2), ExprEQ("c", 3))), "#c0 = :c0 AND #c1 = :c1 AND #c2 = :c2"},
+		{ExprNot(ExprEQ("a", 1).Or(ExprEQ("b", 2))), "NOT (#c0 = :c0 OR #c1 = :c1)"},
+		{ExprAnd(Expr{}, ExprEQ("a", 1)), "#c0 = :c0"},
+	}
+
+	for _, tt := range tests {
+		b := newExprBuilder("c", nil)
+		assert.Equal(tt.expected, tt.expr.build(b))
+	}
+
+	assert.True(ExprAnd().IsEmpty())
+	assert.True(ExprNot(Expr{}).IsEmpty())
+}
+
+func TestExprInEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	assert.NoError(tbl.Put())
+
+	// no item matches IN with empty values.
+	assert.Error(tbl.DeleteWithCondition(ExprIn("group"), 1, 1))
+	assert.Error(tbl.DeleteWithCondition(ExprIn("unknown"), 1, 1))
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal("a", result["group"])
+
+	assert.NoError(tbl.DeleteWithCondition(ExprNot(ExprIn("group")), 1, 1))
+	result, err = tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Nil(result)
+}
+
+func TestExprBuilderValues(t *testing.T) {
+	assert := assert.New(t)
+
+	b := newExprBuilder("c", map[string]string{"id": "S"})
+	assert.Nil(b.Names())
+	assert.Nil(b.Values())
+
+	expr := ExprEQ("id", 100).And(ExprEQ("count", 100), ExprAttributeType("id", "N"), ExprSize("id").GE(2))
+	assert.Equal("#c0 = :c0 AND #c1 = :c1 AND attribute_type(#c0, :c2) AND size(#c0) >= :c3", expr.build(b))
+
+	names := b.Names()
+	assert.Len(names, 2)
+	assert.Equal("id", *names["#c0"])
+	assert.Equal("count", *names["#c1"])
+
+	values := b.Values()
+	assert.Len(values, 4)
+	assert.Equal("100", *values[":c0"].S, "key attribute value should be converted into the key type")
+	assert.Equal("100", *values[":c1"].N)
+	assert.Equal("N", *values[":c2"].S)
+	assert.Equal("2", *values[":c3"].N)
+}
+
+func TestConditionListFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getUpdateTestTable()
+	cond := tbl.NewConditionList()
+	cond.AndEQ("id", 100)
+	cond.AndBeginsWith("time", 20)
+	cond.FilterEQ("status", "active")
+	cond.Filter(ExprSize("tags").GT(1).Or(ExprAttributeNotExists("tags")))
+	assert.True(cond.HasFilter())
+
+	assert.Equal("#c0 = :c0 AND begins_with(#c1, :c1)", *cond.FormatCondition())
+	assert.Equal("#c2 = :c2 AND (size(#c3) > :c3 OR attribute_not_exists(#c3))", *cond.FormatFilter())
+
+	values := cond.FormatValues()
+	assert.Len(values, 4)
+	assert.Equal("100", *values[":c0"].N)
+	assert.Equal("20", *values[":c1"].N)
+	assert.Equal("active", *values[":c2"].S)
+	assert.Len(cond.FormatNames(), 4)
+}
@@ -1,15 +1,36 @@
 package dynamodb
 
 import (
+	"fmt"
+	"sort"
+
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 
 	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
 )
 
+// expectedOperators maps ComparisonOperator of `Expected` into the operator of ConditionExpression.
+var expectedOperators = map[string]string{
+	ComparisonOperatorEQ: conditionEQ,
+	ComparisonOperatorNE: conditionNE,
+	ComparisonOperatorGT: conditionGT,
+	ComparisonOperatorLT: conditionLT,
+	ComparisonOperatorGE: conditionGE,
+	ComparisonOperatorLE: conditionLE,
+}
+
 // PutItem is wrapped struct for DynamoDB Item to put.
 type PutItem struct {
-	data       map[string]*SDK.AttributeValue
-	conditions map[string]*SDK.ExpectedAttributeValue
+	data        map[string]*SDK.AttributeValue
+	conditions  map[string]*SDK.ExpectedAttributeValue
+	expressions []Expr
+
+	versionAttribute string
+	// the version before the put and its condition, which are set once by applyVersion.
+	isVersionApplied bool
+	baseVersion      int64
+	versionCondition Expr
 }
 
 // NewPutItem returns initialized *PutItem.
@@ -20,11 +41,50 @@ func NewPutItem() *PutItem {
 	}
 }
 
+// NewPutItemFromStruct returns initialized *PutItem from the struct.
+// The struct tag `dynamodb:""` is used to marshal, and these options are supported.
+//     `dynamodb:",omitempty"`  omits the field with zero value.
+//     `dynamodb:",stringset"`  marshals []string as String Set.
+//     `dynamodb:",unixtime"`   marshals time.Time as Number of unix time. (default is RFC3339 String)
+//     `dynamodb:"-"`           ignores the field.
+//     `dynamodb:",version"`    uses the number field as the version attribute for optimistic locking.
+// Nested structs are marshaled as Map.
+func NewPutItemFromStruct(v interface{}) (*PutItem, error) {
+	return NewPutItemFromStructWithTagName(v, defaultResultTag)
+}
+
+// NewPutItemFromStructWithTagName returns initialized *PutItem from the struct and tag name.
+func NewPutItemFromStructWithTagName(v interface{}, structTag string) (*PutItem, error) {
+	encoder := dynamodbattribute.NewEncoder()
+	encoder.TagKey = structTag
+
+	av, err := encoder.Encode(v)
+	switch {
+	case err != nil:
+		return nil, err
+	case av == nil || av.M == nil:
+		return nil, fmt.Errorf("value must be a struct or map; type=%T;", v)
+	}
+
+	item := NewPutItem()
+	item.data = av.M
+	if _, name, ok := findVersionField(v, structTag); ok {
+		item.versionAttribute = name
+	}
+	return item, nil
+}
+
 // AddAttribute adds an attribute to the PutItem.
 func (item *PutItem) AddAttribute(name string, value interface{}) {
 	item.data[name] = createAttributeValue(value)
 }
 
+// SetVersionAttribute sets the version attribute for optimistic locking.
+// It overrides the version attribute of the table design.
+func (item *PutItem) SetVersionAttribute(name string) {
+	item.versionAttribute = name
+}
+
 // GetAttribute gets an attribute from PutItem.
 func (item *PutItem) GetAttribute(name string) interface{} {
 	return item.data[name]
@@ -80,11 +140,56 @@ func (item *PutItem) AddConditionLE(name string, value interface{}) {
 	item.addCondition(name, cond)
 }
 
+// AddCondition adds a condition expression.
+// When the item has condition expressions, all of the conditions are sent as ConditionExpression.
+func (item *PutItem) AddCondition(e Expr) {
+	if e.IsEmpty() {
+		return
+	}
+	item.expressions = append(item.expressions, e)
+}
+
+// HasConditionExpression checks if at least one condition expression is set or not.
+func (item *PutItem) HasConditionExpression() bool {
+	return len(item.expressions) != 0 || !item.versionCondition.IsEmpty()
+}
+
 // addCondition adds a condition.
 func (item *PutItem) addCondition(name string, condition *SDK.ExpectedAttributeValue) {
 	item.conditions[name] = condition
 }
 
+// toItemConditions converts legacy `Expected` conditions and condition expressions to ItemConditions.
+func (item *PutItem) toItemConditions(prefix string) ItemConditions {
+	names := make([]string, 0, len(item.conditions))
+	for name := range item.conditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conds := newItemConditions(prefix)
+	for _, name := range names {
+		expected := item.conditions[name]
+		switch {
+		case expected.Exists != nil && *expected.Exists:
+			conds.AddConditionExist(name)
+		case expected.Exists != nil:
+			conds.AddConditionNotExist(name)
+		case expected.ComparisonOperator != nil:
+			operator, ok := expectedOperators[*expected.ComparisonOperator]
+			if !ok {
+				continue
+			}
+			conds.AddCondition(newCompareExpr(operator, name, expected.Value))
+		}
+	}
+	for _, e := range item.expressions {
+		conds.AddCondition(e)
+	}
+	conds.AddCondition(item.versionCondition)
+	return conds
+}
+
 // CountUp counts up the value.
 func (item *PutItem) CountUp(name string, num int) {
 	// TODO: implement atomic counter
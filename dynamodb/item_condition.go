@@ -0,0 +1,107 @@
+package dynamodb
+
+import (
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ItemConditions contains conditions for ConditionExpression on write operations.
+// All of the conditions are combined with AND.
+type ItemConditions struct {
+	prefix     string
+	conditions []Expr
+}
+
+// newItemConditions returns initialized ItemConditions.
+// prefix is used for the placeholder of ExpressionAttributeNames and ExpressionAttributeValues.
+func newItemConditions(prefix string) ItemConditions {
+	return ItemConditions{
+		prefix: prefix,
+	}
+}
+
+// HasCondition checks if at least one condition is set or not.
+func (c *ItemConditions) HasCondition() bool {
+	return len(c.conditions) != 0
+}
+
+// AddCondition adds a condition expression.
+func (c *ItemConditions) AddCondition(e Expr) {
+	if e.IsEmpty() {
+		return
+	}
+	c.conditions = append(c.conditions, e)
+}
+
+// AddConditionExist adds a EXIST condition.
+func (c *ItemConditions) AddConditionExist(name string) {
+	c.AddCondition(ExprAttributeExists(name))
+}
+
+// AddConditionNotExist adds a NOT EXIST condition.
+func (c *ItemConditions) AddConditionNotExist(name string) {
+	c.AddCondition(ExprAttributeNotExists(name))
+}
+
+// AddConditionEQ adds a EQUAL condition.
+func (c *ItemConditions) AddConditionEQ(name string, value interface{}) {
+	c.AddCondition(ExprEQ(name, value))
+}
+
+// AddConditionNE adds a NOT EQUAL condition.
+func (c *ItemConditions) AddConditionNE(name string, value interface{}) {
+	c.AddCondition(ExprNE(name, value))
+}
+
+// AddConditionGT adds a GREATER THAN condition.
+func (c *ItemConditions) AddConditionGT(name string, value interface{}) {
+	c.AddCondition(ExprGT(name, value))
+}
+
+// AddConditionLT adds a LESS THAN condition.
+func (c *ItemConditions) AddConditionLT(name string, value interface{}) {
+	c.AddCondition(ExprLT(name, value))
+}
+
+// AddConditionGE adds a GREATER THAN or EQUAL condition.
+func (c *ItemConditions) AddConditionGE(name string, value interface{}) {
+	c.AddCondition(ExprGE(name, value))
+}
+
+// AddConditionLE adds a LESS THAN or EQUAL condition.
+func (c *ItemConditions) AddConditionLE(name string, value interface{}) {
+	c.AddCondition(ExprLE(name, value))
+}
+
+// Expr returns the combined condition expression.
+func (c *ItemConditions) Expr() Expr {
+	return ExprAnd(c.conditions...)
+}
+
+// FormatCondition returns string pointer for ConditionExpression.
+func (c *ItemConditions) FormatCondition() *string {
+	e, _ := c.build()
+	return e
+}
+
+// FormatValues returns the parameter for ExpressionAttributeValues.
+func (c *ItemConditions) FormatValues() map[string]*SDK.AttributeValue {
+	_, b := c.build()
+	return b.Values()
+}
+
+// FormatNames returns the parameter for ExpressionAttributeNames.
+func (c *ItemConditions) FormatNames() map[string]*string {
+	_, b := c.build()
+	return b.Names()
+}
+
+// build returns ConditionExpression and the builder which contains names and values.
+func (c *ItemConditions) build() (*string, *exprBuilder) {
+	b := newExprBuilder(c.prefix, nil)
+	if !c.HasCondition() {
+		return nil, b
+	}
+
+	e := c.Expr().build(b)
+	return &e, b
+}
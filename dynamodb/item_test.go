@@ -2,6 +2,7 @@ package dynamodb
 
 import (
 	"testing"
+	"time"
 
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +16,58 @@ func TestNewPutItem(t *testing.T) {
 	assert.Len(item.conditions, 0)
 }
 
+type testStructItem struct {
+	ID      int               `dynamodb:"id"`
+	Time    int               `dynamodb:"time"`
+	Name    string            `dynamodb:"name,omitempty"`
+	Tags    []string          `dynamodb:"tags,stringset"`
+	Created time.Time         `dynamodb:"created,unixtime"`
+	Updated time.Time         `dynamodb:"updated"`
+	Nested  testStructNested  `dynamodb:"nested"`
+	Extra   map[string]string `dynamodb:"extra,omitempty"`
+	Ignored string            `dynamodb:"-"`
+}
+
+type testStructNested struct {
+	Value string `dynamodb:"value"`
+}
+
+func TestNewPutItemFromStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	item, err := NewPutItemFromStruct(testStructItem{
+		ID:      100,
+		Time:    1,
+		Tags:    []string{"a", "b"},
+		Created: now,
+		Updated: now,
+		Nested:  testStructNested{Value: "foo"},
+		Ignored: "ignored",
+	})
+	assert.NoError(err)
+	assert.Len(item.data, 6)
+	assert.Equal("100", *item.data["id"].N)
+	assert.Equal("1", *item.data["time"].N)
+	assert.Len(item.data["tags"].SS, 2)
+	assert.Equal("1577934245", *item.data["created"].N)
+	assert.Equal("2020-01-02T03:04:05Z", *item.data["updated"].S)
+	assert.Equal("foo", *item.data["nested"].M["value"].S)
+	_, ok := item.data["name"]
+	assert.False(ok, "omitempty field should be omitted")
+	_, ok = item.data["Ignored"]
+	assert.False(ok)
+
+	_, err = NewPutItemFromStruct(100)
+	assert.Error(err)
+
+	item, err = NewPutItemFromStructWithTagName(struct {
+		ID int `json:"id"`
+	}{ID: 1}, "json")
+	assert.NoError(err)
+	assert.Equal("1", *item.data["id"].N)
+}
+
 func TestAddAttribute(t *testing.T) {
 	assert := assert.New(t)
 
@@ -148,3 +201,33 @@ func TestNewExpectedCondition(t *testing.T) {
 	assert.Equal("99", *exp.Value.N)
 	assert.Equal("foo", *exp.ComparisonOperator)
 }
+
+func TestToItemConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	item := NewPutItem()
+	item.AddConditionNotExist("id")
+	item.AddConditionEQ("status", "active")
+	item.AddConditionLE("price", 10.5)
+	item.AddCondition(ExprContains("tags", "foo"))
+	assert.True(item.HasConditionExpression())
+
+	conds := item.toItemConditions("pc")
+	assert.Equal("attribute_not_exists(#pc0) AND #pc1 <= :pc0 AND #pc2 = :pc1 AND contains(#pc3, :pc2)", *conds.FormatCondition())
+
+	values := conds.FormatValues()
+	assert.Len(values, 3)
+	assert.Equal("10.5", *values[":pc0"].N)
+	assert.Equal("active", *values[":pc1"].S)
+	assert.Equal("foo", *values[":pc2"].S)
+
+	names := conds.FormatNames()
+	assert.Len(names, 4)
+	assert.Equal("price", *names["#pc1"])
+
+	conds = NewPutItem().toItemConditions("pc")
+	assert.False(conds.HasCondition())
+	assert.Nil(conds.FormatCondition())
+	assert.Nil(conds.FormatNames())
+	assert.Nil(conds.FormatValues())
+}
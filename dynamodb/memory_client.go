@@ -0,0 +1,949 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const (
+	memErrCodeValidation = "ValidationException"
+	memTableARNPrefix    = "arn:aws:dynamodb:local:000000000000:table/"
+
+	batchGetItemMax = 100
+)
+
+// MemoryClient is an in-memory implementation of DynamoDB API for unit tests.
+// It supports the operations used by this package.
+// Other operations are not implemented and panic when called.
+type MemoryClient struct {
+	dynamodbiface.DynamoDBAPI
+
+	mu      sync.RWMutex
+	tables  map[string]*memoryTable
+	backups map[string]*memoryBackup
+}
+
+// NewMemoryClient returns initialized *MemoryClient.
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{
+		tables:  make(map[string]*memoryTable),
+		backups: make(map[string]*memoryBackup),
+	}
+}
+
+func newMemValidationError(msg string) error {
+	return awserr.New(memErrCodeValidation, msg, nil)
+}
+
+func newMemTableNotFoundError(name string) error {
+	return awserr.New(SDK.ErrCodeResourceNotFoundException, fmt.Sprintf("Requested resource not found: Table: %s not found", name), nil)
+}
+
+func newMemConditionalCheckFailedError() error {
+	return awserr.New(SDK.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+}
+
+// getTable returns the table. The caller must hold the lock.
+func (c *MemoryClient) getTable(name *string) (*memoryTable, error) {
+	if name == nil {
+		return nil, newMemValidationError("TableName is missing")
+	}
+	t, ok := c.tables[*name]
+	if !ok {
+		return nil, newMemTableNotFoundError(*name)
+	}
+	return t, nil
+}
+
+// ---------------------------------
+// Table
+// ---------------------------------
+
+// CreateTable creates a table.
+func (c *MemoryClient) CreateTable(in *SDK.CreateTableInput) (*SDK.CreateTableOutput, error) {
+	if in.TableName == nil || len(in.KeySchema) == 0 {
+		return nil, newMemValidationError("TableName and KeySchema are required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := *in.TableName
+	if _, ok := c.tables[name]; ok {
+		return nil, awserr.New(SDK.ErrCodeResourceInUseException, fmt.Sprintf("Table already exists: %s", name), nil)
+	}
+
+	now := time.Now()
+	desc := &SDK.TableDescription{
+		TableName:             pointers.String(name),
+		TableArn:              pointers.String(memTableARNPrefix + name),
+		TableId:               pointers.String(fmt.Sprintf("%d", now.UnixNano())),
+		TableStatus:           pointers.String(SDK.TableStatusActive),
+		CreationDateTime:      &now,
+		AttributeDefinitions:  in.AttributeDefinitions,
+		KeySchema:             in.KeySchema,
+		TableSizeBytes:        pointers.Long64(0),
+		ProvisionedThroughput: newMemThroughputDescription(in.ProvisionedThroughput),
+	}
+	if in.BillingMode != nil {
+		desc.BillingModeSummary = &SDK.BillingModeSummary{
+			BillingMode:                       in.BillingMode,
+			LastUpdateToPayPerRequestDateTime: &now,
+		}
+	}
+	for _, idx := range in.LocalSecondaryIndexes {
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, &SDK.LocalSecondaryIndexDescription{
+			IndexName:  idx.IndexName,
+			IndexArn:   pointers.String(memTableARNPrefix + name + "/index/" + *idx.IndexName),
+			KeySchema:  idx.KeySchema,
+			Projection: idx.Projection,
+		})
+	}
+	for _, idx := range in.GlobalSecondaryIndexes {
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, newMemGSIDescription(name, idx))
+	}
+	updateMemStream(desc, in.StreamSpecification)
+	updateMemSSE(desc, in.SSESpecification)
+
+	t := newMemoryTable(desc)
+	c.tables[name] = t
+	return &SDK.CreateTableOutput{
+		TableDescription: t.description(),
+	}, nil
+}
+
+func newMemThroughputDescription(tp *SDK.ProvisionedThroughput) *SDK.ProvisionedThroughputDescription {
+	desc := &SDK.ProvisionedThroughputDescription{
+		ReadCapacityUnits:      pointers.Long64(0),
+		WriteCapacityUnits:     pointers.Long64(0),
+		NumberOfDecreasesToday: pointers.Long64(0),
+	}
+	if tp != nil {
+		desc.ReadCapacityUnits = tp.ReadCapacityUnits
+		desc.WriteCapacityUnits = tp.WriteCapacityUnits
+	}
+	return desc
+}
+
+func newMemGSIDescription(tableName string, idx *SDK.GlobalSecondaryIndex) *SDK.GlobalSecondaryIndexDescription {
+	return &SDK.GlobalSecondaryIndexDescription{
+		IndexName:             idx.IndexName,
+		IndexArn:              pointers.String(memTableARNPrefix + tableName + "/index/" + *idx.IndexName),
+		IndexStatus:           pointers.String(SDK.IndexStatusActive),
+		KeySchema:             idx.KeySchema,
+		Projection:            idx.Projection,
+		ProvisionedThroughput: newMemThroughputDescription(idx.ProvisionedThroughput),
+	}
+}
+
+// DescribeTable returns the table description.
+func (c *MemoryClient) DescribeTable(in *SDK.DescribeTableInput) (*SDK.DescribeTableOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.DescribeTableOutput{
+		Table: t.description(),
+	}, nil
+}
+
+// DeleteTable deletes the table.
+func (c *MemoryClient) DeleteTable(in *SDK.DeleteTableInput) (*SDK.DeleteTableOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	delete(c.tables, *in.TableName)
+
+	desc := t.description()
+	desc.TableStatus = pointers.String(SDK.TableStatusDeleting)
+	return &SDK.DeleteTableOutput{
+		TableDescription: desc,
+	}, nil
+}
+
+// ListTables returns the table names in alphabetical order.
+func (c *MemoryClient) ListTables(in *SDK.ListTablesInput) (*SDK.ListTablesOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.tables))
+	for name := range c.tables {
+		if in.ExclusiveStartTableName != nil && name <= *in.ExclusiveStartTableName {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &SDK.ListTablesOutput{}
+	for _, name := range names {
+		if in.Limit != nil && int64(len(out.TableNames)) >= *in.Limit {
+			out.LastEvaluatedTableName = out.TableNames[len(out.TableNames)-1]
+			break
+		}
+		out.TableNames = append(out.TableNames, pointers.String(name))
+	}
+	return out, nil
+}
+
+// UpdateTable updates throughput, billing mode, stream and global secondary indexes of the table.
+func (c *MemoryClient) UpdateTable(in *SDK.UpdateTableInput) (*SDK.UpdateTableOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := t.desc
+	if in.ProvisionedThroughput != nil {
+		tp := desc.ProvisionedThroughput
+		if *in.ProvisionedThroughput.ReadCapacityUnits < *tp.ReadCapacityUnits ||
+			*in.ProvisionedThroughput.WriteCapacityUnits < *tp.WriteCapacityUnits {
+			tp.NumberOfDecreasesToday = pointers.Long64(*tp.NumberOfDecreasesToday + 1)
+		}
+		tp.ReadCapacityUnits = in.ProvisionedThroughput.ReadCapacityUnits
+		tp.WriteCapacityUnits = in.ProvisionedThroughput.WriteCapacityUnits
+	}
+	if in.BillingMode != nil {
+		now := time.Now()
+		desc.BillingModeSummary = &SDK.BillingModeSummary{
+			BillingMode:                       in.BillingMode,
+			LastUpdateToPayPerRequestDateTime: &now,
+		}
+		if *in.BillingMode == SDK.BillingModePayPerRequest {
+			desc.ProvisionedThroughput = newMemThroughputDescription(nil)
+			for _, idx := range desc.GlobalSecondaryIndexes {
+				idx.ProvisionedThroughput = newMemThroughputDescription(nil)
+			}
+		}
+	}
+	updateMemStream(desc, in.StreamSpecification)
+	updateMemSSE(desc, in.SSESpecification)
+	if len(in.AttributeDefinitions) != 0 {
+		desc.AttributeDefinitions = mergeMemAttributeDefinitions(desc.AttributeDefinitions, in.AttributeDefinitions)
+	}
+
+	for _, u := range in.GlobalSecondaryIndexUpdates {
+		switch {
+		case u.Create != nil:
+			desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, newMemGSIDescription(*desc.TableName, &SDK.GlobalSecondaryIndex{
+				IndexName:             u.Create.IndexName,
+				KeySchema:             u.Create.KeySchema,
+				Projection:            u.Create.Projection,
+				ProvisionedThroughput: u.Create.ProvisionedThroughput,
+			}))
+		case u.Delete != nil:
+			list := desc.GlobalSecondaryIndexes[:0]
+			for _, idx := range desc.GlobalSecondaryIndexes {
+				if *idx.IndexName != *u.Delete.IndexName {
+					list = append(list, idx)
+				}
+			}
+			desc.GlobalSecondaryIndexes = list
+		case u.Update != nil:
+			for _, idx := range desc.GlobalSecondaryIndexes {
+				if *idx.IndexName == *u.Update.IndexName {
+					idx.ProvisionedThroughput = newMemThroughputDescription(u.Update.ProvisionedThroughput)
+				}
+			}
+		}
+	}
+
+	t.refreshSchema()
+	return &SDK.UpdateTableOutput{
+		TableDescription: t.description(),
+	}, nil
+}
+
+// updateMemStream updates the stream specification and the latest stream.
+func updateMemStream(desc *SDK.TableDescription, spec *SDK.StreamSpecification) {
+	switch {
+	case spec == nil:
+		return
+	case spec.StreamEnabled == nil || !*spec.StreamEnabled:
+		desc.StreamSpecification = nil
+		return
+	}
+
+	label := time.Now().UTC().Format("2006-01-02T15:04:05.000")
+	desc.StreamSpecification = spec
+	desc.LatestStreamLabel = pointers.String(label)
+	desc.LatestStreamArn = pointers.String(*desc.TableArn + "/stream/" + label)
+}
+
+// updateMemSSE updates the server-side encryption status.
+func updateMemSSE(desc *SDK.TableDescription, spec *SDK.SSESpecification) {
+	switch {
+	case spec == nil:
+		return
+	case spec.Enabled == nil || !*spec.Enabled:
+		desc.SSEDescription = &SDK.SSEDescription{
+			Status: pointers.String(SDK.SSEStatusDisabled),
+		}
+		return
+	}
+
+	desc.SSEDescription = &SDK.SSEDescription{
+		Status:  pointers.String(SDK.SSEStatusEnabled),
+		SSEType: pointers.String(SDK.SSETypeKms),
+	}
+}
+
+// DescribeTimeToLive returns Time to Live setting of the table.
+func (c *MemoryClient) DescribeTimeToLive(in *SDK.DescribeTimeToLiveInput) (*SDK.DescribeTimeToLiveOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &SDK.TimeToLiveDescription{
+		TimeToLiveStatus: pointers.String(SDK.TimeToLiveStatusDisabled),
+	}
+	if t.ttl != nil {
+		desc = t.ttl
+	}
+	return &SDK.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: desc,
+	}, nil
+}
+
+// UpdateTimeToLive enables or disables Time to Live of the table.
+// Expired items are not deleted on MemoryClient.
+func (c *MemoryClient) UpdateTimeToLive(in *SDK.UpdateTimeToLiveInput) (*SDK.UpdateTimeToLiveOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := in.TimeToLiveSpecification
+	if spec == nil || spec.Enabled == nil || spec.AttributeName == nil {
+		return nil, newMemValidationError("TimeToLiveSpecification must have AttributeName and Enabled")
+	}
+
+	isEnabled := t.ttl != nil && *t.ttl.TimeToLiveStatus == SDK.TimeToLiveStatusEnabled
+	if isEnabled == *spec.Enabled {
+		return nil, newMemValidationError("TimeToLive is already " + *t.ttlStatus())
+	}
+
+	t.ttl = &SDK.TimeToLiveDescription{
+		AttributeName:    spec.AttributeName,
+		TimeToLiveStatus: pointers.String(SDK.TimeToLiveStatusEnabled),
+	}
+	if !*spec.Enabled {
+		t.ttl = nil
+	}
+	return &SDK.UpdateTimeToLiveOutput{
+		TimeToLiveSpecification: spec,
+	}, nil
+}
+
+func mergeMemAttributeDefinitions(current, added []*SDK.AttributeDefinition) []*SDK.AttributeDefinition {
+	exists := make(map[string]struct{})
+	for _, a := range current {
+		exists[*a.AttributeName] = struct{}{}
+	}
+	for _, a := range added {
+		if _, ok := exists[*a.AttributeName]; !ok {
+			current = append(current, a)
+		}
+	}
+	return current
+}
+
+// ---------------------------------
+// Item
+// ---------------------------------
+
+// PutItem puts the item.
+func (c *MemoryClient) PutItem(in *SDK.PutItemInput) (*SDK.PutItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validateItem(in.Item); err != nil {
+		return nil, err
+	}
+
+	old := t.get(in.Item)
+	if err := checkMemCondition(old, in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, in.Expected, in.ConditionalOperator); err != nil {
+		return nil, err
+	}
+	t.put(in.Item)
+
+	out := &SDK.PutItemOutput{
+		ConsumedCapacity: newMemWriteCapacity(in.TableName, in.ReturnConsumedCapacity, 1),
+	}
+	if in.ReturnValues != nil && *in.ReturnValues == SDK.ReturnValueAllOld {
+		out.Attributes = copyMemItem(old)
+	}
+	return out, nil
+}
+
+// GetItem gets the item.
+func (c *MemoryClient) GetItem(in *SDK.GetItemInput) (*SDK.GetItemOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validateKey(in.Key); err != nil {
+		return nil, err
+	}
+
+	out := &SDK.GetItemOutput{
+		ConsumedCapacity: newMemReadCapacity(in.TableName, in.ReturnConsumedCapacity, 1, in.ConsistentRead),
+	}
+	item := t.get(in.Key)
+	if item == nil {
+		return out, nil
+	}
+	item, err = projectMemItem(item, in.ProjectionExpression, in.ExpressionAttributeNames)
+	if err != nil {
+		return nil, newMemValidationError(err.Error())
+	}
+	out.Item = copyMemItem(item)
+	return out, nil
+}
+
+// DeleteItem deletes the item.
+func (c *MemoryClient) DeleteItem(in *SDK.DeleteItemInput) (*SDK.DeleteItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validateKey(in.Key); err != nil {
+		return nil, err
+	}
+
+	old := t.get(in.Key)
+	if err := checkMemCondition(old, in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, in.Expected, in.ConditionalOperator); err != nil {
+		return nil, err
+	}
+	t.delete(in.Key)
+
+	out := &SDK.DeleteItemOutput{
+		ConsumedCapacity: newMemWriteCapacity(in.TableName, in.ReturnConsumedCapacity, 1),
+	}
+	if in.ReturnValues != nil && *in.ReturnValues == SDK.ReturnValueAllOld {
+		out.Attributes = copyMemItem(old)
+	}
+	return out, nil
+}
+
+// UpdateItem updates the item, or creates a new item when it does not exist.
+func (c *MemoryClient) UpdateItem(in *SDK.UpdateItemInput) (*SDK.UpdateItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validateKey(in.Key); err != nil {
+		return nil, err
+	}
+
+	old := t.get(in.Key)
+	if err := checkMemCondition(old, in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues, in.Expected, in.ConditionalOperator); err != nil {
+		return nil, err
+	}
+
+	item, updated, err := t.applyUpdate(old, in.Key, in.UpdateExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues)
+	if err != nil {
+		return nil, err
+	}
+	t.put(item)
+
+	out := &SDK.UpdateItemOutput{
+		ConsumedCapacity: newMemWriteCapacity(in.TableName, in.ReturnConsumedCapacity, 1),
+	}
+	if in.ReturnValues != nil {
+		switch *in.ReturnValues {
+		case SDK.ReturnValueAllOld:
+			out.Attributes = copyMemItem(old)
+		case SDK.ReturnValueAllNew:
+			out.Attributes = copyMemItem(item)
+		case SDK.ReturnValueUpdatedOld:
+			out.Attributes = memUpdatedAttributes(old, updated)
+		case SDK.ReturnValueUpdatedNew:
+			out.Attributes = memUpdatedAttributes(item, updated)
+		}
+	}
+	return out, nil
+}
+
+// applyUpdate returns the updated item and updated attribute names.
+func (t *memoryTable) applyUpdate(old, key map[string]*SDK.AttributeValue, expr *string, names map[string]*string, values map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, map[string]struct{}, error) {
+	item := copyMemItem(old)
+	if item == nil {
+		item = copyMemItem(key)
+	}
+	if expr == nil {
+		return item, nil, nil
+	}
+
+	u, err := parseMemUpdate(*expr, names, values)
+	if err != nil {
+		return nil, nil, newMemValidationError(err.Error())
+	}
+	updated, err := u.apply(item)
+	if err != nil {
+		return nil, nil, newMemValidationError(err.Error())
+	}
+	for _, k := range t.primaryKeys() {
+		if _, ok := updated[k]; ok {
+			return nil, nil, newMemValidationError(fmt.Sprintf("cannot update attribute of the key; key=%s", k))
+		}
+	}
+	if err := t.validateItem(item); err != nil {
+		return nil, nil, err
+	}
+	return item, updated, nil
+}
+
+func memUpdatedAttributes(item map[string]*SDK.AttributeValue, updated map[string]struct{}) map[string]*SDK.AttributeValue {
+	m := make(map[string]*SDK.AttributeValue)
+	for name := range updated {
+		if v, ok := item[name]; ok {
+			m[name] = copyMemValue(v)
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// checkMemCondition checks ConditionExpression or legacy `Expected` conditions on the item.
+func checkMemCondition(item map[string]*SDK.AttributeValue, expr *string, names map[string]*string, values map[string]*SDK.AttributeValue, expected map[string]*SDK.ExpectedAttributeValue, operator *string) error {
+	if expr != nil && len(expected) != 0 {
+		return newMemValidationError("cannot use ConditionExpression with Expected")
+	}
+
+	cond, err := parseMemCondition(stringValue(expr), names, values)
+	if err != nil {
+		return newMemValidationError(err.Error())
+	}
+	if item == nil {
+		item = map[string]*SDK.AttributeValue{}
+	}
+	if !cond(item) || !matchMemExpected(item, expected, operator) {
+		return newMemConditionalCheckFailedError()
+	}
+	return nil
+}
+
+// ---------------------------------
+// Query and Scan
+// ---------------------------------
+
+// Query executes Query operation.
+func (c *MemoryClient) Query(in *SDK.QueryInput) (*SDK.QueryOutput, error) {
+	return c.QueryWithContext(aws.BackgroundContext(), in)
+}
+
+// QueryWithContext executes Query operation with context.
+func (c *MemoryClient) QueryWithContext(ctx aws.Context, in *SDK.QueryInput, opts ...request.Option) (*SDK.QueryOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, awserr.New(request.CanceledErrorCode, "request context canceled", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.read(memoryReadInput{
+		indexName:      in.IndexName,
+		keyCondition:   in.KeyConditionExpression,
+		filter:         in.FilterExpression,
+		projection:     in.ProjectionExpression,
+		names:          in.ExpressionAttributeNames,
+		values:         in.ExpressionAttributeValues,
+		startKey:       in.ExclusiveStartKey,
+		limit:          in.Limit,
+		selectType:     in.Select,
+		isConsistent:   in.ConsistentRead != nil && *in.ConsistentRead,
+		isDesc:         in.ScanIndexForward != nil && !*in.ScanIndexForward,
+		isQueryRequest: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.QueryOutput{
+		Items:            res.items,
+		LastEvaluatedKey: res.lastEvaluatedKey,
+		Count:            pointers.Long64(res.count),
+		ScannedCount:     pointers.Long64(res.scannedCount),
+		ConsumedCapacity: t.indexCapacity(newMemReadCapacity(in.TableName, in.ReturnConsumedCapacity, res.scannedCount, in.ConsistentRead), in.ReturnConsumedCapacity, in.IndexName),
+	}, nil
+}
+
+// Scan executes Scan operation.
+func (c *MemoryClient) Scan(in *SDK.ScanInput) (*SDK.ScanOutput, error) {
+	return c.ScanWithContext(aws.BackgroundContext(), in)
+}
+
+// ScanWithContext executes Scan operation with context.
+// Items are scanned in the order of the keys, and parallel scan segments are split by the hash of hash key.
+func (c *MemoryClient) ScanWithContext(ctx aws.Context, in *SDK.ScanInput, opts ...request.Option) (*SDK.ScanOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, awserr.New(request.CanceledErrorCode, "request context canceled", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.read(memoryReadInput{
+		indexName:     in.IndexName,
+		filter:        in.FilterExpression,
+		projection:    in.ProjectionExpression,
+		names:         in.ExpressionAttributeNames,
+		values:        in.ExpressionAttributeValues,
+		startKey:      in.ExclusiveStartKey,
+		limit:         in.Limit,
+		selectType:    in.Select,
+		isConsistent:  in.ConsistentRead != nil && *in.ConsistentRead,
+		segment:       in.Segment,
+		totalSegments: in.TotalSegments,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.ScanOutput{
+		Items:            res.items,
+		LastEvaluatedKey: res.lastEvaluatedKey,
+		Count:            pointers.Long64(res.count),
+		ScannedCount:     pointers.Long64(res.scannedCount),
+		ConsumedCapacity: t.indexCapacity(newMemReadCapacity(in.TableName, in.ReturnConsumedCapacity, res.scannedCount, in.ConsistentRead), in.ReturnConsumedCapacity, in.IndexName),
+	}, nil
+}
+
+// ---------------------------------
+// Batch
+// ---------------------------------
+
+// BatchWriteItem puts or deletes items on multiple tables.
+// All of the items are processed, so UnprocessedItems is always empty.
+func (c *MemoryClient) BatchWriteItem(in *SDK.BatchWriteItemInput) (*SDK.BatchWriteItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := 0
+	for name, requests := range in.RequestItems {
+		t, err := c.getTable(pointers.String(name))
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range requests {
+			switch {
+			case r.PutRequest != nil:
+				err = t.validateItem(r.PutRequest.Item)
+			case r.DeleteRequest != nil:
+				err = t.validateKey(r.DeleteRequest.Key)
+			default:
+				err = newMemValidationError("WriteRequest must have PutRequest or DeleteRequest")
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		total += len(requests)
+	}
+	if total == 0 || total > batchWriteItemMax {
+		return nil, newMemValidationError(fmt.Sprintf("the number of write requests must be between 1 and %d; count=%d", batchWriteItemMax, total))
+	}
+
+	out := &SDK.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]*SDK.WriteRequest{},
+	}
+	for name, requests := range in.RequestItems {
+		t := c.tables[name]
+		for _, r := range requests {
+			if r.PutRequest != nil {
+				t.put(r.PutRequest.Item)
+				continue
+			}
+			t.delete(r.DeleteRequest.Key)
+		}
+		if cc := newMemWriteCapacity(pointers.String(name), in.ReturnConsumedCapacity, int64(len(requests))); cc != nil {
+			out.ConsumedCapacity = append(out.ConsumedCapacity, cc)
+		}
+	}
+	return out, nil
+}
+
+// BatchGetItem gets items from multiple tables.
+// All of the keys are processed, so UnprocessedKeys is always empty.
+func (c *MemoryClient) BatchGetItem(in *SDK.BatchGetItemInput) (*SDK.BatchGetItemOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	total := 0
+	for name, ka := range in.RequestItems {
+		t, err := c.getTable(pointers.String(name))
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range ka.Keys {
+			if err := t.validateKey(key); err != nil {
+				return nil, err
+			}
+		}
+		total += len(ka.Keys)
+	}
+	if total == 0 || total > batchGetItemMax {
+		return nil, newMemValidationError(fmt.Sprintf("the number of keys must be between 1 and %d; count=%d", batchGetItemMax, total))
+	}
+
+	out := &SDK.BatchGetItemOutput{
+		Responses:       make(map[string][]map[string]*SDK.AttributeValue),
+		UnprocessedKeys: map[string]*SDK.KeysAndAttributes{},
+	}
+	for name, ka := range in.RequestItems {
+		t := c.tables[name]
+		list := []map[string]*SDK.AttributeValue{}
+		for _, key := range ka.Keys {
+			item := t.get(key)
+			if item == nil {
+				continue
+			}
+			item, err := projectMemItem(item, ka.ProjectionExpression, ka.ExpressionAttributeNames)
+			if err != nil {
+				return nil, newMemValidationError(err.Error())
+			}
+			list = append(list, copyMemItem(item))
+		}
+		out.Responses[name] = list
+		if cc := newMemReadCapacity(pointers.String(name), in.ReturnConsumedCapacity, int64(len(ka.Keys)), ka.ConsistentRead); cc != nil {
+			out.ConsumedCapacity = append(out.ConsumedCapacity, cc)
+		}
+	}
+	return out, nil
+}
+
+// ---------------------------------
+// Transaction
+// ---------------------------------
+
+// TransactWriteItems executes write operations atomically.
+// When any of the conditions fails, it returns *SDK.TransactionCanceledException with the reasons.
+func (c *MemoryClient) TransactWriteItems(in *SDK.TransactWriteItemsInput) (*SDK.TransactWriteItemsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type writeOperation struct {
+		table  *memoryTable
+		put    map[string]*SDK.AttributeValue
+		delete map[string]*SDK.AttributeValue
+	}
+
+	var ops []writeOperation
+	reasons := make([]*SDK.CancellationReason, len(in.TransactItems))
+	isCanceled := false
+	for i, item := range in.TransactItems {
+		var (
+			tableName, cond, returnValues *string
+			key                           map[string]*SDK.AttributeValue
+			names                         map[string]*string
+			values                        map[string]*SDK.AttributeValue
+		)
+		switch {
+		case item.Put != nil:
+			tableName, key, cond, names, values, returnValues = item.Put.TableName, item.Put.Item, item.Put.ConditionExpression, item.Put.ExpressionAttributeNames, item.Put.ExpressionAttributeValues, item.Put.ReturnValuesOnConditionCheckFailure
+		case item.Update != nil:
+			tableName, key, cond, names, values, returnValues = item.Update.TableName, item.Update.Key, item.Update.ConditionExpression, item.Update.ExpressionAttributeNames, item.Update.ExpressionAttributeValues, item.Update.ReturnValuesOnConditionCheckFailure
+		case item.Delete != nil:
+			tableName, key, cond, names, values, returnValues = item.Delete.TableName, item.Delete.Key, item.Delete.ConditionExpression, item.Delete.ExpressionAttributeNames, item.Delete.ExpressionAttributeValues, item.Delete.ReturnValuesOnConditionCheckFailure
+		case item.ConditionCheck != nil:
+			tableName, key, cond, names, values, returnValues = item.ConditionCheck.TableName, item.ConditionCheck.Key, item.ConditionCheck.ConditionExpression, item.ConditionCheck.ExpressionAttributeNames, item.ConditionCheck.ExpressionAttributeValues, item.ConditionCheck.ReturnValuesOnConditionCheckFailure
+		default:
+			return nil, newMemValidationError("TransactWriteItem must have one of Put, Update, Delete or ConditionCheck")
+		}
+
+		t, err := c.getTable(tableName)
+		if err != nil {
+			return nil, err
+		}
+		if item.Put != nil {
+			err = t.validateItem(key)
+		} else {
+			err = t.validateKey(key)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		old := t.get(key)
+		reasons[i] = &SDK.CancellationReason{Code: pointers.String("None")}
+		if err := checkMemCondition(old, cond, names, values, nil, nil); err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == memErrCodeValidation {
+				return nil, err
+			}
+			isCanceled = true
+			reasons[i] = &SDK.CancellationReason{
+				Code:    pointers.String("ConditionalCheckFailed"),
+				Message: pointers.String("The conditional request failed"),
+			}
+			if returnValues != nil && *returnValues == SDK.ReturnValuesOnConditionCheckFailureAllOld {
+				reasons[i].Item = copyMemItem(old)
+			}
+			continue
+		}
+
+		switch {
+		case item.Put != nil:
+			ops = append(ops, writeOperation{table: t, put: key})
+		case item.Update != nil:
+			updated, _, err := t.applyUpdate(old, key, item.Update.UpdateExpression, names, values)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, writeOperation{table: t, put: updated})
+		case item.Delete != nil:
+			ops = append(ops, writeOperation{table: t, delete: key})
+		}
+	}
+
+	if isCanceled {
+		return nil, &SDK.TransactionCanceledException{
+			Message_:            pointers.String("Transaction cancelled, please refer cancellation reasons for specific reasons"),
+			CancellationReasons: reasons,
+		}
+	}
+
+	for _, op := range ops {
+		if op.put != nil {
+			op.table.put(op.put)
+			continue
+		}
+		op.table.delete(op.delete)
+	}
+	return &SDK.TransactWriteItemsOutput{}, nil
+}
+
+// TransactGetItems gets items atomically.
+func (c *MemoryClient) TransactGetItems(in *SDK.TransactGetItemsInput) (*SDK.TransactGetItemsOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := &SDK.TransactGetItemsOutput{}
+	for _, item := range in.TransactItems {
+		if item.Get == nil {
+			return nil, newMemValidationError("TransactGetItem must have Get")
+		}
+
+		t, err := c.getTable(item.Get.TableName)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.validateKey(item.Get.Key); err != nil {
+			return nil, err
+		}
+
+		resp := &SDK.ItemResponse{}
+		if v := t.get(item.Get.Key); v != nil {
+			v, err = projectMemItem(v, item.Get.ProjectionExpression, item.Get.ExpressionAttributeNames)
+			if err != nil {
+				return nil, newMemValidationError(err.Error())
+			}
+			resp.Item = copyMemItem(v)
+		}
+		out.Responses = append(out.Responses, resp)
+	}
+	return out, nil
+}
+
+// ---------------------------------
+// Capacity
+// ---------------------------------
+
+// newMemReadCapacity returns approximate consumed capacity which counts an item as a read unit.
+func newMemReadCapacity(tableName, returnType *string, items int64, consistent *bool) *SDK.ConsumedCapacity {
+	if returnType == nil || *returnType == SDK.ReturnConsumedCapacityNone {
+		return nil
+	}
+	if items < 1 {
+		items = 1
+	}
+
+	units := float64(items)
+	if consistent == nil || !*consistent {
+		units /= 2
+	}
+	return &SDK.ConsumedCapacity{
+		TableName:     tableName,
+		CapacityUnits: pointers.Float64(units),
+	}
+}
+
+// indexCapacity sets the capacity of the table or the index when the return type is INDEXES.
+func (t *memoryTable) indexCapacity(cc *SDK.ConsumedCapacity, returnType, indexName *string) *SDK.ConsumedCapacity {
+	if cc == nil || *returnType != SDK.ReturnConsumedCapacityIndexes {
+		return cc
+	}
+
+	c := &SDK.Capacity{CapacityUnits: cc.CapacityUnits}
+	idx, ok := t.indexes[stringValue(indexName)]
+	switch {
+	case !ok:
+		cc.Table = c
+	case idx.isGlobal:
+		cc.GlobalSecondaryIndexes = map[string]*SDK.Capacity{idx.name: c}
+	default:
+		cc.LocalSecondaryIndexes = map[string]*SDK.Capacity{idx.name: c}
+	}
+	return cc
+}
+
+// newMemWriteCapacity returns approximate consumed capacity which counts an item as a write unit.
+func newMemWriteCapacity(tableName, returnType *string, items int64) *SDK.ConsumedCapacity {
+	if returnType == nil || *returnType == SDK.ReturnConsumedCapacityNone {
+		return nil
+	}
+	return &SDK.ConsumedCapacity{
+		TableName:     tableName,
+		CapacityUnits: pointers.Float64(float64(items)),
+	}
+}
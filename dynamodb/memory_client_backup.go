@@ -0,0 +1,290 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// memoryBackup is an on-demand backup of memoryTable.
+type memoryBackup struct {
+	summary *SDK.BackupSummary
+	desc    *SDK.TableDescription
+	items   map[string]map[string]*SDK.AttributeValue
+}
+
+// ---------------------------------
+// Continuous backups
+// ---------------------------------
+
+// DescribeContinuousBackups returns point-in-time recovery setting of the table.
+func (c *MemoryClient) DescribeContinuousBackups(in *SDK.DescribeContinuousBackupsInput) (*SDK.DescribeContinuousBackupsOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: t.continuousBackups(),
+	}, nil
+}
+
+// UpdateContinuousBackups enables or disables point-in-time recovery of the table.
+func (c *MemoryClient) UpdateContinuousBackups(in *SDK.UpdateContinuousBackupsInput) (*SDK.UpdateContinuousBackupsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := in.PointInTimeRecoverySpecification
+	if spec == nil || spec.PointInTimeRecoveryEnabled == nil {
+		return nil, newMemValidationError("PointInTimeRecoverySpecification must have PointInTimeRecoveryEnabled")
+	}
+
+	t.pitr = nil
+	if *spec.PointInTimeRecoveryEnabled {
+		now := time.Now()
+		t.pitr = &SDK.PointInTimeRecoveryDescription{
+			PointInTimeRecoveryStatus:  pointers.String(SDK.PointInTimeRecoveryStatusEnabled),
+			EarliestRestorableDateTime: &now,
+		}
+	}
+	return &SDK.UpdateContinuousBackupsOutput{
+		ContinuousBackupsDescription: t.continuousBackups(),
+	}, nil
+}
+
+// continuousBackups returns the description of continuous backups.
+func (t *memoryTable) continuousBackups() *SDK.ContinuousBackupsDescription {
+	pitr := &SDK.PointInTimeRecoveryDescription{
+		PointInTimeRecoveryStatus: pointers.String(SDK.PointInTimeRecoveryStatusDisabled),
+	}
+	if t.pitr != nil {
+		now := time.Now()
+		pitr = &SDK.PointInTimeRecoveryDescription{
+			PointInTimeRecoveryStatus:  t.pitr.PointInTimeRecoveryStatus,
+			EarliestRestorableDateTime: t.pitr.EarliestRestorableDateTime,
+			LatestRestorableDateTime:   &now,
+		}
+	}
+	return &SDK.ContinuousBackupsDescription{
+		ContinuousBackupsStatus:        pointers.String(SDK.ContinuousBackupsStatusEnabled),
+		PointInTimeRecoveryDescription: pitr,
+	}
+}
+
+// ---------------------------------
+// On-demand backups
+// ---------------------------------
+
+// CreateBackup creates a snapshot of the table.
+func (c *MemoryClient) CreateBackup(in *SDK.CreateBackupInput) (*SDK.CreateBackupOutput, error) {
+	if in.BackupName == nil || *in.BackupName == "" {
+		return nil, newMemValidationError("BackupName is missing")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, err := c.getTable(in.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	arn := fmt.Sprintf("%s/backup/%020d-%d", *t.desc.TableArn, now.UnixNano(), len(c.backups))
+	b := &memoryBackup{
+		summary: &SDK.BackupSummary{
+			BackupArn:              pointers.String(arn),
+			BackupName:             in.BackupName,
+			BackupStatus:           pointers.String(SDK.BackupStatusAvailable),
+			BackupType:             pointers.String(SDK.BackupTypeUser),
+			BackupSizeBytes:        pointers.Long64(0),
+			BackupCreationDateTime: &now,
+			TableArn:               t.desc.TableArn,
+			TableId:                t.desc.TableId,
+			TableName:              t.desc.TableName,
+		},
+		desc:  t.description(),
+		items: make(map[string]map[string]*SDK.AttributeValue, len(t.items)),
+	}
+	for k, item := range t.items {
+		b.items[k] = copyMemItem(item)
+	}
+	c.backups[arn] = b
+
+	return &SDK.CreateBackupOutput{
+		BackupDetails: &SDK.BackupDetails{
+			BackupArn:              b.summary.BackupArn,
+			BackupName:             b.summary.BackupName,
+			BackupStatus:           b.summary.BackupStatus,
+			BackupType:             b.summary.BackupType,
+			BackupSizeBytes:        b.summary.BackupSizeBytes,
+			BackupCreationDateTime: b.summary.BackupCreationDateTime,
+		},
+	}, nil
+}
+
+// ListBackups returns the backups in order of the creation time.
+func (c *MemoryClient) ListBackups(in *SDK.ListBackupsInput) (*SDK.ListBackupsOutput, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	arns := make([]string, 0, len(c.backups))
+	for arn, b := range c.backups {
+		switch {
+		case in.TableName != nil && *b.summary.TableName != *in.TableName,
+			in.ExclusiveStartBackupArn != nil && arn <= *in.ExclusiveStartBackupArn:
+			continue
+		}
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+
+	out := &SDK.ListBackupsOutput{}
+	for _, arn := range arns {
+		if in.Limit != nil && int64(len(out.BackupSummaries)) >= *in.Limit {
+			out.LastEvaluatedBackupArn = out.BackupSummaries[len(out.BackupSummaries)-1].BackupArn
+			break
+		}
+		out.BackupSummaries = append(out.BackupSummaries, c.backups[arn].summary)
+	}
+	return out, nil
+}
+
+// RestoreTableFromBackup creates a new table from the backup.
+func (c *MemoryClient) RestoreTableFromBackup(in *SDK.RestoreTableFromBackupInput) (*SDK.RestoreTableFromBackupOutput, error) {
+	if in.BackupArn == nil || in.TargetTableName == nil {
+		return nil, newMemValidationError("BackupArn and TargetTableName are required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.backups[*in.BackupArn]
+	if !ok {
+		return nil, awserr.New(SDK.ErrCodeBackupNotFoundException, fmt.Sprintf("Backup not found: %s", *in.BackupArn), nil)
+	}
+
+	t, err := c.restoreTable(*in.TargetTableName, b.desc, b.items, &SDK.RestoreSummary{
+		SourceBackupArn:   b.summary.BackupArn,
+		SourceTableArn:    b.summary.TableArn,
+		RestoreDateTime:   b.summary.BackupCreationDateTime,
+		RestoreInProgress: pointers.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.RestoreTableFromBackupOutput{
+		TableDescription: t.description(),
+	}, nil
+}
+
+// RestoreTableToPointInTime creates a new table from the source table.
+// MemoryClient does not keep the history of items, so the current items are restored at any restorable time.
+func (c *MemoryClient) RestoreTableToPointInTime(in *SDK.RestoreTableToPointInTimeInput) (*SDK.RestoreTableToPointInTimeOutput, error) {
+	if in.TargetTableName == nil {
+		return nil, newMemValidationError("TargetTableName is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, err := c.getTable(in.SourceTableName)
+	if err != nil {
+		return nil, err
+	}
+	if src.pitr == nil {
+		return nil, awserr.New(SDK.ErrCodePointInTimeRecoveryUnavailableException, fmt.Sprintf("Point in time recovery is not enabled for table '%s'", *in.SourceTableName), nil)
+	}
+
+	restoreTime := time.Now()
+	switch {
+	case in.UseLatestRestorableTime != nil && *in.UseLatestRestorableTime:
+	case in.RestoreDateTime == nil:
+		return nil, newMemValidationError("either RestoreDateTime or UseLatestRestorableTime must be specified")
+	case in.RestoreDateTime.Before(*src.pitr.EarliestRestorableDateTime), in.RestoreDateTime.After(restoreTime):
+		return nil, awserr.New(SDK.ErrCodeInvalidRestoreTimeException, "RestoreDateTime is outside of the restorable period", nil)
+	default:
+		restoreTime = *in.RestoreDateTime
+	}
+
+	t, err := c.restoreTable(*in.TargetTableName, src.desc, src.items, &SDK.RestoreSummary{
+		SourceTableArn:    src.desc.TableArn,
+		RestoreDateTime:   &restoreTime,
+		RestoreInProgress: pointers.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &SDK.RestoreTableToPointInTimeOutput{
+		TableDescription: t.description(),
+	}, nil
+}
+
+// restoreTable creates a new table with the schema and the items of the source.
+// Stream, TTL and point-in-time recovery settings are not restored. The caller must hold the lock.
+func (c *MemoryClient) restoreTable(name string, src *SDK.TableDescription, items map[string]map[string]*SDK.AttributeValue, summary *SDK.RestoreSummary) (*memoryTable, error) {
+	if _, ok := c.tables[name]; ok {
+		return nil, awserr.New(SDK.ErrCodeTableAlreadyExistsException, fmt.Sprintf("Table already exists: %s", name), nil)
+	}
+
+	now := time.Now()
+	desc := &SDK.TableDescription{
+		TableName:             pointers.String(name),
+		TableArn:              pointers.String(memTableARNPrefix + name),
+		TableId:               pointers.String(fmt.Sprintf("%d", now.UnixNano())),
+		TableStatus:           pointers.String(SDK.TableStatusActive),
+		CreationDateTime:      &now,
+		AttributeDefinitions:  src.AttributeDefinitions,
+		KeySchema:             src.KeySchema,
+		TableSizeBytes:        pointers.Long64(0),
+		ProvisionedThroughput: newMemThroughputDescription(toMemThroughput(src.ProvisionedThroughput)),
+		BillingModeSummary:    src.BillingModeSummary,
+		SSEDescription:        src.SSEDescription,
+		RestoreSummary:        summary,
+	}
+	for _, idx := range src.LocalSecondaryIndexes {
+		desc.LocalSecondaryIndexes = append(desc.LocalSecondaryIndexes, &SDK.LocalSecondaryIndexDescription{
+			IndexName:  idx.IndexName,
+			IndexArn:   pointers.String(memTableARNPrefix + name + "/index/" + *idx.IndexName),
+			KeySchema:  idx.KeySchema,
+			Projection: idx.Projection,
+		})
+	}
+	for _, idx := range src.GlobalSecondaryIndexes {
+		desc.GlobalSecondaryIndexes = append(desc.GlobalSecondaryIndexes, newMemGSIDescription(name, &SDK.GlobalSecondaryIndex{
+			IndexName:             idx.IndexName,
+			KeySchema:             idx.KeySchema,
+			Projection:            idx.Projection,
+			ProvisionedThroughput: toMemThroughput(idx.ProvisionedThroughput),
+		}))
+	}
+
+	t := newMemoryTable(desc)
+	for k, item := range items {
+		t.items[k] = copyMemItem(item)
+	}
+	c.tables[name] = t
+	return t, nil
+}
+
+func toMemThroughput(desc *SDK.ProvisionedThroughputDescription) *SDK.ProvisionedThroughput {
+	if desc == nil {
+		return nil
+	}
+	return &SDK.ProvisionedThroughput{
+		ReadCapacityUnits:  desc.ReadCapacityUnits,
+		WriteCapacityUnits: desc.WriteCapacityUnits,
+	}
+}
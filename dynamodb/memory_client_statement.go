@@ -0,0 +1,258 @@
+package dynamodb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const (
+	// max number of items in a page of ExecuteStatement.
+	memStatementPageSize = 100
+
+	batchExecuteStatementMemMax = 25
+)
+
+// ExecuteStatement executes PartiQL statement.
+// It supports SELECT, INSERT, UPDATE and DELETE on a table, and the result of SELECT is paginated by NextToken.
+func (c *MemoryClient) ExecuteStatement(in *SDK.ExecuteStatementInput) (*SDK.ExecuteStatementOutput, error) {
+	stmt, err := parseMemStatement(stringValue(in.Statement), in.Parameters)
+	if err != nil {
+		return nil, newMemValidationError(err.Error())
+	}
+
+	if !stmt.isRead() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		t, err := c.getTable(pointers.String(stmt.tableName))
+		if err != nil {
+			return nil, err
+		}
+		if err := t.executeWrite(stmt); err != nil {
+			return nil, err
+		}
+		return &SDK.ExecuteStatementOutput{
+			ConsumedCapacity: newMemWriteCapacity(pointers.String(stmt.tableName), in.ReturnConsumedCapacity, 1),
+		}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, err := c.getTable(pointers.String(stmt.tableName))
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.selectItems(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if in.NextToken != nil {
+		start, err = strconv.Atoi(*in.NextToken)
+		if err != nil || start < 0 || start > len(items) {
+			return nil, newMemValidationError(fmt.Sprintf("invalid NextToken; token=%s", *in.NextToken))
+		}
+	}
+	end := start + memStatementPageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	out := &SDK.ExecuteStatementOutput{
+		Items:            items[start:end],
+		ConsumedCapacity: t.indexCapacity(newMemReadCapacity(pointers.String(stmt.tableName), in.ReturnConsumedCapacity, int64(end-start), in.ConsistentRead), in.ReturnConsumedCapacity, nil),
+	}
+	if end < len(items) {
+		out.NextToken = pointers.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+// BatchExecuteStatement executes PartiQL statements.
+// SELECT statement must have the equality conditions on all of the key attributes.
+// Each statement is executed separately, and the failure is reported in the response.
+func (c *MemoryClient) BatchExecuteStatement(in *SDK.BatchExecuteStatementInput) (*SDK.BatchExecuteStatementOutput, error) {
+	if len(in.Statements) == 0 || len(in.Statements) > batchExecuteStatementMemMax {
+		return nil, newMemValidationError(fmt.Sprintf("the number of statements must be between 1 and %d; count=%d", batchExecuteStatementMemMax, len(in.Statements)))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := &SDK.BatchExecuteStatementOutput{}
+	units := make(map[string]float64)
+	for _, req := range in.Statements {
+		res := &SDK.BatchStatementResponse{}
+		item, tableName, err := c.executeBatchStatement(req)
+		if tableName != "" {
+			res.TableName = pointers.String(tableName)
+			units[tableName] += memStatementCapacityUnits(req)
+		}
+		if err != nil {
+			res.Error = newMemBatchStatementError(err)
+		} else if item != nil {
+			res.Item = item
+		}
+		out.Responses = append(out.Responses, res)
+	}
+
+	if in.ReturnConsumedCapacity == nil || *in.ReturnConsumedCapacity == SDK.ReturnConsumedCapacityNone {
+		return out, nil
+	}
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.ConsumedCapacity = append(out.ConsumedCapacity, &SDK.ConsumedCapacity{
+			TableName:     pointers.String(name),
+			CapacityUnits: pointers.Float64(units[name]),
+		})
+	}
+	return out, nil
+}
+
+// memStatementCapacityUnits returns approximate capacity units of a statement in BatchExecuteStatement.
+func memStatementCapacityUnits(req *SDK.BatchStatementRequest) float64 {
+	if isWriteStatement(stringValue(req.Statement)) {
+		return 1
+	}
+	if req.ConsistentRead != nil && *req.ConsistentRead {
+		return 1
+	}
+	return 0.5
+}
+
+// executeBatchStatement executes a statement in BatchExecuteStatement. The caller must hold the lock.
+func (c *MemoryClient) executeBatchStatement(req *SDK.BatchStatementRequest) (map[string]*SDK.AttributeValue, string, error) {
+	stmt, err := parseMemStatement(stringValue(req.Statement), req.Parameters)
+	if err != nil {
+		return nil, "", newMemValidationError(err.Error())
+	}
+
+	t, err := c.getTable(pointers.String(stmt.tableName))
+	if err != nil {
+		return nil, stmt.tableName, err
+	}
+	if !stmt.isRead() {
+		return nil, stmt.tableName, t.executeWrite(stmt)
+	}
+
+	if _, err := t.statementKey(stmt); err != nil {
+		return nil, stmt.tableName, err
+	}
+	items, err := t.selectItems(stmt)
+	if err != nil || len(items) == 0 {
+		return nil, stmt.tableName, err
+	}
+	return items[0], stmt.tableName, nil
+}
+
+func newMemBatchStatementError(err error) *SDK.BatchStatementError {
+	code := SDK.BatchStatementErrorCodeEnumValidationError
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case SDK.ErrCodeConditionalCheckFailedException:
+			code = SDK.BatchStatementErrorCodeEnumConditionalCheckFailed
+		case SDK.ErrCodeDuplicateItemException:
+			code = SDK.BatchStatementErrorCodeEnumDuplicateItem
+		case SDK.ErrCodeResourceNotFoundException:
+			code = SDK.BatchStatementErrorCodeEnumResourceNotFound
+		}
+	}
+	return &SDK.BatchStatementError{
+		Code:    pointers.String(code),
+		Message: pointers.String(err.Error()),
+	}
+}
+
+// selectItems returns the items matched with WHERE clause in the order of the primary key.
+func (t *memoryTable) selectItems(stmt *memStatement) ([]map[string]*SDK.AttributeValue, error) {
+	cond, err := parseMemCondition(stmt.condition, stmt.names, stmt.values)
+	if err != nil {
+		return nil, newMemValidationError(err.Error())
+	}
+
+	list := make([]map[string]*SDK.AttributeValue, 0, len(t.items))
+	for _, item := range t.items {
+		if cond(item) {
+			list = append(list, item)
+		}
+	}
+	sortMemItemsByKeys(list, t.primaryKeys())
+
+	items := make([]map[string]*SDK.AttributeValue, len(list))
+	for i, item := range list {
+		projected, err := projectMemItem(item, pointers.String(stmt.projection), stmt.names)
+		if err != nil {
+			return nil, newMemValidationError(err.Error())
+		}
+		items[i] = copyMemItem(projected)
+	}
+	return items, nil
+}
+
+// executeWrite executes INSERT, UPDATE or DELETE statement.
+func (t *memoryTable) executeWrite(stmt *memStatement) error {
+	if stmt.action == memStmtInsert {
+		if err := t.validateItem(stmt.item); err != nil {
+			return err
+		}
+		if t.get(stmt.item) != nil {
+			return awserr.New(SDK.ErrCodeDuplicateItemException, "Duplicate primary key exists in table", nil)
+		}
+		t.put(stmt.item)
+		return nil
+	}
+
+	key, err := t.statementKey(stmt)
+	if err != nil {
+		return err
+	}
+	old := t.get(key)
+	switch {
+	case old == nil && stmt.action == memStmtDelete:
+		return nil
+	case old == nil:
+		return newMemConditionalCheckFailedError()
+	}
+	if err := checkMemCondition(old, pointers.String(stmt.condition), stmt.names, stmt.values, nil, nil); err != nil {
+		return err
+	}
+
+	if stmt.action == memStmtDelete {
+		t.delete(key)
+		return nil
+	}
+	item, _, err := t.applyUpdate(old, key, pointers.String(stmt.update), stmt.names, stmt.values)
+	if err != nil {
+		return err
+	}
+	t.put(item)
+	return nil
+}
+
+// statementKey returns the primary key from the equality conditions in WHERE clause.
+func (t *memoryTable) statementKey(stmt *memStatement) (map[string]*SDK.AttributeValue, error) {
+	key := make(map[string]*SDK.AttributeValue)
+	for _, k := range t.primaryKeys() {
+		v, ok := stmt.equals[k]
+		if !ok {
+			return nil, newMemValidationError(fmt.Sprintf("Where clause does not contain a mandatory equality on all key attributes; key=%s", k))
+		}
+		key[k] = v
+	}
+	if err := t.validateKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,284 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMemoryTestTable(t *testing.T) *Table {
+	svc := NewInMemory()
+	design := NewTableDesignWithHashKeyN("mem_table", "id")
+	design.AddRangeKeyN("time")
+	design.AddLSIS("lsi-index", "lsi_key")
+	design.AddGSISN("gsi-index", "group", "time")
+	if err := svc.CreateTable(design); err != nil {
+		t.Fatalf("error on CreateTable; error=%s;", err.Error())
+	}
+
+	tbl, err := svc.GetTable("mem_table")
+	if err != nil {
+		t.Fatalf("error on GetTable; error=%s;", err.Error())
+	}
+	return tbl
+}
+
+func putMemoryTestItem(tbl *Table, id, time int, group, lsi string) {
+	item := NewPutItem()
+	item.AddAttribute("id", id)
+	item.AddAttribute("time", time)
+	if group != "" {
+		item.AddAttribute("group", group)
+	}
+	if lsi != "" {
+		item.AddAttribute("lsi_key", lsi)
+	}
+	tbl.AddItem(item)
+}
+
+func TestMemoryClientTable(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	svc := tbl.service
+	assert.Nil(svc.GetClient())
+	assert.IsType(&MemoryClient{}, svc.GetClientAPI())
+
+	design := tbl.GetDesign()
+	assert.True(design.IsActive())
+	assert.Equal("id", design.GetHashKeyName())
+	assert.Equal("time", design.GetRangeKeyName())
+	assert.True(design.HasLSI())
+	assert.True(design.HasGSI())
+
+	err := svc.CreateTable(NewTableDesignWithHashKeyN("mem_table", "id"))
+	assert.Error(err, "duplicate table should be error")
+
+	list, err := svc.ListTables()
+	assert.NoError(err)
+	assert.Equal([]string{"mem_table"}, list)
+
+	assert.NoError(tbl.UpdateThroughput(5, 5))
+	desc, err := svc.DescribeTable("mem_table")
+	assert.NoError(err)
+	assert.Equal(int64(5), desc.ProvisionedThroughput.ReadCapacityUnits)
+
+	assert.NoError(svc.ForceDeleteTable("mem_table"))
+	_, err = svc.DescribeTable("mem_table")
+	assert.Error(err)
+}
+
+func TestMemoryClientQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	for i := 1; i <= 5; i++ {
+		putMemoryTestItem(tbl, 100, i, "g1", string(rune('f'-i)))
+	}
+	putMemoryTestItem(tbl, 200, 1, "g1", "")
+	putMemoryTestItem(tbl, 200, 2, "", "a")
+	assert.NoError(tbl.BatchPut())
+
+	// key conditions and order.
+	cond := tbl.NewConditionList()
+	cond.AndEQ("id", 100)
+	cond.AndGE("time", 2)
+	res, err := tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(int64(4), res.Count)
+	assert.Equal(2, res.ToSliceMap()[0]["time"])
+
+	cond.SetDesc(true)
+	res, err = tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(5, res.ToSliceMap()[0]["time"])
+
+	// filter is evaluated after limit.
+	cond = tbl.NewConditionList()
+	cond.AndEQ("id", 100)
+	cond.FilterEQ("lsi_key", "a")
+	cond.SetLimit(3)
+	res, err = tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(int64(0), res.Count)
+	assert.Equal(int64(3), res.ScannedCount)
+	assert.NotEmpty(res.LastEvaluatedKey)
+
+	// paging with start keys.
+	cond = tbl.NewConditionList()
+	cond.AndEQ("id", 100)
+	cond.SetLimit(2)
+	list, err := tbl.QueryIter(cond).All()
+	assert.NoError(err)
+	assert.Len(list, 5)
+
+	// LSI is sorted by the index range key.
+	cond = tbl.NewConditionList()
+	cond.SetIndex("lsi-index")
+	cond.AndEQ("id", 100)
+	cond.AndLT("lsi_key", "c")
+	res, err = tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(int64(2), res.Count)
+	assert.Equal("a", res.ToSliceMap()[0]["lsi_key"])
+
+	// GSI is sparse, and the paging works with the index keys.
+	cond = tbl.NewConditionList()
+	cond.SetIndex("gsi-index")
+	cond.AndEQ("group", "g1")
+	cond.AndEQ("time", 1)
+	res, err = tbl.Query(cond)
+	assert.NoError(err)
+	assert.Equal(int64(2), res.Count)
+
+	cond = tbl.NewConditionList()
+	cond.SetIndex("gsi-index")
+	cond.AndEQ("group", "g1")
+	cond.SetLimit(1)
+	list, err = tbl.QueryIter(cond).All()
+	assert.NoError(err)
+	assert.Len(list, 6)
+
+	cond.SetConsistent(true)
+	_, err = tbl.Query(cond)
+	assert.Error(err, "consistent read on GSI should be error")
+
+	// count.
+	cond = tbl.NewConditionList()
+	cond.AndEQ("id", 200)
+	res, err = tbl.Count(cond)
+	assert.NoError(err)
+	assert.Equal(int64(2), res.Count)
+	assert.Len(res.Items, 0)
+}
+
+func TestMemoryClientScan(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	for i := 0; i < 30; i++ {
+		putMemoryTestItem(tbl, i%7, i, "", "")
+	}
+	assert.NoError(tbl.Put())
+
+	cond := tbl.NewConditionList()
+	cond.FilterLT("time", 10)
+	cond.SetLimit(4)
+	list, err := tbl.ScanIter(cond).All()
+	assert.NoError(err)
+	assert.Len(list, 10)
+
+	res, err := tbl.ParallelScan(tbl.NewConditionList(), 3, func(map[string]interface{}) error { return nil })
+	assert.NoError(err)
+	assert.Equal(int64(30), res.Count())
+	assert.True(res.ConsumedCapacity() > 0)
+}
+
+func TestMemoryClientItem(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+
+	// conditional put with `Expected`.
+	item := NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 1)
+	item.AddConditionNotExist("id")
+	tbl.AddItem(item)
+	assert.NoError(tbl.Put())
+	tbl.AddItem(item)
+	err := tbl.Put()
+	assert.Error(err)
+	assert.Len(tbl.GetErrorItems(), 1)
+
+	// conditional put with ConditionExpression.
+	item = NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 1)
+	item.AddAttribute("name", "foo")
+	item.AddCondition(ExprAttributeExists("id"))
+	tbl.AddItem(item)
+	assert.NoError(tbl.Put())
+
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal("foo", result["name"])
+
+	// invalid key type.
+	_, err = tbl.GetOne("1", 1)
+	assert.Error(err)
+	item = NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 2)
+	item.AddAttribute("lsi_key", 100)
+	tbl.AddItem(item)
+	assert.Error(tbl.Put(), "invalid type of index key should be error")
+
+	// update.
+	u := tbl.Update(1, 1)
+	u.Increment("count", 2)
+	u.AddConditionEQ("name", "foo")
+	u.SetReturnValues(ReturnValueUpdatedNew)
+	out, err := u.Exec()
+	assert.NoError(err)
+	assert.Equal(map[string]interface{}{"count": 2}, out.ToMap())
+
+	u = tbl.Update(1, 1)
+	u.Set("id", 2)
+	_, err = u.Exec()
+	assert.Error(err, "updating the key should be error")
+
+	// conditional delete.
+	err = tbl.DeleteWithCondition(ExprEQ("name", "bar"), 1, 1)
+	assert.Error(err)
+	aerr, ok := err.(awserr.Error)
+	assert.True(ok)
+	assert.Equal(SDK.ErrCodeConditionalCheckFailedException, aerr.Code())
+	assert.NoError(tbl.DeleteWithCondition(ExprEQ("name", "foo"), 1, 1))
+
+	result, err = tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Nil(result)
+}
+
+func TestMemoryClientTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "", "")
+	assert.NoError(tbl.Put())
+
+	tx := tbl.service.Transact()
+	item := NewPutItem()
+	item.AddAttribute("id", 2)
+	item.AddAttribute("time", 1)
+	tx.Put(tbl, item)
+	tx.ConditionCheck(tbl, 1, 1).AddConditionNotExist("id")
+	err := tx.Commit()
+	assert.Error(err)
+
+	canceled, ok := err.(*TransactionCanceledError)
+	assert.True(ok)
+	assert.Len(canceled.FailedReasons(), 1)
+	assert.Equal(1, canceled.FailedReasons()[0].Index)
+
+	result, err := tbl.GetOne(2, 1)
+	assert.NoError(err)
+	assert.Nil(result, "canceled transaction should not write items")
+
+	tx = tbl.service.Transact()
+	tx.Put(tbl, item)
+	tx.Delete(tbl, 1, 1).AddConditionExist("id")
+	assert.NoError(tx.Commit())
+
+	get := tbl.service.TransactGet()
+	get.Get(tbl, 1, 1)
+	get.Get(tbl, 2, 1)
+	res, err := get.Exec()
+	assert.NoError(err)
+	list := res.ToSliceMap()
+	assert.Nil(list[0])
+	assert.Equal(2, list[1]["id"])
+}
@@ -0,0 +1,1196 @@
+package dynamodb
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// token types of expression.
+const (
+	memTokenEOF = iota
+	memTokenIdent
+	memTokenName
+	memTokenValue
+	memTokenNumber
+	memTokenSymbol
+)
+
+type memToken struct {
+	typ  int
+	text string
+}
+
+// tokenizeMemExpression splits the expression into tokens.
+func tokenizeMemExpression(s string) ([]memToken, error) {
+	var tokens []memToken
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '#' || c == ':':
+			j := i + 1
+			for j < len(s) && (isMemIdentChar(rune(s[j])) || s[j] == '-') {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("invalid placeholder at %d; expression=%s", i, s)
+			}
+			typ := memTokenName
+			if c == ':' {
+				typ = memTokenValue
+			}
+			tokens = append(tokens, memToken{typ: typ, text: s[i:j]})
+			i = j
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(s) && unicode.IsDigit(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, memToken{typ: memTokenNumber, text: s[i:j]})
+			i = j
+		case isMemIdentChar(c):
+			j := i
+			for j < len(s) && isMemIdentChar(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, memToken{typ: memTokenIdent, text: s[i:j]})
+			i = j
+		case c == '<' || c == '>':
+			if i+1 < len(s) && (s[i+1] == '=' || (c == '<' && s[i+1] == '>')) {
+				tokens = append(tokens, memToken{typ: memTokenSymbol, text: s[i : i+2]})
+				i += 2
+				continue
+			}
+			tokens = append(tokens, memToken{typ: memTokenSymbol, text: string(c)})
+			i++
+		case strings.ContainsRune("=(),.[]+-", c):
+			tokens = append(tokens, memToken{typ: memTokenSymbol, text: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("invalid character `%c` at %d; expression=%s", c, i, s)
+		}
+	}
+	return append(tokens, memToken{typ: memTokenEOF}), nil
+}
+
+func isMemIdentChar(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// memPathElement is an element of document path.
+// When name is empty, the element is an index of list.
+type memPathElement struct {
+	name  string
+	index int
+}
+
+type memPath []memPathElement
+
+// String returns the path as string. (e.g. `a.b[0]`)
+func (p memPath) String() string {
+	var buf bytes.Buffer
+	for i, e := range p {
+		switch {
+		case e.name == "":
+			fmt.Fprintf(&buf, "[%d]", e.index)
+		case i == 0:
+			buf.WriteString(e.name)
+		default:
+			buf.WriteString("." + e.name)
+		}
+	}
+	return buf.String()
+}
+
+// memExprParser parses condition expression and update expression.
+type memExprParser struct {
+	tokens []memToken
+	pos    int
+	names  map[string]*string
+	values map[string]*SDK.AttributeValue
+}
+
+func newMemExprParser(expr string, names map[string]*string, values map[string]*SDK.AttributeValue) (*memExprParser, error) {
+	tokens, err := tokenizeMemExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &memExprParser{
+		tokens: tokens,
+		names:  names,
+		values: values,
+	}, nil
+}
+
+func (p *memExprParser) peek() memToken {
+	return p.tokens[p.pos]
+}
+
+func (p *memExprParser) next() memToken {
+	t := p.tokens[p.pos]
+	if t.typ != memTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *memExprParser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.typ == memTokenIdent && strings.EqualFold(t.text, word)
+}
+
+func (p *memExprParser) isSymbol(sym string) bool {
+	t := p.peek()
+	return t.typ == memTokenSymbol && t.text == sym
+}
+
+func (p *memExprParser) expectSymbol(sym string) error {
+	if !p.isSymbol(sym) {
+		return fmt.Errorf("expected `%s` but got `%s`", sym, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *memExprParser) expectEOF() error {
+	if t := p.peek(); t.typ != memTokenEOF {
+		return fmt.Errorf("unexpected token `%s`", t.text)
+	}
+	return nil
+}
+
+// memCondition is a parsed condition expression.
+type memCondition func(item map[string]*SDK.AttributeValue) bool
+
+// memOperand is a parsed operand of condition expression.
+type memOperand func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue
+
+// parseMemCondition parses condition expression.
+// Empty expression is always true.
+func parseMemCondition(expr string, names map[string]*string, values map[string]*SDK.AttributeValue) (memCondition, error) {
+	if strings.TrimSpace(expr) == "" {
+		return func(map[string]*SDK.AttributeValue) bool { return true }, nil
+	}
+
+	p, err := newMemExprParser(expr, names, values)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression; expression=%s; error=%s", expr, err.Error())
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, fmt.Errorf("invalid expression; expression=%s; error=%s", expr, err.Error())
+	}
+	return cond, nil
+}
+
+func (p *memExprParser) parseOr() (memCondition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(conditionOR) {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(item map[string]*SDK.AttributeValue) bool {
+			return l(item) || right(item)
+		}
+	}
+	return left, nil
+}
+
+func (p *memExprParser) parseAnd() (memCondition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword(conditionAND) {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(item map[string]*SDK.AttributeValue) bool {
+			return l(item) && right(item)
+		}
+	}
+	return left, nil
+}
+
+func (p *memExprParser) parseNot() (memCondition, error) {
+	if !p.isKeyword("NOT") {
+		return p.parsePrimary()
+	}
+
+	p.next()
+	cond, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return func(item map[string]*SDK.AttributeValue) bool {
+		return !cond(item)
+	}, nil
+}
+
+func (p *memExprParser) parsePrimary() (memCondition, error) {
+	if p.isSymbol("(") {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return cond, p.expectSymbol(")")
+	}
+
+	if t := p.peek(); t.typ == memTokenIdent && p.tokens[p.pos+1].text == "(" && !strings.EqualFold(t.text, "size") {
+		return p.parseFunction()
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.isKeyword("BETWEEN"):
+		p.next()
+		from, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword(conditionAND) {
+			return nil, fmt.Errorf("expected `AND` on BETWEEN but got `%s`", p.peek().text)
+		}
+		p.next()
+		to, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return func(item map[string]*SDK.AttributeValue) bool {
+			v := left(item)
+			return compareMemValues(v, from(item), conditionGE) && compareMemValues(v, to(item), conditionLE)
+		}, nil
+	case p.isKeyword("IN"):
+		p.next()
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		var list []memOperand
+		for {
+			o, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, o)
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return func(item map[string]*SDK.AttributeValue) bool {
+			v := left(item)
+			for _, o := range list {
+				if compareMemValues(v, o(item), conditionEQ) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+
+	t := p.next()
+	switch t.text {
+	case conditionEQ, conditionNE, conditionLT, conditionLE, conditionGT, conditionGE:
+	default:
+		return nil, fmt.Errorf("expected comparator but got `%s`", t.text)
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return func(item map[string]*SDK.AttributeValue) bool {
+		return compareMemValues(left(item), right(item), t.text)
+	}, nil
+}
+
+func (p *memExprParser) parseFunction() (memCondition, error) {
+	fn := strings.ToLower(p.next().text)
+	p.next() // (
+
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var arg memOperand
+	switch fn {
+	case exprFuncAttributeExists, exprFuncAttributeNotExists:
+	case exprFuncAttributeType, exprFuncBeginsWith, exprFuncContains:
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		if arg, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported function `%s`", fn)
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+
+	return func(item map[string]*SDK.AttributeValue) bool {
+		v := getMemPath(item, path)
+		switch fn {
+		case exprFuncAttributeExists:
+			return v != nil
+		case exprFuncAttributeNotExists:
+			return v == nil
+		case exprFuncAttributeType:
+			typ := arg(item)
+			return v != nil && typ != nil && typ.S != nil && memValueType(v) == *typ.S
+		case exprFuncBeginsWith:
+			return memBeginsWith(v, arg(item))
+		case exprFuncContains:
+			return memContains(v, arg(item))
+		}
+		return false
+	}, nil
+}
+
+// parseOperand parses path, value placeholder or size function.
+func (p *memExprParser) parseOperand() (memOperand, error) {
+	t := p.peek()
+	switch {
+	case t.typ == memTokenValue:
+		p.next()
+		v, ok := p.values[t.text]
+		if !ok {
+			return nil, fmt.Errorf("value placeholder is not defined; placeholder=%s", t.text)
+		}
+		return func(map[string]*SDK.AttributeValue) *SDK.AttributeValue { return v }, nil
+	case t.typ == memTokenIdent && strings.EqualFold(t.text, "size") && p.tokens[p.pos+1].text == "(":
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue {
+			n, ok := memValueSize(getMemPath(item, path))
+			if !ok {
+				return nil
+			}
+			return &SDK.AttributeValue{N: pointers.String(strconv.Itoa(n))}
+		}, nil
+	}
+
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	return func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue {
+		return getMemPath(item, path)
+	}, nil
+}
+
+// parsePath parses document path. (e.g. `#a.b[0]`)
+func (p *memExprParser) parsePath() (memPath, error) {
+	name, err := p.parsePathName()
+	if err != nil {
+		return nil, err
+	}
+	path := memPath{{name: name}}
+	for {
+		switch {
+		case p.isSymbol("."):
+			p.next()
+			name, err := p.parsePathName()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, memPathElement{name: name})
+		case p.isSymbol("["):
+			p.next()
+			t := p.next()
+			if t.typ != memTokenNumber {
+				return nil, fmt.Errorf("expected list index but got `%s`", t.text)
+			}
+			index, _ := strconv.Atoi(t.text)
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+			path = append(path, memPathElement{index: index})
+		default:
+			return path, nil
+		}
+	}
+}
+
+func (p *memExprParser) parsePathName() (string, error) {
+	t := p.next()
+	switch t.typ {
+	case memTokenIdent:
+		return t.text, nil
+	case memTokenName:
+		name, ok := p.names[t.text]
+		if !ok || name == nil {
+			return "", fmt.Errorf("name placeholder is not defined; placeholder=%s", t.text)
+		}
+		return *name, nil
+	}
+	return "", fmt.Errorf("expected attribute name but got `%s`", t.text)
+}
+
+// memUpdate is a parsed update expression.
+type memUpdate struct {
+	sets    []memSetAction
+	removes []memPath
+	adds    []memValueAction
+	deletes []memValueAction
+}
+
+type memSetAction struct {
+	path  memPath
+	value memOperand
+}
+
+type memValueAction struct {
+	path  memPath
+	value memOperand
+}
+
+// parseMemUpdate parses update expression.
+func parseMemUpdate(expr string, names map[string]*string, values map[string]*SDK.AttributeValue) (*memUpdate, error) {
+	p, err := newMemExprParser(expr, names, values)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &memUpdate{}
+	for p.peek().typ != memTokenEOF {
+		t := p.next()
+		if t.typ != memTokenIdent {
+			return nil, fmt.Errorf("invalid update expression; expression=%s; token=%s", expr, t.text)
+		}
+		action := strings.ToUpper(t.text)
+		for {
+			if err := p.parseUpdateAction(u, action); err != nil {
+				return nil, fmt.Errorf("invalid update expression; expression=%s; error=%s", expr, err.Error())
+			}
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	return u, nil
+}
+
+func (p *memExprParser) parseUpdateAction(u *memUpdate, action string) error {
+	path, err := p.parsePath()
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case updateActionSet:
+		if err := p.expectSymbol("="); err != nil {
+			return err
+		}
+		value, err := p.parseSetValue()
+		if err != nil {
+			return err
+		}
+		u.sets = append(u.sets, memSetAction{path: path, value: value})
+	case updateActionRemove:
+		u.removes = append(u.removes, path)
+	case updateActionAdd, updateActionDelete:
+		value, err := p.parseOperand()
+		if err != nil {
+			return err
+		}
+		if action == updateActionAdd {
+			u.adds = append(u.adds, memValueAction{path: path, value: value})
+		} else {
+			u.deletes = append(u.deletes, memValueAction{path: path, value: value})
+		}
+	default:
+		return fmt.Errorf("unsupported action `%s`", action)
+	}
+	return nil
+}
+
+// parseSetValue parses the value of SET action. (e.g. `if_not_exists(#a, :a) + :b`)
+func (p *memExprParser) parseSetValue() (memOperand, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isSymbol("+") && !p.isSymbol("-") {
+		return left, nil
+	}
+
+	operator := p.next().text
+	right, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	return func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue {
+		return calcMemNumber(left(item), right(item), operator)
+	}, nil
+}
+
+func (p *memExprParser) parseSetOperand() (memOperand, error) {
+	t := p.peek()
+	if t.typ != memTokenIdent || p.tokens[p.pos+1].text != "(" {
+		return p.parseOperand()
+	}
+
+	fn := strings.ToLower(p.next().text)
+	p.next() // (
+	switch fn {
+	case "if_not_exists":
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		def, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue {
+			if v := getMemPath(item, path); v != nil {
+				return v
+			}
+			return def(item)
+		}, p.expectSymbol(")")
+	case "list_append":
+		left, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		right, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return func(item map[string]*SDK.AttributeValue) *SDK.AttributeValue {
+			l, r := left(item), right(item)
+			if l == nil || r == nil {
+				return nil
+			}
+			list := append(append([]*SDK.AttributeValue{}, l.L...), r.L...)
+			return &SDK.AttributeValue{L: list}
+		}, p.expectSymbol(")")
+	}
+	return nil, fmt.Errorf("unsupported function `%s`", fn)
+}
+
+// apply applies the update to the item and returns updated top-level attribute names.
+// All of the values are evaluated with the item before the update.
+func (u *memUpdate) apply(item map[string]*SDK.AttributeValue) (map[string]struct{}, error) {
+	before := copyMemItem(item)
+	updated := make(map[string]struct{})
+
+	for _, a := range u.sets {
+		v := a.value(before)
+		if v == nil {
+			return nil, fmt.Errorf("the provided expression refers to an attribute that does not exist in the item; path=%s", a.path)
+		}
+		if err := setMemPath(item, a.path, v); err != nil {
+			return nil, err
+		}
+		updated[a.path[0].name] = struct{}{}
+	}
+	for _, path := range u.removes {
+		removeMemPath(item, path)
+		updated[path[0].name] = struct{}{}
+	}
+	for _, a := range u.adds {
+		v, err := addMemValue(getMemPath(item, a.path), a.value(before))
+		if err != nil {
+			return nil, err
+		}
+		if err := setMemPath(item, a.path, v); err != nil {
+			return nil, err
+		}
+		updated[a.path[0].name] = struct{}{}
+	}
+	for _, a := range u.deletes {
+		v := deleteMemValue(getMemPath(item, a.path), a.value(before))
+		if v == nil {
+			removeMemPath(item, a.path)
+		} else if err := setMemPath(item, a.path, v); err != nil {
+			return nil, err
+		}
+		updated[a.path[0].name] = struct{}{}
+	}
+	return updated, nil
+}
+
+// getMemPath returns the value of the path in the item.
+func getMemPath(item map[string]*SDK.AttributeValue, path memPath) *SDK.AttributeValue {
+	v := item[path[0].name]
+	for _, e := range path[1:] {
+		switch {
+		case v == nil:
+			return nil
+		case e.name == "":
+			if e.index >= len(v.L) {
+				return nil
+			}
+			v = v.L[e.index]
+		default:
+			v = v.M[e.name]
+		}
+	}
+	return v
+}
+
+// setMemPath sets the value of the path in the item.
+func setMemPath(item map[string]*SDK.AttributeValue, path memPath, value *SDK.AttributeValue) error {
+	if len(path) == 1 {
+		item[path[0].name] = value
+		return nil
+	}
+
+	parent := getMemPath(item, path[:len(path)-1])
+	last := path[len(path)-1]
+	switch {
+	case parent == nil:
+		return fmt.Errorf("the document path provided in the update expression is invalid for update; path=%s", path)
+	case last.name == "" && parent.L != nil:
+		if last.index >= len(parent.L) {
+			parent.L = append(parent.L, value)
+			return nil
+		}
+		parent.L[last.index] = value
+		return nil
+	case last.name != "" && parent.M != nil:
+		parent.M[last.name] = value
+		return nil
+	}
+	return fmt.Errorf("the document path provided in the update expression is invalid for update; path=%s", path)
+}
+
+// removeMemPath removes the value of the path from the item.
+func removeMemPath(item map[string]*SDK.AttributeValue, path memPath) {
+	if len(path) == 1 {
+		delete(item, path[0].name)
+		return
+	}
+
+	parent := getMemPath(item, path[:len(path)-1])
+	last := path[len(path)-1]
+	switch {
+	case parent == nil:
+	case last.name == "" && last.index < len(parent.L):
+		parent.L = append(parent.L[:last.index:last.index], parent.L[last.index+1:]...)
+	case last.name != "":
+		delete(parent.M, last.name)
+	}
+}
+
+// compareMemValues compares two values by the operator.
+// It returns false when the types of values are different, except `<>`.
+func compareMemValues(a, b *SDK.AttributeValue, operator string) bool {
+	if a == nil || b == nil {
+		return operator == conditionNE && (a != nil || b != nil)
+	}
+
+	switch operator {
+	case conditionEQ:
+		return equalMemValues(a, b)
+	case conditionNE:
+		return !equalMemValues(a, b)
+	}
+
+	n, ok := orderMemValues(a, b)
+	if !ok {
+		return false
+	}
+	switch operator {
+	case conditionLT:
+		return n < 0
+	case conditionLE:
+		return n <= 0
+	case conditionGT:
+		return n > 0
+	case conditionGE:
+		return n >= 0
+	}
+	return false
+}
+
+// orderMemValues compares scalar values of N, S and B.
+func orderMemValues(a, b *SDK.AttributeValue) (int, bool) {
+	switch {
+	case a.N != nil && b.N != nil:
+		x, okX := new(big.Float).SetString(*a.N)
+		y, okY := new(big.Float).SetString(*b.N)
+		if !okX || !okY {
+			return 0, false
+		}
+		return x.Cmp(y), true
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	}
+	return 0, false
+}
+
+// equalMemValues checks if two values are same or not.
+func equalMemValues(a, b *SDK.AttributeValue) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	typ := memValueType(a)
+	if typ != memValueType(b) {
+		return false
+	}
+	switch typ {
+	case AttributeTypeNumber, AttributeTypeString, AttributeTypeBinary:
+		n, ok := orderMemValues(a, b)
+		return ok && n == 0
+	case AttributeTypeBool:
+		return *a.BOOL == *b.BOOL
+	case AttributeTypeNull:
+		return true
+	case AttributeTypeStringSet, AttributeTypeNumberSet, AttributeTypeBinarySet:
+		x, y := memSetElements(a), memSetElements(b)
+		if len(x) != len(y) {
+			return false
+		}
+		for _, v := range x {
+			if !containsMemSetElement(y, v) {
+				return false
+			}
+		}
+		return true
+	case AttributeTypeList:
+		if len(a.L) != len(b.L) {
+			return false
+		}
+		for i := range a.L {
+			if !equalMemValues(a.L[i], b.L[i]) {
+				return false
+			}
+		}
+		return true
+	case AttributeTypeMap:
+		if len(a.M) != len(b.M) {
+			return false
+		}
+		for k, v := range a.M {
+			if !equalMemValues(v, b.M[k]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// memValueType returns the data type of the value. (e.g. `S`, `N`, `SS`)
+func memValueType(v *SDK.AttributeValue) string {
+	switch {
+	case v.S != nil:
+		return AttributeTypeString
+	case v.N != nil:
+		return AttributeTypeNumber
+	case v.B != nil:
+		return AttributeTypeBinary
+	case v.BOOL != nil:
+		return AttributeTypeBool
+	case v.NULL != nil:
+		return AttributeTypeNull
+	case v.SS != nil:
+		return AttributeTypeStringSet
+	case v.NS != nil:
+		return AttributeTypeNumberSet
+	case v.BS != nil:
+		return AttributeTypeBinarySet
+	case v.L != nil:
+		return AttributeTypeList
+	case v.M != nil:
+		return AttributeTypeMap
+	}
+	return ""
+}
+
+// memValueSize returns the size of the value for `size()` function.
+func memValueSize(v *SDK.AttributeValue) (int, bool) {
+	switch {
+	case v == nil:
+		return 0, false
+	case v.S != nil:
+		return len(*v.S), true
+	case v.B != nil:
+		return len(v.B), true
+	case v.SS != nil, v.NS != nil, v.BS != nil:
+		return len(memSetElements(v)), true
+	case v.L != nil:
+		return len(v.L), true
+	case v.M != nil:
+		return len(v.M), true
+	}
+	return 0, false
+}
+
+func memBeginsWith(v, prefix *SDK.AttributeValue) bool {
+	switch {
+	case v == nil || prefix == nil:
+		return false
+	case v.S != nil && prefix.S != nil:
+		return strings.HasPrefix(*v.S, *prefix.S)
+	case v.B != nil && prefix.B != nil:
+		return bytes.HasPrefix(v.B, prefix.B)
+	}
+	return false
+}
+
+func memContains(v, operand *SDK.AttributeValue) bool {
+	switch {
+	case v == nil || operand == nil:
+		return false
+	case v.S != nil && operand.S != nil:
+		return strings.Contains(*v.S, *operand.S)
+	case v.B != nil && operand.B != nil:
+		return bytes.Contains(v.B, operand.B)
+	case v.SS != nil, v.NS != nil, v.BS != nil:
+		return containsMemSetElement(memSetElements(v), operand)
+	case v.L != nil:
+		for _, e := range v.L {
+			if equalMemValues(e, operand) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// memSetElements returns elements of the set as scalar values.
+func memSetElements(v *SDK.AttributeValue) []*SDK.AttributeValue {
+	var list []*SDK.AttributeValue
+	for _, s := range v.SS {
+		list = append(list, &SDK.AttributeValue{S: s})
+	}
+	for _, n := range v.NS {
+		list = append(list, &SDK.AttributeValue{N: n})
+	}
+	for _, b := range v.BS {
+		list = append(list, &SDK.AttributeValue{B: b})
+	}
+	return list
+}
+
+func containsMemSetElement(list []*SDK.AttributeValue, v *SDK.AttributeValue) bool {
+	for _, e := range list {
+		if equalMemValues(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// newMemSet creates a set value of the type from scalar values.
+func newMemSet(typ string, list []*SDK.AttributeValue) *SDK.AttributeValue {
+	if len(list) == 0 {
+		return nil
+	}
+
+	v := &SDK.AttributeValue{}
+	for _, e := range list {
+		switch typ {
+		case AttributeTypeStringSet:
+			v.SS = append(v.SS, e.S)
+		case AttributeTypeNumberSet:
+			v.NS = append(v.NS, e.N)
+		case AttributeTypeBinarySet:
+			v.BS = append(v.BS, e.B)
+		}
+	}
+	return v
+}
+
+// calcMemNumber calculates `a + b` or `a - b` of numbers.
+func calcMemNumber(a, b *SDK.AttributeValue, operator string) *SDK.AttributeValue {
+	if a == nil || b == nil || a.N == nil || b.N == nil {
+		return nil
+	}
+	x, okX := new(big.Float).SetString(*a.N)
+	y, okY := new(big.Float).SetString(*b.N)
+	if !okX || !okY {
+		return nil
+	}
+
+	if operator == "-" {
+		x.Sub(x, y)
+	} else {
+		x.Add(x, y)
+	}
+	return &SDK.AttributeValue{N: pointers.String(x.Text('f', -1))}
+}
+
+// addMemValue returns the value of ADD action.
+func addMemValue(current, v *SDK.AttributeValue) (*SDK.AttributeValue, error) {
+	if v == nil {
+		return nil, fmt.Errorf("the value of ADD action is missing")
+	}
+
+	typ := memValueType(v)
+	switch typ {
+	case AttributeTypeNumber:
+		if current == nil {
+			return v, nil
+		}
+		if res := calcMemNumber(current, v, "+"); res != nil {
+			return res, nil
+		}
+	case AttributeTypeStringSet, AttributeTypeNumberSet, AttributeTypeBinarySet:
+		if current == nil {
+			return v, nil
+		}
+		if memValueType(current) == typ {
+			list := memSetElements(current)
+			for _, e := range memSetElements(v) {
+				if !containsMemSetElement(list, e) {
+					list = append(list, e)
+				}
+			}
+			return newMemSet(typ, list), nil
+		}
+	}
+	return nil, fmt.Errorf("an operand in the update expression has an incorrect data type; type=%s", typ)
+}
+
+// deleteMemValue returns the value of DELETE action.
+// It returns nil when the set becomes empty.
+func deleteMemValue(current, v *SDK.AttributeValue) *SDK.AttributeValue {
+	if current == nil || v == nil {
+		return current
+	}
+
+	typ := memValueType(current)
+	removed := memSetElements(v)
+	var list []*SDK.AttributeValue
+	for _, e := range memSetElements(current) {
+		if !containsMemSetElement(removed, e) {
+			list = append(list, e)
+		}
+	}
+	return newMemSet(typ, list)
+}
+
+// matchMemExpected checks the legacy `Expected` conditions.
+func matchMemExpected(item map[string]*SDK.AttributeValue, expected map[string]*SDK.ExpectedAttributeValue, operator *string) bool {
+	if len(expected) == 0 {
+		return true
+	}
+
+	isOr := operator != nil && *operator == SDK.ConditionalOperatorOr
+	for name, e := range expected {
+		ok := matchMemExpectedAttribute(item[name], e)
+		switch {
+		case isOr && ok:
+			return true
+		case !isOr && !ok:
+			return false
+		}
+	}
+	return !isOr
+}
+
+func matchMemExpectedAttribute(v *SDK.AttributeValue, e *SDK.ExpectedAttributeValue) bool {
+	if e == nil {
+		return true
+	}
+
+	if e.ComparisonOperator == nil {
+		switch {
+		case e.Exists != nil && !*e.Exists:
+			return v == nil
+		case e.Value != nil:
+			return equalMemValues(v, e.Value)
+		}
+		return v != nil
+	}
+
+	args := e.AttributeValueList
+	if e.Value != nil {
+		args = []*SDK.AttributeValue{e.Value}
+	}
+	arg := func(i int) *SDK.AttributeValue {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+
+	switch *e.ComparisonOperator {
+	case SDK.ComparisonOperatorEq:
+		return compareMemValues(v, arg(0), conditionEQ)
+	case SDK.ComparisonOperatorNe:
+		return compareMemValues(v, arg(0), conditionNE)
+	case SDK.ComparisonOperatorLt:
+		return compareMemValues(v, arg(0), conditionLT)
+	case SDK.ComparisonOperatorLe:
+		return compareMemValues(v, arg(0), conditionLE)
+	case SDK.ComparisonOperatorGt:
+		return compareMemValues(v, arg(0), conditionGT)
+	case SDK.ComparisonOperatorGe:
+		return compareMemValues(v, arg(0), conditionGE)
+	case SDK.ComparisonOperatorNull:
+		return v == nil
+	case SDK.ComparisonOperatorNotNull:
+		return v != nil
+	case SDK.ComparisonOperatorBeginsWith:
+		return memBeginsWith(v, arg(0))
+	case SDK.ComparisonOperatorContains:
+		return memContains(v, arg(0))
+	case SDK.ComparisonOperatorNotContains:
+		return v != nil && !memContains(v, arg(0))
+	case SDK.ComparisonOperatorBetween:
+		return compareMemValues(v, arg(0), conditionGE) && compareMemValues(v, arg(1), conditionLE)
+	case SDK.ComparisonOperatorIn:
+		for _, a := range args {
+			if equalMemValues(v, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// projectMemItem returns the item which has only the attributes of ProjectionExpression.
+// Nested paths are projected as its top-level attributes.
+func projectMemItem(item map[string]*SDK.AttributeValue, expr *string, names map[string]*string) (map[string]*SDK.AttributeValue, error) {
+	if expr == nil || *expr == "" {
+		return item, nil
+	}
+
+	p, err := newMemExprParser(*expr, names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*SDK.AttributeValue)
+	for {
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, fmt.Errorf("invalid projection expression; expression=%s; error=%s", *expr, err.Error())
+		}
+		if v, ok := item[path[0].name]; ok {
+			result[path[0].name] = v
+		}
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return result, p.expectEOF()
+}
+
+// copyMemItem returns deep copy of the item.
+func copyMemItem(item map[string]*SDK.AttributeValue) map[string]*SDK.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	m := make(map[string]*SDK.AttributeValue, len(item))
+	for k, v := range item {
+		m[k] = copyMemValue(v)
+	}
+	return m
+}
+
+func copyMemValue(v *SDK.AttributeValue) *SDK.AttributeValue {
+	if v == nil {
+		return nil
+	}
+
+	c := *v
+	if v.B != nil {
+		c.B = append([]byte{}, v.B...)
+	}
+	if v.SS != nil {
+		c.SS = append([]*string{}, v.SS...)
+	}
+	if v.NS != nil {
+		c.NS = append([]*string{}, v.NS...)
+	}
+	if v.BS != nil {
+		c.BS = append([][]byte{}, v.BS...)
+	}
+	if v.L != nil {
+		c.L = make([]*SDK.AttributeValue, len(v.L))
+		for i, e := range v.L {
+			c.L[i] = copyMemValue(e)
+		}
+	}
+	if v.M != nil {
+		c.M = copyMemItem(v.M)
+	}
+	return &c
+}
+
+// memKeyString returns a unique string of the key attributes to identify the item.
+func memKeyString(item map[string]*SDK.AttributeValue, keys ...string) string {
+	list := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		v := item[k]
+		if v == nil {
+			list = append(list, "")
+			continue
+		}
+		list = append(list, memValueType(v)+":"+memScalarString(v))
+	}
+	return strings.Join(list, "\x00")
+}
+
+func memScalarString(v *SDK.AttributeValue) string {
+	switch {
+	case v.S != nil:
+		return *v.S
+	case v.N != nil:
+		if f, ok := new(big.Float).SetString(*v.N); ok {
+			return f.Text('g', -1)
+		}
+		return *v.N
+	case v.B != nil:
+		return string(v.B)
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package dynamodb
+
+import (
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func TestParseMemCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	item := Marshal(map[string]interface{}{
+		"id":     100,
+		"name":   "foo",
+		"tags":   []string{"a", "b"},
+		"nested": map[string]interface{}{"value": 10},
+	})
+	item["list"] = &SDK.AttributeValue{L: []*SDK.AttributeValue{{S: pointers.String("x")}}}
+
+	names := map[string]*string{"#n": pointers.String("name"), "#v": pointers.String("value")}
+	values := Marshal(map[string]interface{}{
+		":id":   100,
+		":low":  50,
+		":high": 150,
+		":name": "foo",
+		":pre":  "fo",
+		":tag":  "a",
+		":size": 2,
+		":type": "SS",
+		":x":    "x",
+	})
+
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{"", true},
+		{"id = :id", true},
+		{"id <> :id", false},
+		{"id < :high AND id > :low", true},
+		{"id BETWEEN :low AND :high", true},
+		{"id BETWEEN :high AND :high", false},
+		{"#n IN (:pre, :name)", true},
+		{"begins_with(#n, :pre)", true},
+		{"contains(tags, :tag)", true},
+		{"size(tags) = :size", true},
+		{"attribute_type(tags, :type)", true},
+		{"attribute_exists(nested.#v) AND nested.#v < :low", true},
+		{"attribute_not_exists(nested.none)", true},
+		{"list[0] = :x", true},
+		{"NOT (id = :id) OR #n = :name", true},
+		{"NOT id = :id OR (#n = :pre AND id = :id)", false},
+		{"id = :name", false},
+	}
+
+	for _, tt := range tests {
+		cond, err := parseMemCondition(tt.expr, names, values)
+		assert.NoError(err, tt.expr)
+		assert.Equal(tt.expected, cond(item), tt.expr)
+	}
+
+	for _, expr := range []string{"id =", "id = :undefined", "#undefined = :id", "unknown(id)", "(id = :id"} {
+		_, err := parseMemCondition(expr, names, values)
+		assert.Error(err, expr)
+	}
+}
+
+func TestParseMemUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	item := Marshal(map[string]interface{}{
+		"id":     100,
+		"count":  1,
+		"tags":   []string{"a", "b"},
+		"old":    "x",
+		"nested": map[string]interface{}{"value": 10},
+	})
+	names := map[string]*string{"#c": pointers.String("count")}
+	values := Marshal(map[string]interface{}{
+		":one":  1,
+		":zero": 0,
+		":tag":  []string{"c"},
+		":del":  []string{"a", "b"},
+		":name": "foo",
+	})
+	values[":list"] = &SDK.AttributeValue{L: []*SDK.AttributeValue{{S: pointers.String("x")}}}
+	values[":empty"] = &SDK.AttributeValue{L: []*SDK.AttributeValue{}}
+
+	u, err := parseMemUpdate("SET #c = #c + :one, total = if_not_exists(total, :zero) - :one, "+
+		"logs = list_append(if_not_exists(logs, :empty), :list), nested.#c = :one, name = :name "+
+		"REMOVE old ADD tags :tag, num :one DELETE tags :del", names, values)
+	assert.NoError(err)
+
+	updated, err := u.apply(item)
+	assert.NoError(err)
+	assert.Len(updated, 8)
+
+	m := UnmarshalAttributeValue(item)
+	assert.Equal(2, m["count"])
+	assert.Equal(-1, m["total"])
+	assert.Equal([]interface{}{"x"}, m["logs"])
+	assert.Equal(map[string]interface{}{"value": 10, "count": 1}, m["nested"])
+	assert.Equal("foo", m["name"])
+	assert.Equal(1, m["num"])
+	assert.Nil(m["old"])
+	assert.Len(item["tags"].SS, 1)
+	assert.Equal("c", *item["tags"].SS[0])
+
+	u, err = parseMemUpdate("DELETE tags :tag", names, values)
+	assert.NoError(err)
+	_, err = u.apply(item)
+	assert.NoError(err)
+	_, ok := item["tags"]
+	assert.False(ok, "empty set should be removed")
+
+	u, err = parseMemUpdate("SET a = none", nil, nil)
+	assert.NoError(err)
+	_, err = u.apply(item)
+	assert.Error(err)
+
+	_, err = parseMemUpdate("UPSERT a = :one", nil, values)
+	assert.Error(err)
+}
+
+func TestMatchMemExpected(t *testing.T) {
+	assert := assert.New(t)
+
+	item := Marshal(map[string]interface{}{"id": 100, "name": "foo"})
+	assert.True(matchMemExpected(item, nil, nil))
+	assert.True(matchMemExpected(item, map[string]*SDK.ExpectedAttributeValue{
+		"id":   {Exists: pointers.Bool(true), Value: createAttributeValue(100)},
+		"none": {Exists: pointers.Bool(false)},
+		"name": NewExpectedCondition("f", ComparisonOperatorGT),
+	}, nil))
+	assert.False(matchMemExpected(item, map[string]*SDK.ExpectedAttributeValue{
+		"id": {Exists: pointers.Bool(false)},
+	}, nil))
+	assert.True(matchMemExpected(item, map[string]*SDK.ExpectedAttributeValue{
+		"id":   {Exists: pointers.Bool(false)},
+		"name": NewExpectedCondition("foo", ComparisonOperatorEQ),
+	}, pointers.String(SDK.ConditionalOperatorOr)))
+}
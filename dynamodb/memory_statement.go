@@ -0,0 +1,629 @@
+package dynamodb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// token types of PartiQL statement.
+const (
+	memStmtTokenEOF = iota
+	memStmtTokenIdent
+	memStmtTokenQuoted
+	memStmtTokenString
+	memStmtTokenNumber
+	memStmtTokenParam
+	memStmtTokenSymbol
+)
+
+// actions of PartiQL statement.
+const (
+	memStmtSelect = "SELECT"
+	memStmtInsert = "INSERT"
+	memStmtUpdate = "UPDATE"
+	memStmtDelete = "DELETE"
+)
+
+// tokenizeMemStatement splits the PartiQL statement into tokens.
+func tokenizeMemStatement(s string) ([]memToken, error) {
+	var tokens []memToken
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := readMemQuoted(s[i:], byte(c))
+			if err != nil {
+				return nil, fmt.Errorf("%s at %d; statement=%s", err.Error(), i, s)
+			}
+			typ := memStmtTokenQuoted
+			if c == '\'' {
+				typ = memStmtTokenString
+			}
+			tokens = append(tokens, memToken{typ: typ, text: text})
+			i += n
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(s) && unicode.IsDigit(rune(s[i+1])) && !isMemStmtOperandEnd(tokens)):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, memToken{typ: memStmtTokenNumber, text: s[i:j]})
+			i = j
+		case isMemIdentChar(c):
+			j := i
+			for j < len(s) && isMemIdentChar(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, memToken{typ: memStmtTokenIdent, text: s[i:j]})
+			i = j
+		case c == '?':
+			tokens = append(tokens, memToken{typ: memStmtTokenParam, text: "?"})
+			i++
+		case c == '<' || c == '>' || c == '!':
+			if i+1 < len(s) && (s[i+1] == '=' || (c == '<' && s[i+1] == '>')) {
+				tokens = append(tokens, memToken{typ: memStmtTokenSymbol, text: s[i : i+2]})
+				i += 2
+				continue
+			}
+			if c == '!' {
+				return nil, fmt.Errorf("invalid character `%c` at %d; statement=%s", c, i, s)
+			}
+			tokens = append(tokens, memToken{typ: memStmtTokenSymbol, text: string(c)})
+			i++
+		case strings.ContainsRune("=(),.[]{}:*+-", c):
+			tokens = append(tokens, memToken{typ: memStmtTokenSymbol, text: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("invalid character `%c` at %d; statement=%s", c, i, s)
+		}
+	}
+	return append(tokens, memToken{typ: memStmtTokenEOF}), nil
+}
+
+// readMemQuoted reads the quoted text and returns the unquoted text and the read length.
+// The quote character in the text is escaped by doubling it.
+func readMemQuoted(s string, quote byte) (string, int, error) {
+	var buf bytes.Buffer
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			buf.WriteByte(quote)
+			i++
+			continue
+		}
+		return buf.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated quote `%c`", quote)
+}
+
+// isMemStmtOperandEnd checks if the last token ends an operand, to distinguish minus operator from negative number.
+func isMemStmtOperandEnd(tokens []memToken) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	t := tokens[len(tokens)-1]
+	switch t.typ {
+	case memStmtTokenSymbol:
+		return t.text == ")" || t.text == "]" || t.text == "}"
+	case memStmtTokenIdent:
+		return !isMemStmtKeyword(t.text)
+	}
+	return true
+}
+
+// isMemStmtKeyword checks if the word is a keyword used in the expression.
+func isMemStmtKeyword(word string) bool {
+	switch strings.ToUpper(word) {
+	case "AND", "OR", "NOT", "BETWEEN", "IN", "SET", "REMOVE", "WHERE", "VALUE", "FROM", "INTO":
+		return true
+	}
+	return false
+}
+
+// memStatement is a parsed PartiQL statement.
+// The clauses are converted into the expressions of DynamoDB API with placeholders.
+type memStatement struct {
+	action     string
+	tableName  string
+	item       map[string]*SDK.AttributeValue
+	projection string
+	update     string
+	condition  string
+	names      map[string]*string
+	values     map[string]*SDK.AttributeValue
+	// equality conditions on top-level attributes in WHERE clause.
+	equals map[string]*SDK.AttributeValue
+}
+
+func (s *memStatement) isRead() bool {
+	return s.action == memStmtSelect
+}
+
+// memStmtParser parses PartiQL statement.
+type memStmtParser struct {
+	tokens   []memToken
+	pos      int
+	params   []*SDK.AttributeValue
+	paramPos int
+	stmt     *memStatement
+}
+
+// parseMemStatement parses PartiQL statement.
+// It supports SELECT, INSERT, UPDATE and DELETE on a table, and the parameters are bound to `?` in order.
+func parseMemStatement(statement string, params []*SDK.AttributeValue) (*memStatement, error) {
+	tokens, err := tokenizeMemStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &memStmtParser{
+		tokens: tokens,
+		params: params,
+		stmt: &memStatement{
+			names:  make(map[string]*string),
+			values: make(map[string]*SDK.AttributeValue),
+			equals: make(map[string]*SDK.AttributeValue),
+		},
+	}
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("invalid statement; statement=%s; error=%s", statement, err.Error())
+	}
+	if p.paramPos != len(params) {
+		return nil, fmt.Errorf("the number of parameters does not match the placeholders; statement=%s; parameters=%d; placeholders=%d", statement, len(params), p.paramPos)
+	}
+	return p.stmt, nil
+}
+
+func (p *memStmtParser) peek() memToken {
+	return p.tokens[p.pos]
+}
+
+func (p *memStmtParser) peekAt(n int) memToken {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *memStmtParser) next() memToken {
+	t := p.tokens[p.pos]
+	if t.typ != memStmtTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *memStmtParser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.typ == memStmtTokenIdent && strings.EqualFold(t.text, word)
+}
+
+func (p *memStmtParser) isSymbol(sym string) bool {
+	t := p.peek()
+	return t.typ == memStmtTokenSymbol && t.text == sym
+}
+
+func (p *memStmtParser) expectKeyword(word string) error {
+	if !p.isKeyword(word) {
+		return fmt.Errorf("expected `%s` but got `%s`", word, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *memStmtParser) expectSymbol(sym string) error {
+	if !p.isSymbol(sym) {
+		return fmt.Errorf("expected `%s` but got `%s`", sym, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *memStmtParser) expectEOF() error {
+	if t := p.peek(); t.typ != memStmtTokenEOF {
+		return fmt.Errorf("unexpected token `%s`", t.text)
+	}
+	return nil
+}
+
+func (p *memStmtParser) parse() error {
+	t := p.next()
+	if t.typ != memStmtTokenIdent {
+		return fmt.Errorf("unexpected token `%s`", t.text)
+	}
+
+	p.stmt.action = strings.ToUpper(t.text)
+	var err error
+	switch p.stmt.action {
+	case memStmtSelect:
+		err = p.parseSelect()
+	case memStmtInsert:
+		err = p.parseInsert()
+	case memStmtUpdate:
+		err = p.parseUpdate()
+	case memStmtDelete:
+		err = p.parseDelete()
+	default:
+		err = fmt.Errorf("unsupported statement `%s`", t.text)
+	}
+	if err != nil {
+		return err
+	}
+	return p.expectEOF()
+}
+
+// parseSelect parses `SELECT * | path, ... FROM table [WHERE condition]`.
+func (p *memStmtParser) parseSelect() error {
+	if p.isSymbol("*") {
+		p.next()
+	} else {
+		list, err := p.parseList(func() (string, error) { return p.parseExpression(true) })
+		if err != nil {
+			return err
+		}
+		p.stmt.projection = strings.Join(list, ", ")
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return err
+	}
+	if err := p.parseTableName(); err != nil {
+		return err
+	}
+	return p.parseWhere(false)
+}
+
+// parseInsert parses `INSERT INTO table VALUE {'name': value, ...}`.
+func (p *memStmtParser) parseInsert() error {
+	if err := p.expectKeyword("INTO"); err != nil {
+		return err
+	}
+	if err := p.parseTableName(); err != nil {
+		return err
+	}
+	if err := p.expectKeyword("VALUE"); err != nil {
+		return err
+	}
+
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	if v.M == nil {
+		return fmt.Errorf("VALUE must be a tuple")
+	}
+	p.stmt.item = v.M
+	return nil
+}
+
+// parseUpdate parses `UPDATE table SET path = value | REMOVE path ... WHERE condition`.
+func (p *memStmtParser) parseUpdate() error {
+	if err := p.parseTableName(); err != nil {
+		return err
+	}
+
+	var sets, removes []string
+	for {
+		switch {
+		case p.isKeyword(updateActionSet):
+			p.next()
+			list, err := p.parseList(func() (string, error) { return p.parseExpression(true) })
+			if err != nil {
+				return err
+			}
+			sets = append(sets, list...)
+		case p.isKeyword(updateActionRemove):
+			p.next()
+			list, err := p.parseList(func() (string, error) { return p.parseExpression(true) })
+			if err != nil {
+				return err
+			}
+			removes = append(removes, list...)
+		default:
+			if len(sets) == 0 && len(removes) == 0 {
+				return fmt.Errorf("expected `SET` or `REMOVE` but got `%s`", p.peek().text)
+			}
+			var actions []string
+			if len(sets) != 0 {
+				actions = append(actions, updateActionSet+" "+strings.Join(sets, ", "))
+			}
+			if len(removes) != 0 {
+				actions = append(actions, updateActionRemove+" "+strings.Join(removes, ", "))
+			}
+			p.stmt.update = strings.Join(actions, " ")
+			return p.parseWhere(true)
+		}
+	}
+}
+
+// parseDelete parses `DELETE FROM table WHERE condition`.
+func (p *memStmtParser) parseDelete() error {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return err
+	}
+	if err := p.parseTableName(); err != nil {
+		return err
+	}
+	return p.parseWhere(true)
+}
+
+func (p *memStmtParser) parseTableName() error {
+	t := p.next()
+	if t.typ != memStmtTokenQuoted && t.typ != memStmtTokenIdent {
+		return fmt.Errorf("expected table name but got `%s`", t.text)
+	}
+	if p.isSymbol(".") {
+		return fmt.Errorf("index is not supported; table=%s", t.text)
+	}
+	p.stmt.tableName = t.text
+	return nil
+}
+
+func (p *memStmtParser) parseWhere(isRequired bool) error {
+	if !p.isKeyword("WHERE") {
+		if isRequired {
+			return fmt.Errorf("WHERE clause is required")
+		}
+		return nil
+	}
+	p.next()
+
+	cond, err := p.parseExpression(false)
+	if err != nil {
+		return err
+	}
+	p.stmt.condition = cond
+	return nil
+}
+
+func (p *memStmtParser) parseList(fn func() (string, error)) ([]string, error) {
+	var list []string
+	for {
+		s, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+		if !p.isSymbol(",") {
+			return list, nil
+		}
+		p.next()
+	}
+}
+
+// parseExpression converts the tokens into the expression of DynamoDB API until the end of the clause.
+// Attribute names and values are replaced with the placeholders.
+func (p *memStmtParser) parseExpression(isUpdate bool) (string, error) {
+	var list []string
+	depth := 0
+	for {
+		t := p.peek()
+		switch {
+		case t.typ == memStmtTokenEOF,
+			t.typ == memStmtTokenIdent && depth == 0 && p.isClauseEnd(),
+			isUpdate && depth == 0 && t.text == "," && t.typ == memStmtTokenSymbol:
+			if len(list) == 0 {
+				return "", fmt.Errorf("empty expression before `%s`", t.text)
+			}
+			return strings.Join(list, " "), nil
+		case t.typ == memStmtTokenIdent && isMemStmtKeyword(t.text):
+			list = append(list, strings.ToUpper(p.next().text))
+		case t.typ == memStmtTokenIdent && p.peekAt(1).text == "(":
+			// function
+			list = append(list, p.next().text)
+		case t.typ == memStmtTokenIdent && !isMemStmtLiteral(t.text), t.typ == memStmtTokenQuoted:
+			name, path, err := p.parsePath()
+			if err != nil {
+				return "", err
+			}
+			list = append(list, path)
+			if !isUpdate && depth == 0 && name != "" && p.isSymbol("=") && isMemStmtValueStart(p.peekAt(1)) {
+				p.next()
+				v, err := p.parseValue()
+				if err != nil {
+					return "", err
+				}
+				p.stmt.equals[name] = v
+				list = append(list, "=", p.addValue(v))
+			}
+		case t.typ == memStmtTokenSymbol && (t.text == "(" || t.text == ")" || t.text == ","):
+			if t.text == "(" {
+				depth++
+			} else if t.text == ")" {
+				depth--
+			}
+			list = append(list, p.next().text)
+		case t.typ == memStmtTokenSymbol && t.text == "!=":
+			p.next()
+			list = append(list, "<>")
+		case isMemStmtValueStart(t):
+			v, err := p.parseValue()
+			if err != nil {
+				return "", err
+			}
+			list = append(list, p.addValue(v))
+		case t.typ == memStmtTokenSymbol && isMemStmtOperator(t.text):
+			list = append(list, p.next().text)
+		default:
+			return "", fmt.Errorf("unexpected token `%s`", t.text)
+		}
+	}
+}
+
+// isClauseEnd checks if the current token is the keyword of the next clause.
+func (p *memStmtParser) isClauseEnd() bool {
+	for _, word := range []string{"FROM", "WHERE", updateActionSet, updateActionRemove} {
+		if p.isKeyword(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePath parses document path and returns the expression with name placeholders.
+// The top-level name is returned when the path has no nested element.
+func (p *memStmtParser) parsePath() (string, string, error) {
+	t := p.next()
+	name := t.text
+	var buf bytes.Buffer
+	buf.WriteString(p.addName(name))
+	isNested := false
+	for {
+		switch {
+		case p.isSymbol("."):
+			p.next()
+			t := p.next()
+			if t.typ != memStmtTokenIdent && t.typ != memStmtTokenQuoted {
+				return "", "", fmt.Errorf("expected attribute name but got `%s`", t.text)
+			}
+			buf.WriteString("." + p.addName(t.text))
+			isNested = true
+		case p.isSymbol("["):
+			p.next()
+			t := p.next()
+			if t.typ != memStmtTokenNumber {
+				return "", "", fmt.Errorf("expected list index but got `%s`", t.text)
+			}
+			if err := p.expectSymbol("]"); err != nil {
+				return "", "", err
+			}
+			buf.WriteString("[" + t.text + "]")
+			isNested = true
+		default:
+			if isNested {
+				name = ""
+			}
+			return name, buf.String(), nil
+		}
+	}
+}
+
+// parseValue parses the literal or the parameter.
+func (p *memStmtParser) parseValue() (*SDK.AttributeValue, error) {
+	t := p.next()
+	switch t.typ {
+	case memStmtTokenParam:
+		if p.paramPos >= len(p.params) {
+			return nil, fmt.Errorf("the number of parameters is less than the placeholders")
+		}
+		v := p.params[p.paramPos]
+		p.paramPos++
+		return copyMemValue(v), nil
+	case memStmtTokenString:
+		return &SDK.AttributeValue{S: pointers.String(t.text)}, nil
+	case memStmtTokenNumber:
+		return &SDK.AttributeValue{N: pointers.String(t.text)}, nil
+	case memStmtTokenIdent:
+		switch strings.ToLower(t.text) {
+		case "true", "false":
+			return &SDK.AttributeValue{BOOL: pointers.Bool(strings.EqualFold(t.text, "true"))}, nil
+		case "null":
+			return &SDK.AttributeValue{NULL: pointers.Bool(true)}, nil
+		}
+	case memStmtTokenSymbol:
+		switch t.text {
+		case "{":
+			return p.parseTuple()
+		case "[":
+			return p.parseArray()
+		}
+	}
+	return nil, fmt.Errorf("expected value but got `%s`", t.text)
+}
+
+// parseTuple parses `{'name': value, ...}` after `{`.
+func (p *memStmtParser) parseTuple() (*SDK.AttributeValue, error) {
+	m := make(map[string]*SDK.AttributeValue)
+	for !p.isSymbol("}") {
+		t := p.next()
+		if t.typ != memStmtTokenString && t.typ != memStmtTokenQuoted {
+			return nil, fmt.Errorf("expected attribute name but got `%s`", t.text)
+		}
+		if err := p.expectSymbol(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		m[t.text] = v
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expectSymbol("}"); err != nil {
+		return nil, err
+	}
+	return &SDK.AttributeValue{M: m}, nil
+}
+
+// parseArray parses `[value, ...]` after `[`.
+func (p *memStmtParser) parseArray() (*SDK.AttributeValue, error) {
+	list := []*SDK.AttributeValue{}
+	for !p.isSymbol("]") {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expectSymbol("]"); err != nil {
+		return nil, err
+	}
+	return &SDK.AttributeValue{L: list}, nil
+}
+
+func (p *memStmtParser) addName(name string) string {
+	placeholder := fmt.Sprintf("#n%d", len(p.stmt.names))
+	p.stmt.names[placeholder] = pointers.String(name)
+	return placeholder
+}
+
+func (p *memStmtParser) addValue(v *SDK.AttributeValue) string {
+	placeholder := fmt.Sprintf(":v%d", len(p.stmt.values))
+	p.stmt.values[placeholder] = v
+	return placeholder
+}
+
+func isMemStmtOperator(sym string) bool {
+	switch sym {
+	case "=", "<>", "<", "<=", ">", ">=", "+", "-":
+		return true
+	}
+	return false
+}
+
+func isMemStmtLiteral(word string) bool {
+	switch strings.ToLower(word) {
+	case "true", "false", "null":
+		return true
+	}
+	return false
+}
+
+func isMemStmtValueStart(t memToken) bool {
+	switch t.typ {
+	case memStmtTokenParam, memStmtTokenString, memStmtTokenNumber:
+		return true
+	case memStmtTokenIdent:
+		return isMemStmtLiteral(t.text)
+	case memStmtTokenSymbol:
+		return t.text == "{" || t.text == "["
+	}
+	return false
+}
@@ -0,0 +1,346 @@
+package dynamodb
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// memoryTable is a table of MemoryClient.
+type memoryTable struct {
+	desc     *SDK.TableDescription
+	hashKey  string
+	rangeKey string
+	attrs    map[string]string
+	indexes  map[string]*memoryIndex
+	items    map[string]map[string]*SDK.AttributeValue
+	ttl      *SDK.TimeToLiveDescription
+	pitr     *SDK.PointInTimeRecoveryDescription
+}
+
+// memoryIndex is a secondary index of memoryTable.
+type memoryIndex struct {
+	name           string
+	hashKey        string
+	rangeKey       string
+	isGlobal       bool
+	projectionType string
+	nonKeyAttrs    []string
+}
+
+func newMemoryTable(desc *SDK.TableDescription) *memoryTable {
+	t := &memoryTable{
+		desc:    desc,
+		items:   make(map[string]map[string]*SDK.AttributeValue),
+		indexes: make(map[string]*memoryIndex),
+	}
+	t.refreshSchema()
+	return t
+}
+
+// refreshSchema updates keys and indexes from the table description.
+func (t *memoryTable) refreshSchema() {
+	t.hashKey, t.rangeKey = keySchemaNames(t.desc.KeySchema)
+
+	t.attrs = make(map[string]string)
+	for _, a := range t.desc.AttributeDefinitions {
+		t.attrs[*a.AttributeName] = *a.AttributeType
+	}
+
+	t.indexes = make(map[string]*memoryIndex)
+	for _, idx := range t.desc.LocalSecondaryIndexes {
+		t.indexes[*idx.IndexName] = newMemoryIndex(*idx.IndexName, idx.KeySchema, idx.Projection, false)
+	}
+	for _, idx := range t.desc.GlobalSecondaryIndexes {
+		t.indexes[*idx.IndexName] = newMemoryIndex(*idx.IndexName, idx.KeySchema, idx.Projection, true)
+	}
+}
+
+func newMemoryIndex(name string, schema []*SDK.KeySchemaElement, proj *SDK.Projection, isGlobal bool) *memoryIndex {
+	idx := &memoryIndex{
+		name:           name,
+		isGlobal:       isGlobal,
+		projectionType: ProjectionTypeAll,
+	}
+	idx.hashKey, idx.rangeKey = keySchemaNames(schema)
+	if proj != nil {
+		if proj.ProjectionType != nil {
+			idx.projectionType = *proj.ProjectionType
+		}
+		for _, a := range proj.NonKeyAttributes {
+			idx.nonKeyAttrs = append(idx.nonKeyAttrs, *a)
+		}
+	}
+	return idx
+}
+
+// description returns a copy of the table description with the current item count.
+func (t *memoryTable) description() *SDK.TableDescription {
+	desc := *t.desc
+	count := int64(len(t.items))
+	desc.ItemCount = &count
+	return &desc
+}
+
+// ttlStatus returns the status of Time to Live.
+func (t *memoryTable) ttlStatus() *string {
+	if t.ttl == nil {
+		return pointers.String(SDK.TimeToLiveStatusDisabled)
+	}
+	return t.ttl.TimeToLiveStatus
+}
+
+// primaryKeys returns key names of the table.
+func (t *memoryTable) primaryKeys() []string {
+	if t.rangeKey == "" {
+		return []string{t.hashKey}
+	}
+	return []string{t.hashKey, t.rangeKey}
+}
+
+// validateKey checks the key has only primary key attributes with valid types.
+func (t *memoryTable) validateKey(key map[string]*SDK.AttributeValue) error {
+	keys := t.primaryKeys()
+	if len(key) != len(keys) {
+		return newMemValidationError("the provided key element does not match the schema")
+	}
+	return t.validateKeyTypes(key, keys...)
+}
+
+// validateItem checks the item has primary key attributes and key attributes of the indexes have valid types.
+func (t *memoryTable) validateItem(item map[string]*SDK.AttributeValue) error {
+	if err := t.validateKeyTypes(item, t.primaryKeys()...); err != nil {
+		return err
+	}
+
+	for name, typ := range t.attrs {
+		if v, ok := item[name]; ok && memValueType(v) != typ {
+			return newMemValidationError(fmt.Sprintf("type mismatch for index key; attribute=%s; expected=%s; actual=%s", name, typ, memValueType(v)))
+		}
+	}
+	return nil
+}
+
+func (t *memoryTable) validateKeyTypes(item map[string]*SDK.AttributeValue, keys ...string) error {
+	for _, k := range keys {
+		v, ok := item[k]
+		switch {
+		case !ok || v == nil:
+			return newMemValidationError(fmt.Sprintf("missing the key in the item; key=%s", k))
+		case memValueType(v) != t.attrs[k]:
+			return newMemValidationError(fmt.Sprintf("type mismatch for key; key=%s; expected=%s; actual=%s", k, t.attrs[k], memValueType(v)))
+		}
+	}
+	return nil
+}
+
+// keyString returns the identifier of the item.
+func (t *memoryTable) keyString(item map[string]*SDK.AttributeValue) string {
+	return memKeyString(item, t.hashKey, t.rangeKey)
+}
+
+// keyOf returns primary key attributes of the item.
+func (t *memoryTable) keyOf(item map[string]*SDK.AttributeValue) map[string]*SDK.AttributeValue {
+	key := make(map[string]*SDK.AttributeValue)
+	for _, k := range t.primaryKeys() {
+		key[k] = item[k]
+	}
+	return key
+}
+
+func (t *memoryTable) get(key map[string]*SDK.AttributeValue) map[string]*SDK.AttributeValue {
+	return t.items[t.keyString(key)]
+}
+
+func (t *memoryTable) put(item map[string]*SDK.AttributeValue) {
+	t.items[t.keyString(item)] = copyMemItem(item)
+}
+
+func (t *memoryTable) delete(key map[string]*SDK.AttributeValue) {
+	delete(t.items, t.keyString(key))
+}
+
+// memoryReadInput contains common parameters of Query and Scan.
+type memoryReadInput struct {
+	indexName      *string
+	keyCondition   *string
+	filter         *string
+	projection     *string
+	names          map[string]*string
+	values         map[string]*SDK.AttributeValue
+	startKey       map[string]*SDK.AttributeValue
+	limit          *int64
+	selectType     *string
+	isConsistent   bool
+	isDesc         bool
+	segment        *int64
+	totalSegments  *int64
+	isQueryRequest bool
+}
+
+// memoryReadOutput contains common results of Query and Scan.
+type memoryReadOutput struct {
+	items            []map[string]*SDK.AttributeValue
+	lastEvaluatedKey map[string]*SDK.AttributeValue
+	count            int64
+	scannedCount     int64
+}
+
+// read executes Query or Scan operation.
+func (t *memoryTable) read(in memoryReadInput) (*memoryReadOutput, error) {
+	hashKey, rangeKey := t.hashKey, t.rangeKey
+	var index *memoryIndex
+	if in.indexName != nil {
+		idx, ok := t.indexes[*in.indexName]
+		switch {
+		case !ok:
+			return nil, newMemValidationError(fmt.Sprintf("the table does not have the specified index; index=%s", *in.indexName))
+		case idx.isGlobal && in.isConsistent:
+			return nil, newMemValidationError("consistent reads are not supported on global secondary indexes")
+		}
+		index = idx
+		hashKey, rangeKey = idx.hashKey, idx.rangeKey
+	}
+
+	if in.isQueryRequest && (in.keyCondition == nil || *in.keyCondition == "") {
+		return nil, newMemValidationError("either the KeyConditions or KeyConditionExpression parameter must be specified in the request")
+	}
+	keyCond, err := parseMemCondition(stringValue(in.keyCondition), in.names, in.values)
+	if err != nil {
+		return nil, newMemValidationError(err.Error())
+	}
+	filter, err := parseMemCondition(stringValue(in.filter), in.names, in.values)
+	if err != nil {
+		return nil, newMemValidationError(err.Error())
+	}
+
+	// collect items on the table or the index.
+	sortKeys := []string{hashKey, rangeKey, t.hashKey, t.rangeKey}
+	list := make([]map[string]*SDK.AttributeValue, 0, len(t.items))
+	for _, item := range t.items {
+		switch {
+		case item[hashKey] == nil,
+			rangeKey != "" && item[rangeKey] == nil,
+			!in.isQueryRequest && !t.inSegment(item, hashKey, in.segment, in.totalSegments),
+			!keyCond(item):
+			continue
+		}
+		list = append(list, item)
+	}
+	sortMemItemsByKeys(list, sortKeys)
+	if in.isDesc {
+		for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+			list[i], list[j] = list[j], list[i]
+		}
+	}
+
+	// skip items until the start key.
+	if len(in.startKey) != 0 {
+		pos := 0
+		for pos < len(list) {
+			n := compareMemItemsByKeys(list[pos], in.startKey, sortKeys)
+			if (!in.isDesc && n > 0) || (in.isDesc && n < 0) {
+				break
+			}
+			pos++
+		}
+		list = list[pos:]
+	}
+
+	out := &memoryReadOutput{}
+	for i, item := range list {
+		if in.limit != nil && out.scannedCount >= *in.limit {
+			last := list[i-1]
+			out.lastEvaluatedKey = memKeyAttributes(last, sortKeys)
+			break
+		}
+
+		out.scannedCount++
+		if !filter(item) {
+			continue
+		}
+		out.count++
+		if in.selectType != nil && *in.selectType == SDK.SelectCount {
+			continue
+		}
+
+		projected := t.projectIndex(item, index)
+		projected, err = projectMemItem(projected, in.projection, in.names)
+		if err != nil {
+			return nil, newMemValidationError(err.Error())
+		}
+		out.items = append(out.items, copyMemItem(projected))
+	}
+	return out, nil
+}
+
+// inSegment checks the item belongs to the segment of parallel scan.
+func (t *memoryTable) inSegment(item map[string]*SDK.AttributeValue, hashKey string, segment, total *int64) bool {
+	if segment == nil || total == nil || *total <= 1 {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(memKeyString(item, hashKey)))
+	return int64(h.Sum32())%*total == *segment
+}
+
+// projectIndex returns the attributes projected into the index.
+func (t *memoryTable) projectIndex(item map[string]*SDK.AttributeValue, index *memoryIndex) map[string]*SDK.AttributeValue {
+	if index == nil || index.projectionType == ProjectionTypeAll {
+		return item
+	}
+
+	names := append([]string{t.hashKey, t.rangeKey, index.hashKey, index.rangeKey}, index.nonKeyAttrs...)
+	return memKeyAttributes(item, names)
+}
+
+// memKeyAttributes returns the attributes of the names in the item.
+func memKeyAttributes(item map[string]*SDK.AttributeValue, names []string) map[string]*SDK.AttributeValue {
+	m := make(map[string]*SDK.AttributeValue)
+	for _, name := range names {
+		if v, ok := item[name]; ok && name != "" {
+			m[name] = copyMemValue(v)
+		}
+	}
+	return m
+}
+
+// sortMemItemsByKeys sorts items by the order of key attributes.
+func sortMemItemsByKeys(items []map[string]*SDK.AttributeValue, keys []string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return compareMemItemsByKeys(items[i], items[j], keys) < 0
+	})
+}
+
+// compareMemItemsByKeys compares two items by the order of key attributes.
+func compareMemItemsByKeys(a, b map[string]*SDK.AttributeValue, keys []string) int {
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		x, y := a[k], b[k]
+		if x == nil || y == nil {
+			continue
+		}
+		n, ok := orderMemValues(x, y)
+		if !ok {
+			continue
+		}
+		if n != 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
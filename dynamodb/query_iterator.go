@@ -0,0 +1,265 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// QueryIter returns *Iterator to fetch all pages of Query operation lazily.
+// The limit of the condition is used as the page size of each request, not the total number of items.
+func (t *Table) QueryIter(cond *ConditionList) *Iterator {
+	return newIterator(cond, t.cursorKeyNames(cond.index), func(ctx aws.Context, c *ConditionList) (*QueryResult, error) {
+		return t.queryWithContext(ctx, c, &SDK.QueryInput{})
+	})
+}
+
+// ScanIter returns *Iterator to fetch all pages of Scan operation lazily.
+// The limit of the condition is used as the page size of each request, not the total number of items.
+func (t *Table) ScanIter(cond *ConditionList) *Iterator {
+	return newIterator(cond, t.cursorKeyNames(cond.index), func(ctx aws.Context, c *ConditionList) (*QueryResult, error) {
+		return t.scanWithContext(ctx, c, &SDK.ScanInput{})
+	})
+}
+
+// cursorKeyNames returns the attribute names of ExclusiveStartKey for the table or the index.
+func (t *Table) cursorKeyNames(indexName string) []string {
+	if t.design == nil || t.design.HashKey == nil {
+		return nil
+	}
+
+	names := []string{t.design.GetHashKeyName()}
+	if t.design.HasRangeKey() {
+		names = append(names, t.design.GetRangeKeyName())
+	}
+
+	var schema []*SDK.KeySchemaElement
+	for _, lsi := range t.design.ListLSI() {
+		if *lsi.IndexName == indexName {
+			schema = lsi.KeySchema
+		}
+	}
+	for _, gsi := range t.design.ListGSI() {
+		if *gsi.IndexName == indexName {
+			schema = gsi.KeySchema
+		}
+	}
+	hashKey, rangeKey := keySchemaNames(schema)
+	names = appendKeyName(names, hashKey)
+	return appendKeyName(names, rangeKey)
+}
+
+// appendKeyName appends the attribute name when it is not empty or duplicated.
+func appendKeyName(names []string, name string) []string {
+	if name == "" {
+		return names
+	}
+	for _, v := range names {
+		if v == name {
+			return names
+		}
+	}
+	return append(names, name)
+}
+
+type pageFetcher func(aws.Context, *ConditionList) (*QueryResult, error)
+
+// Iterator fetches pages of Query or Scan operation lazily.
+type Iterator struct {
+	ctx      context.Context
+	cond     ConditionList
+	keyNames []string
+	fetch    pageFetcher
+
+	page      *QueryResult
+	index     int
+	hasPage   bool
+	isStarted bool
+	err       error
+}
+
+func newIterator(cond *ConditionList, keyNames []string, fetch pageFetcher) *Iterator {
+	return &Iterator{
+		ctx:      context.Background(),
+		cond:     *cond,
+		keyNames: keyNames,
+		fetch:    fetch,
+	}
+}
+
+// SetContext sets context to stop fetching pages.
+func (it *Iterator) SetContext(ctx context.Context) {
+	it.ctx = ctx
+}
+
+// SetCursor sets cursor token to start from the page after the token.
+func (it *Iterator) SetCursor(token string) error {
+	key, err := DecodeCursor(token)
+	if err != nil {
+		return err
+	}
+	it.cond.SetStartKey(key)
+	return nil
+}
+
+// Cursor returns cursor token to resume from the item after the current item.
+// When the items are not read by Next, it resumes from the next page of the last fetched page.
+// It returns empty string when there is no more item.
+func (it *Iterator) Cursor() (string, error) {
+	if it.hasPage && it.index >= 0 && it.index < len(it.page.Items)-1 {
+		key, err := it.itemKey(it.page.Items[it.index])
+		if err != nil {
+			return "", err
+		}
+		return EncodeCursor(key)
+	}
+
+	if !it.hasMorePage() {
+		return "", nil
+	}
+	return EncodeCursor(it.cond.startKey)
+}
+
+// itemKey returns ExclusiveStartKey built from the key attributes of the item.
+func (it *Iterator) itemKey(item map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, error) {
+	if len(it.keyNames) == 0 {
+		return nil, fmt.Errorf("cannot build cursor in the middle of the page without key attributes")
+	}
+
+	key := make(map[string]*SDK.AttributeValue, len(it.keyNames))
+	for _, name := range it.keyNames {
+		v, ok := item[name]
+		if !ok {
+			return nil, fmt.Errorf("cannot find key attribute in the item; attribute=%s;", name)
+		}
+		key[name] = v
+	}
+	return key, nil
+}
+
+// NextPage fetches the next page.
+// It returns false when there is no more page or an error occurs.
+func (it *Iterator) NextPage() bool {
+	if it.err != nil || !it.hasMorePage() {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	page, err := it.fetch(it.ctx, &it.cond)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.isStarted = true
+	it.page = page
+	it.index = -1
+	it.hasPage = true
+	it.cond.SetStartKey(page.LastEvaluatedKey)
+	return true
+}
+
+// Page returns the last fetched page.
+func (it *Iterator) Page() *QueryResult {
+	return it.page
+}
+
+// Next moves to the next item and fetches the next page when needed.
+// It returns false when there is no more item or an error occurs.
+func (it *Iterator) Next() bool {
+	for {
+		if it.hasPage && it.index+1 < len(it.page.Items) {
+			it.index++
+			return true
+		}
+		if !it.NextPage() {
+			return false
+		}
+	}
+}
+
+// RawItem returns the current item.
+func (it *Iterator) RawItem() map[string]*SDK.AttributeValue {
+	if !it.hasPage || it.index < 0 || it.index >= len(it.page.Items) {
+		return nil
+	}
+	return it.page.Items[it.index]
+}
+
+// Item returns the current item as map.
+func (it *Iterator) Item() map[string]interface{} {
+	item := it.RawItem()
+	if item == nil {
+		return nil
+	}
+	return UnmarshalAttributeValue(item)
+}
+
+// Err returns the error occurred on fetching pages.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// All fetches all of the rest items.
+func (it *Iterator) All() ([]map[string]interface{}, error) {
+	var list []map[string]interface{}
+	err := it.ForEach(func(item map[string]interface{}) error {
+		list = append(list, item)
+		return nil
+	})
+	return list, err
+}
+
+// ForEach executes fn for all of the rest items.
+// It stops when fn returns an error.
+func (it *Iterator) ForEach(fn func(map[string]interface{}) error) error {
+	for it.Next() {
+		if err := fn(it.Item()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (it *Iterator) hasMorePage() bool {
+	return !it.isStarted || len(it.cond.startKey) != 0
+}
+
+// EncodeCursor encodes LastEvaluatedKey to opaque cursor token.
+func EncodeCursor(key map[string]*SDK.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes cursor token to ExclusiveStartKey.
+func DecodeCursor(token string) (map[string]*SDK.AttributeValue, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var key map[string]*SDK.AttributeValue
+	if err := json.Unmarshal(b, &key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
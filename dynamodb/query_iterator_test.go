@@ -0,0 +1,252 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// newTestPageFetcher returns pageFetcher which returns pages of items, the page size is 2.
+func newTestPageFetcher(total int, calls *int) pageFetcher {
+	return func(ctx aws.Context, c *ConditionList) (*QueryResult, error) {
+		*calls++
+		start := 0
+		if v, ok := c.startKey["id"]; ok {
+			start, _ = strconv.Atoi(*v.N)
+			start++
+		}
+
+		res := &QueryResult{}
+		for i := start; i < start+2 && i < total; i++ {
+			res.Items = append(res.Items, map[string]*SDK.AttributeValue{
+				"id": {N: pointers.String(strconv.Itoa(i))},
+			})
+		}
+		res.Count = int64(len(res.Items))
+		if start+2 < total {
+			res.LastEvaluatedKey = map[string]*SDK.AttributeValue{
+				"id": {N: pointers.String(strconv.Itoa(start + 1))},
+			}
+		}
+		return res, nil
+	}
+}
+
+func TestIteratorNext(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	it := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(5, &calls))
+	assert.Nil(it.Item())
+
+	var ids []int
+	for it.Next() {
+		ids = append(ids, it.Item()["id"].(int))
+	}
+	assert.NoError(it.Err())
+	assert.Equal([]int{0, 1, 2, 3, 4}, ids)
+	assert.Equal(3, calls)
+	assert.False(it.Next())
+	assert.Equal(3, calls)
+
+	cursor, err := it.Cursor()
+	assert.NoError(err)
+	assert.Equal("", cursor)
+}
+
+func TestIteratorAll(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	list, err := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(4, &calls)).All()
+	assert.NoError(err)
+	assert.Len(list, 4)
+	assert.Equal(2, calls)
+
+	calls = 0
+	list, err = newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(0, &calls)).All()
+	assert.NoError(err)
+	assert.Len(list, 0)
+	assert.Equal(1, calls)
+}
+
+func TestIteratorForEach(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	count := 0
+	errStop := errors.New("stop")
+	err := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(10, &calls)).ForEach(func(item map[string]interface{}) error {
+		count++
+		if item["id"] == 2 {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(errStop, err)
+	assert.Equal(3, count)
+	assert.Equal(2, calls)
+
+	errFetch := errors.New("fetch error")
+	it := newIterator(NewConditionList(nil), nil, func(aws.Context, *ConditionList) (*QueryResult, error) {
+		return nil, errFetch
+	})
+	assert.Equal(errFetch, it.ForEach(func(map[string]interface{}) error { return nil }))
+}
+
+func TestIteratorContext(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	it := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(10, &calls))
+	it.SetContext(ctx)
+
+	assert.True(it.Next())
+	assert.True(it.Next())
+	cancel()
+	assert.False(it.Next())
+	assert.Equal(context.Canceled, it.Err())
+	assert.Equal(1, calls)
+}
+
+func TestIteratorCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	it := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(5, &calls))
+	assert.True(it.NextPage())
+	assert.Len(it.Page().Items, 2)
+
+	cursor, err := it.Cursor()
+	assert.NoError(err)
+	assert.NotEmpty(cursor)
+
+	it2 := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(5, &calls))
+	assert.NoError(it2.SetCursor(cursor))
+	list, err := it2.All()
+	assert.NoError(err)
+	assert.Len(list, 3)
+	assert.Equal(2, list[0]["id"])
+
+	assert.Error(it2.SetCursor("!!invalid!!"))
+}
+
+func TestIteratorCursorMiddleOfPage(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	it := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(5, &calls))
+	assert.True(it.Next())
+	assert.Equal(0, it.Item()["id"])
+
+	cursor, err := it.Cursor()
+	assert.NoError(err)
+	it2 := newIterator(NewConditionList(nil), []string{"id"}, newTestPageFetcher(5, &calls))
+	assert.NoError(it2.SetCursor(cursor))
+	list, err := it2.All()
+	assert.NoError(err)
+	assert.Len(list, 4, "the rest of the page should not be lost")
+	assert.Equal(1, list[0]["id"])
+
+	cursor, err = it2.Cursor()
+	assert.NoError(err)
+	assert.Equal("", cursor)
+
+	noKey := newIterator(NewConditionList(nil), nil, newTestPageFetcher(5, &calls))
+	assert.True(noKey.Next())
+	_, err = noKey.Cursor()
+	assert.Error(err)
+}
+
+func TestQueryIterCursorWithIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getMemoryTestTable(t)
+	for i := 1; i <= 7; i++ {
+		putMemoryTestItem(tbl, i, i*10, "a", "")
+	}
+	assert.NoError(tbl.Put())
+
+	cond := tbl.NewConditionList()
+	cond.SetIndex("gsi-index")
+	cond.AndEQ("group", "a")
+	cond.SetLimit(3)
+	it := tbl.QueryIter(cond)
+	assert.True(it.Next())
+	assert.True(it.Next())
+
+	cursor, err := it.Cursor()
+	assert.NoError(err)
+	key, err := DecodeCursor(cursor)
+	assert.NoError(err)
+	assert.Len(key, 3, "cursor should have the keys of the table and the index")
+
+	cond = tbl.NewConditionList()
+	cond.SetIndex("gsi-index")
+	cond.AndEQ("group", "a")
+	cond.SetLimit(3)
+	it = tbl.QueryIter(cond)
+	assert.NoError(it.SetCursor(cursor))
+	list, err := it.All()
+	assert.NoError(err)
+	assert.Len(list, 5)
+	assert.Equal(3, list[0]["id"])
+}
+
+func TestEncodeCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	key := map[string]*SDK.AttributeValue{
+		"id":   {N: pointers.String("100")},
+		"name": {S: pointers.String("foo")},
+	}
+	token, err := EncodeCursor(key)
+	assert.NoError(err)
+
+	decoded, err := DecodeCursor(token)
+	assert.NoError(err)
+	assert.Equal(key, decoded)
+
+	token, err = EncodeCursor(nil)
+	assert.NoError(err)
+	assert.Equal("", token)
+	decoded, err = DecodeCursor("")
+	assert.NoError(err)
+	assert.Nil(decoded)
+}
+
+func TestScanIter(t *testing.T) {
+	assert := assert.New(t)
+	resetTestTable(t)
+	tbl := getTestTable(t)
+	for i := 0; i < 25; i++ {
+		putTestTable(tbl, 100, i)
+	}
+
+	cond := tbl.NewConditionList()
+	cond.SetLimit(10)
+	list, err := tbl.ScanIter(cond).All()
+	assert.NoError(err)
+	assert.Len(list, 25)
+
+	cond = tbl.NewConditionList()
+	cond.AndEQ("id", 100)
+	cond.SetLimit(10)
+	it := tbl.QueryIter(cond)
+	assert.True(it.NextPage())
+	assert.Len(it.Page().Items, 10)
+	cursor, err := it.Cursor()
+	assert.NoError(err)
+	assert.NotEmpty(cursor)
+	list, err = it.All()
+	assert.NoError(err)
+	assert.Len(list, 25)
+}
@@ -13,6 +13,7 @@ type QueryResult struct {
 	LastEvaluatedKey map[string]*SDK.AttributeValue
 	Count            int64
 	ScannedCount     int64
+	ConsumedCapacity ConsumedCapacity
 }
 
 // ToSliceMap converts result to slice of map.
@@ -0,0 +1,30 @@
+package dynamodb
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBatchMaxRetry = 8
+
+	retryBaseInterval = 50 * time.Millisecond
+	retryMaxInterval  = 5 * time.Second
+)
+
+// backoffInterval returns wait duration for the n-th retry.
+// It uses exponential backoff with full jitter.
+func backoffInterval(retry int) time.Duration {
+	max := retryMaxInterval
+	if retry < 16 {
+		if d := retryBaseInterval << uint(retry); d < max {
+			max = d
+		}
+	}
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+// waitBackoff sleeps for the n-th retry.
+func waitBackoff(retry int) {
+	time.Sleep(backoffInterval(retry))
+}
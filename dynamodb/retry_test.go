@@ -0,0 +1,123 @@
+package dynamodb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		retry int
+		max   time.Duration
+	}{
+		{0, 50 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{6, 3200 * time.Millisecond},
+		{7, retryMaxInterval},
+		{100, retryMaxInterval},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			d := backoffInterval(tt.retry)
+			assert.True(d >= 0, "retry=%d", tt.retry)
+			assert.True(d <= tt.max, "retry=%d", tt.retry)
+		}
+	}
+}
+
+// testUnprocessedWriter is a BatchWriteItem endpoint which returns the items of even id
+// as UnprocessedItems on the first failCalls requests.
+type testUnprocessedWriter struct {
+	calls     int
+	failCalls int
+}
+
+// newClient returns *DynamoDB which sends the requests to the writer.
+func (w *testUnprocessedWriter) newClient(t *testing.T) (*DynamoDB, func()) {
+	server := httptest.NewServer(w)
+	conf := getTestConfig()
+	conf.Endpoint = server.URL
+	svc, err := New(conf)
+	if err != nil {
+		server.Close()
+		t.Errorf("error on create client; error=%s;", err.Error())
+		t.FailNow()
+	}
+	return svc, server.Close
+}
+
+func (w *testUnprocessedWriter) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	in := &SDK.BatchWriteItemInput{}
+	if err := jsonutil.UnmarshalJSON(in, r.Body); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.calls++
+
+	unprocessed := make(map[string][]*SDK.WriteRequest)
+	for name, requests := range in.RequestItems {
+		for _, wr := range requests {
+			id, _ := strconv.Atoi(*wr.PutRequest.Item["id"].N)
+			if w.calls <= w.failCalls && id%2 == 0 {
+				unprocessed[name] = append(unprocessed[name], wr)
+			}
+		}
+	}
+	body, err := jsonutil.BuildJSON(&SDK.BatchWriteItemOutput{UnprocessedItems: unprocessed})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	rw.Write(body)
+}
+
+func TestBatchPutRetryUnprocessedItems(t *testing.T) {
+	assert := assert.New(t)
+
+	writer := &testUnprocessedWriter{failCalls: 2}
+	svc, closeServer := writer.newClient(t)
+	defer closeServer()
+	svc.SetBatchMaxRetry(3)
+	tbl, err := NewTableWithDesign(svc, NewTableDesignWithHashKeyN("foo_table", "id"))
+	assert.NoError(err)
+
+	for i := 1; i <= 10; i++ {
+		item := NewPutItem()
+		item.AddAttribute("id", i)
+		tbl.AddItem(item)
+	}
+	assert.NoError(tbl.BatchPut())
+	assert.Equal(3, writer.calls, "unprocessed items should be retried until processed")
+	assert.Len(tbl.GetErrorItems(), 0)
+
+	// unprocessed items remain after the retries.
+	writer.calls = 0
+	writer.failCalls = 100
+	svc.SetBatchMaxRetry(2)
+	for i := 11; i <= 20; i++ {
+		item := NewPutItem()
+		item.AddAttribute("id", i)
+		tbl.AddItem(item)
+	}
+	assert.Error(tbl.BatchPut())
+	assert.Equal(3, writer.calls, "the first request and the max retries")
+
+	list := tbl.GetErrorItems()
+	assert.Len(list, 5)
+	for _, item := range list {
+		assert.Equal("foo_table", *item.TableName)
+		id, _ := strconv.Atoi(*item.Item["id"].N)
+		assert.Equal(0, id%2)
+	}
+}
@@ -39,3 +39,16 @@ func NewHashKeyElement(keyName string) *SDK.KeySchemaElement {
 func NewRangeKeyElement(keyName string) *SDK.KeySchemaElement {
 	return NewKeyElement(keyName, KeyTypeRange)
 }
+
+// keySchemaNames returns the attribute names of HashKey and RangeKey in the key schema.
+func keySchemaNames(schema []*SDK.KeySchemaElement) (hashKey, rangeKey string) {
+	for _, k := range schema {
+		switch *k.KeyType {
+		case KeyTypeHash:
+			hashKey = *k.AttributeName
+		case KeyTypeRange:
+			rangeKey = *k.AttributeName
+		}
+	}
+	return hashKey, rangeKey
+}
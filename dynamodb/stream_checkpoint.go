@@ -0,0 +1,109 @@
+package dynamodb
+
+import (
+	"sync"
+	"time"
+)
+
+// CheckpointShardEnd is the checkpoint of the closed shard which all of the records are processed.
+const CheckpointShardEnd = "SHARD_END"
+
+const (
+	checkpointKeyName   = "checkpoint_key"
+	checkpointShardName = "shard_id"
+)
+
+// checkpointItem is an item of the checkpoint table.
+type checkpointItem struct {
+	Key            string `dynamodb:"checkpoint_key"`
+	ShardID        string `dynamodb:"shard_id"`
+	SequenceNumber string `dynamodb:"sequence_number"`
+	UpdatedAt      int64  `dynamodb:"updated_at"`
+}
+
+// CheckpointStore saves the processed position of the shards of DynamoDB Streams.
+type CheckpointStore interface {
+	// GetCheckpoint returns the last processed sequence number of the shard.
+	// It returns empty string when the shard has not been processed yet.
+	GetCheckpoint(streamARN, shardID string) (string, error)
+	// SetCheckpoint saves the last processed sequence number of the shard.
+	SetCheckpoint(streamARN, shardID, sequenceNumber string) error
+}
+
+// MemoryCheckpointStore is CheckpointStore on memory.
+type MemoryCheckpointStore struct {
+	mu   sync.RWMutex
+	data map[string]string
+}
+
+// NewMemoryCheckpointStore returns initialized *MemoryCheckpointStore.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		data: make(map[string]string),
+	}
+}
+
+// GetCheckpoint returns the last processed sequence number of the shard.
+func (s *MemoryCheckpointStore) GetCheckpoint(streamARN, shardID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data[streamARN+"/"+shardID], nil
+}
+
+// SetCheckpoint saves the last processed sequence number of the shard.
+func (s *MemoryCheckpointStore) SetCheckpoint(streamARN, shardID, sequenceNumber string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[streamARN+"/"+shardID] = sequenceNumber
+	return nil
+}
+
+// TableCheckpointStore is CheckpointStore on DynamoDB table.
+// Multiple consumers can share the table with the different consumer names.
+type TableCheckpointStore struct {
+	table    *Table
+	consumer string
+}
+
+// NewTableCheckpointStore returns initialized *TableCheckpointStore.
+// The checkpoint table is created with PAY_PER_REQUEST when it does not exist.
+func (svc *DynamoDB) NewTableCheckpointStore(tableName, consumer string) (*TableCheckpointStore, error) {
+	design := NewTableDesignWithHashKeyS(tableName, checkpointKeyName)
+	design.AddRangeKeyS(checkpointShardName)
+	design.SetBillingMode(BillingModePayPerRequest)
+	if _, err := svc.EnsureTable(design); err != nil {
+		return nil, err
+	}
+
+	tbl, err := svc.GetTable(tableName)
+	if err != nil {
+		return nil, err
+	}
+	return &TableCheckpointStore{
+		table:    tbl,
+		consumer: consumer,
+	}, nil
+}
+
+// GetCheckpoint returns the last processed sequence number of the shard.
+func (s *TableCheckpointStore) GetCheckpoint(streamARN, shardID string) (string, error) {
+	item := checkpointItem{}
+	if _, err := s.table.GetOneInto(&item, s.checkpointKey(streamARN), shardID); err != nil {
+		return "", err
+	}
+	return item.SequenceNumber, nil
+}
+
+// SetCheckpoint saves the last processed sequence number of the shard.
+func (s *TableCheckpointStore) SetCheckpoint(streamARN, shardID, sequenceNumber string) error {
+	return s.table.PutStruct(checkpointItem{
+		Key:            s.checkpointKey(streamARN),
+		ShardID:        shardID,
+		SequenceNumber: sequenceNumber,
+		UpdatedAt:      time.Now().Unix(),
+	})
+}
+
+func (s *TableCheckpointStore) checkpointKey(streamARN string) string {
+	return s.consumer + "@" + streamARN
+}
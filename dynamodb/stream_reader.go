@@ -0,0 +1,278 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDKStreams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const defaultStreamPollInterval = time.Second
+
+// StreamHandler handles a record of DynamoDB Streams.
+// When it returns error, the reader stops and the record is processed again on the next run.
+type StreamHandler func(StreamRecord) error
+
+// StreamReader reads records of DynamoDB Streams.
+// It follows the lineage of the shards, and the records of the parent shard are handled before the child shards.
+type StreamReader struct {
+	service   *DynamoDB
+	streamARN string
+
+	checkpoint   CheckpointStore
+	iteratorType string
+	limit        int64
+	pollInterval time.Duration
+
+	// shard id => next shard iterator
+	iterators map[string]string
+}
+
+// NewStreamReader returns initialized *StreamReader for the latest stream of the table.
+// The table prefix is added to tableName.
+func (svc *DynamoDB) NewStreamReader(tableName string) (*StreamReader, error) {
+	desc, err := svc.DescribeTable(svc.prefix + tableName)
+	if err != nil {
+		return nil, err
+	}
+	if desc.LatestStreamARN == "" {
+		err := fmt.Errorf("stream is not enabled on the table; table=%s;", svc.prefix+tableName)
+		svc.Errorf("error on `NewStreamReader`; error=%s;", err.Error())
+		return nil, err
+	}
+	return svc.NewStreamReaderWithARN(desc.LatestStreamARN)
+}
+
+// NewStreamReaderWithARN returns initialized *StreamReader for the stream.
+func (svc *DynamoDB) NewStreamReaderWithARN(streamARN string) (*StreamReader, error) {
+	if svc.streamsClient == nil {
+		err := fmt.Errorf("streams client is not set; use SetStreamsClient()")
+		svc.Errorf("error on `NewStreamReader`; error=%s;", err.Error())
+		return nil, err
+	}
+	return &StreamReader{
+		service:      svc,
+		streamARN:    streamARN,
+		checkpoint:   NewMemoryCheckpointStore(),
+		iteratorType: SDKStreams.ShardIteratorTypeTrimHorizon,
+		pollInterval: defaultStreamPollInterval,
+		iterators:    make(map[string]string),
+	}, nil
+}
+
+// GetStreamARN returns ARN of the stream.
+func (r *StreamReader) GetStreamARN() string {
+	return r.streamARN
+}
+
+// SetCheckpointStore sets the store of the checkpoints. (default: MemoryCheckpointStore)
+func (r *StreamReader) SetCheckpointStore(store CheckpointStore) {
+	r.checkpoint = store
+}
+
+// SetStartFromLatest makes the reader start from the latest record on the shard without checkpoint.
+// The reader starts from the oldest record by default.
+// The child shards are always read from the oldest record to avoid losing records.
+func (r *StreamReader) SetStartFromLatest(b bool) {
+	r.iteratorType = SDKStreams.ShardIteratorTypeTrimHorizon
+	if b {
+		r.iteratorType = SDKStreams.ShardIteratorTypeLatest
+	}
+}
+
+// SetLimit sets the max number of records on each GetRecords operation.
+func (r *StreamReader) SetLimit(limit int64) {
+	r.limit = limit
+}
+
+// SetPollInterval sets the interval to poll new records on Run.
+func (r *StreamReader) SetPollInterval(d time.Duration) {
+	r.pollInterval = d
+}
+
+// Run polls the stream and handles the records until the context is done or the handler returns error.
+func (r *StreamReader) Run(ctx context.Context, handler StreamHandler) error {
+	for {
+		if _, err := r.Poll(handler); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+// Poll reads the records on the available shards once, and returns the number of the handled records.
+// The shard is available when the parent shard does not exist or is already processed.
+func (r *StreamReader) Poll(handler StreamHandler) (int, error) {
+	shards, err := r.listShards()
+	if err != nil {
+		return 0, err
+	}
+
+	// get checkpoints of all of the shards to check the parent shards are processed.
+	checkpoints := make(map[string]string, len(shards))
+	for _, s := range shards {
+		seq, err := r.checkpoint.GetCheckpoint(r.streamARN, *s.ShardId)
+		if err != nil {
+			r.service.Errorf("error on `GetCheckpoint`; stream=%s; shard=%s; error=%s;", r.streamARN, *s.ShardId, err.Error())
+			return 0, err
+		}
+		checkpoints[*s.ShardId] = seq
+	}
+
+	total := 0
+	for _, s := range shards {
+		shardID := *s.ShardId
+		if checkpoints[shardID] == CheckpointShardEnd {
+			continue
+		}
+		parentID := stringValue(s.ParentShardId)
+		if seq, ok := checkpoints[parentID]; ok && seq != CheckpointShardEnd {
+			continue
+		}
+
+		iteratorType := r.iteratorType
+		if _, ok := checkpoints[parentID]; ok {
+			iteratorType = SDKStreams.ShardIteratorTypeTrimHorizon
+		}
+		n, isEnded, err := r.readShard(shardID, checkpoints[shardID], iteratorType, handler)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if isEnded {
+			// the child shards can be read on this poll.
+			checkpoints[shardID] = CheckpointShardEnd
+		}
+	}
+	return total, nil
+}
+
+// listShards returns all of the shards on the stream.
+func (r *StreamReader) listShards() ([]*SDKStreams.Shard, error) {
+	var shards []*SDKStreams.Shard
+	in := &SDKStreams.DescribeStreamInput{
+		StreamArn: pointers.String(r.streamARN),
+	}
+	for {
+		out, err := r.service.streamsClient.DescribeStream(in)
+		if err != nil {
+			r.service.Errorf("error on `DescribeStream` operation; stream=%s; error=%s;", r.streamARN, err.Error())
+			return nil, err
+		}
+		if out.StreamDescription == nil {
+			return shards, nil
+		}
+
+		shards = append(shards, out.StreamDescription.Shards...)
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		in.ExclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// readShard reads the records on the shard until it reaches the latest record or the end of the closed shard.
+func (r *StreamReader) readShard(shardID, checkpoint, iteratorType string, handler StreamHandler) (total int, isEnded bool, err error) {
+	iterator, ok := r.iterators[shardID]
+	if !ok {
+		iterator, err = r.getShardIterator(shardID, checkpoint, iteratorType)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+
+	for iterator != "" {
+		in := &SDKStreams.GetRecordsInput{
+			ShardIterator: pointers.String(iterator),
+		}
+		if r.limit > 0 {
+			in.Limit = pointers.Long64(r.limit)
+		}
+
+		out, err := r.service.streamsClient.GetRecords(in)
+		if isExpiredIteratorError(err) {
+			// the iterator expires in 15 minutes.
+			delete(r.iterators, shardID)
+			checkpoint, err = r.checkpoint.GetCheckpoint(r.streamARN, shardID)
+			if err != nil {
+				return total, false, err
+			}
+			if iterator, err = r.getShardIterator(shardID, checkpoint, iteratorType); err != nil {
+				return total, false, err
+			}
+			continue
+		}
+		if err != nil {
+			r.service.Errorf("error on `GetRecords` operation; stream=%s; shard=%s; error=%s;", r.streamARN, shardID, err.Error())
+			return total, false, err
+		}
+
+		for _, record := range out.Records {
+			v := newStreamRecord(shardID, record)
+			if err := handler(v); err != nil {
+				delete(r.iterators, shardID)
+				return total, false, err
+			}
+			total++
+			if err := r.setCheckpoint(shardID, v.SequenceNumber); err != nil {
+				delete(r.iterators, shardID)
+				return total, false, err
+			}
+		}
+
+		next := stringValue(out.NextShardIterator)
+		switch {
+		case next == "":
+			// reached the end of the closed shard.
+			delete(r.iterators, shardID)
+			return total, true, r.setCheckpoint(shardID, CheckpointShardEnd)
+		case len(out.Records) == 0:
+			// reached the latest record of the open shard.
+			r.iterators[shardID] = next
+			return total, false, nil
+		}
+		iterator = next
+	}
+	return total, false, nil
+}
+
+func (r *StreamReader) getShardIterator(shardID, checkpoint, iteratorType string) (string, error) {
+	in := &SDKStreams.GetShardIteratorInput{
+		StreamArn:         pointers.String(r.streamARN),
+		ShardId:           pointers.String(shardID),
+		ShardIteratorType: pointers.String(iteratorType),
+	}
+	if checkpoint != "" {
+		in.ShardIteratorType = pointers.String(SDKStreams.ShardIteratorTypeAfterSequenceNumber)
+		in.SequenceNumber = pointers.String(checkpoint)
+	}
+
+	out, err := r.service.streamsClient.GetShardIterator(in)
+	if err != nil {
+		r.service.Errorf("error on `GetShardIterator` operation; stream=%s; shard=%s; error=%s;", r.streamARN, shardID, err.Error())
+		return "", err
+	}
+	return stringValue(out.ShardIterator), nil
+}
+
+func (r *StreamReader) setCheckpoint(shardID, sequenceNumber string) error {
+	err := r.checkpoint.SetCheckpoint(r.streamARN, shardID, sequenceNumber)
+	if err != nil {
+		r.service.Errorf("error on `SetCheckpoint`; stream=%s; shard=%s; error=%s;", r.streamARN, shardID, err.Error())
+	}
+	return err
+}
+
+func isExpiredIteratorError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == SDKStreams.ErrCodeExpiredIteratorException
+}
@@ -0,0 +1,286 @@
+package dynamodb
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	SDKStreams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const testStreamARN = "arn:aws:dynamodb:local:000000000000:table/stream_table/stream/2020-01-01T00:00:00.000"
+
+// testStreamsClient is a fake DynamoDB Streams API.
+type testStreamsClient struct {
+	dynamodbstreamsiface.DynamoDBStreamsAPI
+
+	shards  []*SDKStreams.Shard
+	records map[string][]*SDKStreams.Record
+	closed  map[string]bool
+	seq     int
+}
+
+func newTestStreamsClient() *testStreamsClient {
+	return &testStreamsClient{
+		records: make(map[string][]*SDKStreams.Record),
+		closed:  make(map[string]bool),
+	}
+}
+
+func (c *testStreamsClient) addShard(shardID, parentID string) {
+	s := &SDKStreams.Shard{ShardId: pointers.String(shardID)}
+	if parentID != "" {
+		s.ParentShardId = pointers.String(parentID)
+	}
+	c.shards = append(c.shards, s)
+}
+
+func (c *testStreamsClient) addRecord(shardID, eventName string, id int, newImage, oldImage map[string]interface{}) {
+	c.seq++
+	r := &SDKStreams.Record{
+		EventID:   pointers.String(fmt.Sprintf("event-%d", c.seq)),
+		EventName: pointers.String(eventName),
+		Dynamodb: &SDKStreams.StreamRecord{
+			SequenceNumber: pointers.String(fmt.Sprintf("%021d", c.seq)),
+			StreamViewType: pointers.String(StreamViewTypeNewAndOldImages),
+			Keys:           Marshal(map[string]interface{}{"id": id}),
+		},
+	}
+	if newImage != nil {
+		r.Dynamodb.NewImage = Marshal(newImage)
+	}
+	if oldImage != nil {
+		r.Dynamodb.OldImage = Marshal(oldImage)
+	}
+	c.records[shardID] = append(c.records[shardID], r)
+}
+
+func (c *testStreamsClient) DescribeStream(in *SDKStreams.DescribeStreamInput) (*SDKStreams.DescribeStreamOutput, error) {
+	// returns a shard per page to test paging.
+	pos := 0
+	if in.ExclusiveStartShardId != nil {
+		for i, s := range c.shards {
+			if *s.ShardId == *in.ExclusiveStartShardId {
+				pos = i + 1
+			}
+		}
+	}
+	desc := &SDKStreams.StreamDescription{
+		StreamArn: in.StreamArn,
+		Shards:    c.shards[pos : pos+1],
+	}
+	if pos+1 < len(c.shards) {
+		desc.LastEvaluatedShardId = c.shards[pos].ShardId
+	}
+	return &SDKStreams.DescribeStreamOutput{StreamDescription: desc}, nil
+}
+
+func (c *testStreamsClient) GetShardIterator(in *SDKStreams.GetShardIteratorInput) (*SDKStreams.GetShardIteratorOutput, error) {
+	records := c.records[*in.ShardId]
+	pos := 0
+	switch *in.ShardIteratorType {
+	case SDKStreams.ShardIteratorTypeLatest:
+		pos = len(records)
+	case SDKStreams.ShardIteratorTypeAfterSequenceNumber:
+		for i, r := range records {
+			if *r.Dynamodb.SequenceNumber == *in.SequenceNumber {
+				pos = i + 1
+			}
+		}
+	}
+	return &SDKStreams.GetShardIteratorOutput{
+		ShardIterator: pointers.String(fmt.Sprintf("%s:%d", *in.ShardId, pos)),
+	}, nil
+}
+
+func (c *testStreamsClient) GetRecords(in *SDKStreams.GetRecordsInput) (*SDKStreams.GetRecordsOutput, error) {
+	parts := strings.Split(*in.ShardIterator, ":")
+	shardID := parts[0]
+	pos, _ := strconv.Atoi(parts[1])
+
+	records := c.records[shardID][pos:]
+	if in.Limit != nil && int64(len(records)) > *in.Limit {
+		records = records[:*in.Limit]
+	}
+	pos += len(records)
+
+	out := &SDKStreams.GetRecordsOutput{Records: records}
+	if !c.closed[shardID] || pos < len(c.records[shardID]) {
+		out.NextShardIterator = pointers.String(fmt.Sprintf("%s:%d", shardID, pos))
+	}
+	return out, nil
+}
+
+func getTestStreamReader(t *testing.T, client *testStreamsClient) *StreamReader {
+	svc := NewInMemory()
+	svc.SetStreamsClient(client)
+	r, err := svc.NewStreamReaderWithARN(testStreamARN)
+	if err != nil {
+		t.Fatalf("error on NewStreamReaderWithARN; error=%s;", err.Error())
+	}
+	return r
+}
+
+func TestStreamReaderPoll(t *testing.T) {
+	assert := assert.New(t)
+
+	// the parent shard is closed, and the child shard is listed before the parent.
+	client := newTestStreamsClient()
+	client.addShard("child", "parent")
+	client.addShard("parent", "trimmed")
+	client.addShard("other", "")
+	for i := 1; i <= 3; i++ {
+		client.addRecord("parent", StreamEventInsert, i, map[string]interface{}{"id": i}, nil)
+	}
+	client.closed["parent"] = true
+	client.addRecord("child", StreamEventModify, 1, map[string]interface{}{"id": 1, "v": 2}, map[string]interface{}{"id": 1})
+	client.addRecord("other", StreamEventRemove, 9, nil, map[string]interface{}{"id": 9})
+
+	r := getTestStreamReader(t, client)
+	r.SetLimit(2)
+
+	var handled []string
+	handler := func(record StreamRecord) error {
+		handled = append(handled, record.ShardID)
+		return nil
+	}
+	n, err := r.Poll(handler)
+	assert.NoError(err)
+	assert.Equal(4, n)
+	assert.Equal([]string{"parent", "parent", "parent", "other"}, handled)
+
+	seq, _ := r.checkpoint.GetCheckpoint(testStreamARN, "parent")
+	assert.Equal(CheckpointShardEnd, seq)
+
+	// the child shard is read after the parent shard is processed.
+	handled = nil
+	n, err = r.Poll(handler)
+	assert.NoError(err)
+	assert.Equal(1, n)
+	assert.Equal([]string{"child"}, handled)
+
+	client.addRecord("child", StreamEventInsert, 2, map[string]interface{}{"id": 2}, nil)
+	n, err = r.Poll(handler)
+	assert.NoError(err)
+	assert.Equal(1, n)
+
+	n, err = r.Poll(handler)
+	assert.NoError(err)
+	assert.Equal(0, n)
+}
+
+func TestStreamReaderCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	client := newTestStreamsClient()
+	client.addShard("shard", "")
+	for i := 1; i <= 3; i++ {
+		client.addRecord("shard", StreamEventInsert, i, map[string]interface{}{"id": i}, nil)
+	}
+
+	r := getTestStreamReader(t, client)
+	store, err := r.service.NewTableCheckpointStore("stream_checkpoint", "consumer")
+	assert.NoError(err)
+	r.SetCheckpointStore(store)
+
+	// the failed record is handled again on the next run.
+	var ids []int
+	n, err := r.Poll(func(record StreamRecord) error {
+		id := record.KeysMap()["id"].(int)
+		if id == 2 {
+			return errors.New("handler error")
+		}
+		ids = append(ids, id)
+		return nil
+	})
+	assert.Error(err)
+	assert.Equal(1, n)
+
+	seq, err := store.GetCheckpoint(testStreamARN, "shard")
+	assert.NoError(err)
+	assert.Equal(fmt.Sprintf("%021d", 1), seq)
+
+	other, err := r.service.NewTableCheckpointStore("stream_checkpoint", "other_consumer")
+	assert.NoError(err)
+	seq, err = other.GetCheckpoint(testStreamARN, "shard")
+	assert.NoError(err)
+	assert.Empty(seq, "checkpoint should be separated by consumer")
+
+	r2 := getTestStreamReader(t, client)
+	r2.SetCheckpointStore(store)
+	n, err = r2.Poll(func(record StreamRecord) error {
+		ids = append(ids, record.KeysMap()["id"].(int))
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal([]int{1, 2, 3}, ids)
+
+	// LATEST skips the existing records.
+	r3 := getTestStreamReader(t, client)
+	r3.SetStartFromLatest(true)
+	n, err = r3.Poll(func(StreamRecord) error { return nil })
+	assert.NoError(err)
+	assert.Equal(0, n)
+}
+
+func TestStreamRecord(t *testing.T) {
+	assert := assert.New(t)
+
+	client := newTestStreamsClient()
+	client.addRecord("shard", StreamEventModify, 1,
+		map[string]interface{}{"id": 1, "name": "new"},
+		map[string]interface{}{"id": 1, "name": "old"})
+	client.addRecord("shard", StreamEventInsert, 2, map[string]interface{}{"id": 2}, nil)
+
+	record := newStreamRecord("shard", client.records["shard"][0])
+	assert.True(record.IsModify())
+	assert.Equal("shard", record.ShardID)
+	assert.Equal(map[string]interface{}{"id": 1}, record.KeysMap())
+	assert.Equal("new", record.NewImageMap()["name"])
+	assert.Equal("old", record.OldImageMap()["name"])
+
+	v := struct {
+		ID   int    `dynamodb:"id"`
+		Name string `dynamodb:"name"`
+	}{}
+	ok, err := record.UnmarshalOldImage(&v)
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal(1, v.ID)
+	assert.Equal("old", v.Name)
+
+	record = newStreamRecord("shard", client.records["shard"][1])
+	assert.True(record.IsInsert())
+	assert.Nil(record.OldImageMap())
+	ok, err = record.UnmarshalOldImage(&v)
+	assert.NoError(err)
+	assert.False(ok)
+}
+
+func TestNewStreamReader(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := NewInMemory()
+	design := NewTableDesignWithHashKeyN("stream_table", "id")
+	assert.NoError(svc.CreateTable(design))
+	_, err := svc.NewStreamReader("stream_table")
+	assert.Error(err, "stream is not enabled")
+
+	design.SetStream(StreamViewTypeNewImage)
+	_, err = svc.EnsureTable(design)
+	assert.NoError(err)
+	_, err = svc.NewStreamReader("stream_table")
+	assert.Error(err, "streams client is not set")
+
+	svc.SetStreamsClient(newTestStreamsClient())
+	r, err := svc.NewStreamReader("stream_table")
+	assert.NoError(err)
+	assert.Contains(r.GetStreamARN(), "table/stream_table/stream/")
+}
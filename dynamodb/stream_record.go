@@ -0,0 +1,128 @@
+package dynamodb
+
+import (
+	"time"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	SDKStreams "github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+// event names of the stream record.
+const (
+	StreamEventInsert = SDKStreams.OperationTypeInsert
+	StreamEventModify = SDKStreams.OperationTypeModify
+	StreamEventRemove = SDKStreams.OperationTypeRemove
+)
+
+// StreamRecord is a record of DynamoDB Streams.
+type StreamRecord struct {
+	ShardID        string
+	EventID        string
+	EventName      string
+	SequenceNumber string
+	StreamViewType string
+	SizeBytes      int64
+	CreatedAt      time.Time
+
+	Keys     map[string]*SDK.AttributeValue
+	NewImage map[string]*SDK.AttributeValue
+	OldImage map[string]*SDK.AttributeValue
+}
+
+// newStreamRecord creates StreamRecord from SDK's record.
+func newStreamRecord(shardID string, r *SDKStreams.Record) StreamRecord {
+	v := StreamRecord{
+		ShardID: shardID,
+	}
+	if r.EventID != nil {
+		v.EventID = *r.EventID
+	}
+	if r.EventName != nil {
+		v.EventName = *r.EventName
+	}
+
+	d := r.Dynamodb
+	if d == nil {
+		return v
+	}
+	if d.SequenceNumber != nil {
+		v.SequenceNumber = *d.SequenceNumber
+	}
+	if d.StreamViewType != nil {
+		v.StreamViewType = *d.StreamViewType
+	}
+	if d.SizeBytes != nil {
+		v.SizeBytes = *d.SizeBytes
+	}
+	if d.ApproximateCreationDateTime != nil {
+		v.CreatedAt = *d.ApproximateCreationDateTime
+	}
+	v.Keys = d.Keys
+	v.NewImage = d.NewImage
+	v.OldImage = d.OldImage
+	return v
+}
+
+// IsInsert checks if the record is created by a new item.
+func (r StreamRecord) IsInsert() bool {
+	return r.EventName == StreamEventInsert
+}
+
+// IsModify checks if the record is created by an updated item.
+func (r StreamRecord) IsModify() bool {
+	return r.EventName == StreamEventModify
+}
+
+// IsRemove checks if the record is created by a deleted item.
+func (r StreamRecord) IsRemove() bool {
+	return r.EventName == StreamEventRemove
+}
+
+// KeysMap returns the primary key attributes of the item as map.
+func (r StreamRecord) KeysMap() map[string]interface{} {
+	return UnmarshalAttributeValue(r.Keys)
+}
+
+// NewImageMap returns the item after it was modified as map.
+// It returns nil when the stream view type does not contain NEW_IMAGE or the item was removed.
+func (r StreamRecord) NewImageMap() map[string]interface{} {
+	if r.NewImage == nil {
+		return nil
+	}
+	return UnmarshalAttributeValue(r.NewImage)
+}
+
+// OldImageMap returns the item before it was modified as map.
+// It returns nil when the stream view type does not contain OLD_IMAGE or the item was inserted.
+func (r StreamRecord) OldImageMap() map[string]interface{} {
+	if r.OldImage == nil {
+		return nil
+	}
+	return UnmarshalAttributeValue(r.OldImage)
+}
+
+// UnmarshalNewImage unmarshals the item after it was modified into the struct.
+// It returns false when the record does not have the image.
+func (r StreamRecord) UnmarshalNewImage(v interface{}) (bool, error) {
+	return unmarshalStreamImage(r.NewImage, v)
+}
+
+// UnmarshalOldImage unmarshals the item before it was modified into the struct.
+// It returns false when the record does not have the image.
+func (r StreamRecord) UnmarshalOldImage(v interface{}) (bool, error) {
+	return unmarshalStreamImage(r.OldImage, v)
+}
+
+func unmarshalStreamImage(image map[string]*SDK.AttributeValue, v interface{}) (bool, error) {
+	if image == nil {
+		return false, nil
+	}
+
+	decoder := dynamodbattribute.NewDecoder()
+	decoder.TagKey = defaultResultTag
+	if err := decoder.Decode(&SDK.AttributeValue{M: image}, v); err != nil {
+		return true, err
+	}
+	return true, nil
+}
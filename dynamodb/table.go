@@ -3,8 +3,11 @@ package dynamodb
 import (
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/aws/aws-sdk-go/aws"
 	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 
 	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
 )
@@ -20,6 +23,11 @@ type Table struct {
 
 	putSpool   []*SDK.PutItemInput
 	errorItems []*SDK.PutItemInput
+	// put items with the version condition
+	versionedItems map[*SDK.PutItemInput]struct{}
+
+	cacheMu sync.RWMutex
+	cache   *ReadCache
 }
 
 // ---------------------------------
@@ -82,7 +90,12 @@ func (t *Table) RefreshDesign() (*TableDesign, error) {
 		return nil, err
 	}
 
-	t.design = newTableDesignFromDescription(desc)
+	design := newTableDesignFromDescription(desc)
+	if t.design != nil {
+		// keep the options which are not saved on DynamoDB.
+		design.versionAttribute = t.design.versionAttribute
+	}
+	t.design = design
 	return t.design, nil
 }
 
@@ -127,54 +140,260 @@ func (t *Table) updateThroughput() error {
 	return nil
 }
 
+// UpdateBillingMode updates the billing mode. (PROVISIONED or PAY_PER_REQUEST)
+// The throughput of the table design is used for PROVISIONED, so it must be set by TableDesign.SetThroughput() beforehand.
+// The design of PAY_PER_REQUEST table has no throughput, and it returns error in that case.
+func (t *Table) UpdateBillingMode(mode string) error {
+	in := &SDK.UpdateTableInput{
+		TableName:   pointers.String(t.nameWithPrefix),
+		BillingMode: pointers.String(mode),
+	}
+	if mode == BillingModeProvisioned {
+		if t.design.readCapacity < 1 || t.design.writeCapacity < 1 {
+			err := fmt.Errorf("read and write capacity must be set to switch to PROVISIONED; read=%d; write=%d;", t.design.readCapacity, t.design.writeCapacity)
+			t.service.Errorf("error on `UpdateBillingMode`; table=%s; error=%s", t.nameWithPrefix, err.Error())
+			return err
+		}
+		in.ProvisionedThroughput = newProvisionedThroughput(t.design.readCapacity, t.design.writeCapacity)
+		for _, gsi := range t.design.ListGSI() {
+			tp := gsi.ProvisionedThroughput
+			if tp == nil || pointerInt64(tp.ReadCapacityUnits) == 0 {
+				tp = in.ProvisionedThroughput
+			}
+			in.GlobalSecondaryIndexUpdates = append(in.GlobalSecondaryIndexUpdates, &SDK.GlobalSecondaryIndexUpdate{
+				Update: &SDK.UpdateGlobalSecondaryIndexAction{
+					IndexName:             gsi.IndexName,
+					ProvisionedThroughput: tp,
+				},
+			})
+		}
+	}
+
+	_, err := t.service.client.UpdateTable(in)
+	if err != nil {
+		t.service.Errorf("error on `UpdateTable` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+
+	_, err = t.RefreshDesign()
+	return err
+}
+
+// DescribeTTL returns the status of Time to Live.
+func (t *Table) DescribeTTL() (TimeToLiveDescription, error) {
+	out, err := t.service.client.DescribeTimeToLive(&SDK.DescribeTimeToLiveInput{
+		TableName: pointers.String(t.nameWithPrefix),
+	})
+	if err != nil {
+		t.service.Errorf("error on `DescribeTimeToLive` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return TimeToLiveDescription{}, err
+	}
+	return NewTimeToLiveDescription(out.TimeToLiveDescription), nil
+}
+
+// EnableTTL enables Time to Live on the attribute.
+// The attribute must contain the expiration time in unix epoch seconds.
+func (t *Table) EnableTTL(attributeName string) error {
+	return t.updateTTL(attributeName, true)
+}
+
+// DisableTTL disables Time to Live.
+func (t *Table) DisableTTL() error {
+	ttl, err := t.DescribeTTL()
+	switch {
+	case err != nil:
+		return err
+	case !ttl.IsEnabled():
+		return nil
+	}
+	return t.updateTTL(ttl.AttributeName, false)
+}
+
+func (t *Table) updateTTL(attributeName string, enabled bool) error {
+	_, err := t.service.client.UpdateTimeToLive(&SDK.UpdateTimeToLiveInput{
+		TableName: pointers.String(t.nameWithPrefix),
+		TimeToLiveSpecification: &SDK.TimeToLiveSpecification{
+			AttributeName: pointers.String(attributeName),
+			Enabled:       pointers.Bool(enabled),
+		},
+	})
+	if err != nil {
+		t.service.Errorf("error on `UpdateTimeToLive` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+	return nil
+}
+
 // ---------------------------------
 // Put
 // ---------------------------------
 
 // AddItem adds an item to the write-waiting list (writeItem)
+// When the version attribute is set, the condition of the version is added and the version of the item is incremented.
 func (t *Table) AddItem(item *PutItem) {
+	isVersioned := t.applyVersion(item)
 	w := &SDK.PutItemInput{
 		TableName:              pointers.String(t.nameWithPrefix),
-		ReturnConsumedCapacity: pointers.String("TOTAL"),
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
 		Item:                   item.data,
-		Expected:               item.conditions,
+	}
+
+	switch {
+	case item.HasConditionExpression():
+		// `Expected` cannot be used with ConditionExpression.
+		conds := item.toItemConditions("pc")
+		w.ConditionExpression = conds.FormatCondition()
+		w.ExpressionAttributeNames = conds.FormatNames()
+		w.ExpressionAttributeValues = conds.FormatValues()
+	default:
+		w.Expected = item.conditions
 	}
 	t.putSpool = append(t.putSpool, w)
+	if isVersioned {
+		if t.versionedItems == nil {
+			t.versionedItems = make(map[*SDK.PutItemInput]struct{})
+		}
+		t.versionedItems[w] = struct{}{}
+	}
 	t.service.addWriteTable(t)
 }
 
+// applyVersion applies optimistic locking to the item, and returns true when the version attribute exists.
+func (t *Table) applyVersion(item *PutItem) bool {
+	name := item.versionAttribute
+	if name == "" && t.design != nil {
+		name = t.design.GetVersionAttribute()
+	}
+	if name == "" {
+		return false
+	}
+
+	item.versionAttribute = name
+	item.applyVersion(name)
+	return true
+}
+
+// PutStruct puts the struct as an item immediately.
+// The struct tag `dynamodb:""` is used to marshal. (see NewPutItemFromStruct)
+// When the version attribute is set, it returns ErrVersionConflict if the stored version is different,
+// and the version field of the struct pointer is incremented after the success.
+func (t *Table) PutStruct(v interface{}) error {
+	item, err := NewPutItemFromStruct(v)
+	if err != nil {
+		t.service.Errorf("error on `PutStruct`; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+
+	if err := t.putItem(item); err != nil {
+		return err
+	}
+	setStructVersion(v, item)
+	return nil
+}
+
+// putItem puts the item immediately with optimistic locking.
+func (t *Table) putItem(item *PutItem) error {
+	isVersioned := t.applyVersion(item)
+	in := &SDK.PutItemInput{
+		TableName:              pointers.String(t.nameWithPrefix),
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+		Item:                   item.data,
+	}
+	if item.HasConditionExpression() || len(item.conditions) != 0 {
+		conds := item.toItemConditions("pc")
+		in.ConditionExpression = conds.FormatCondition()
+		in.ExpressionAttributeNames = conds.FormatNames()
+		in.ExpressionAttributeValues = conds.FormatValues()
+	}
+	if err := t.validatePutItem(in); err != nil {
+		t.service.Errorf("error on `PutItem`; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+
+	out, err := t.service.client.PutItem(in)
+	t.invalidateCache(in.Item)
+	t.service.recordError("PutItem", t.nameWithPrefix, true, err)
+	switch {
+	case isVersioned && isConditionalCheckFailedError(err):
+		t.service.Errorf("error on `PutItem` operation; table=%s; error=%s", t.nameWithPrefix, ErrVersionConflict.Error())
+		return ErrVersionConflict
+	case err != nil:
+		t.service.Errorf("error on `PutItem` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+	t.service.recordCapacity("PutItem", true, out.ConsumedCapacity)
+	return nil
+}
+
 // Put executes put operation from the write-waiting list (writeItem)
+// It returns ErrVersionConflict when all of the errors are caused by the version conflict.
 func (t *Table) Put() error {
+	_, err := t.PutWithResult()
+	return err
+}
+
+// PutWithResult executes put operation from the write-waiting list (writeItem) and returns consumed capacity.
+// It returns ErrVersionConflict when all of the errors are caused by the version conflict.
+func (t *Table) PutWithResult() (*WriteResult, error) {
+	res := &WriteResult{}
 	errList := newErrors()
+	isAllConflicts := true
 	// save items in spool
 	for _, item := range t.putSpool {
 		err := t.validatePutItem(item)
 		if err != nil {
 			errList.Add(err)
+			isAllConflicts = false
 			continue
 		}
 
-		_, err = t.service.client.PutItem(item)
-		if err != nil {
+		out, err := t.service.client.PutItem(item)
+		t.invalidateCache(item.Item)
+		t.service.recordError("PutItem", t.nameWithPrefix, true, err)
+		_, isVersioned := t.versionedItems[item]
+		switch {
+		case isVersioned && isConditionalCheckFailedError(err):
+			errList.Add(ErrVersionConflict)
+			t.errorItems = append(t.errorItems, item)
+		case err != nil:
 			errList.Add(err)
+			isAllConflicts = false
 			t.errorItems = append(t.errorItems, item)
+		default:
+			res.ConsumedCapacity = append(res.ConsumedCapacity, t.service.recordCapacity("PutItem", true, out.ConsumedCapacity)...)
 		}
 	}
 
 	t.putSpool = nil
+	t.versionedItems = nil
+	if errList.HasError() && isAllConflicts {
+		t.service.Errorf("errors on `Put` operations; table=%s; errors=[%s];", t.nameWithPrefix, errList.Error())
+		return res, ErrVersionConflict
+	}
 	if errList.HasError() {
 		t.service.Errorf("errors on `Put` operations; table=%s; errors=[%s];", t.nameWithPrefix, errList.Error())
-		return errList
+		return res, errList
 	}
-	return nil
+	return res, nil
 }
 
 // BatchPut executes BatchWriteItem operation from the write-waiting list (writeItem)
 func (t *Table) BatchPut() error {
+	_, err := t.BatchPutWithResult()
+	return err
+}
+
+// BatchPutWithResult executes BatchWriteItem operation from the write-waiting list (writeItem) and returns consumed capacity.
+func (t *Table) BatchPutWithResult() (*WriteResult, error) {
+	res := &WriteResult{}
 	errList := newErrors()
 	errorSpoolIndices := make([]int, 0, len(t.putSpool))
 	for index, item := range t.putSpool {
 		err := t.validatePutItem(item)
+		if _, ok := t.versionedItems[item]; ok {
+			// BatchWriteItem does not support conditions.
+			err = fmt.Errorf("error on `BatchPut`; versioned item cannot be written by BatchWriteItem, use Put(); table=%s", t.nameWithPrefix)
+		}
 		if err != nil {
 			errList.Add(err)
 			// add to ignore list
@@ -183,21 +402,82 @@ func (t *Table) BatchPut() error {
 		}
 	}
 	t.removeErroredSpoolByIndices(errorSpoolIndices)
-	input := new(SDK.BatchWriteItemInput)
+	t.versionedItems = nil
 	writeRequests := t.spoolToWriteRequests()
 	for i := 0; i < len(writeRequests); i++ {
-		input.SetRequestItems(writeRequests[i])
-		if _, err := t.service.client.BatchWriteItem(input); err != nil {
+		unprocessed, err := t.batchWrite(writeRequests[i], res)
+		if err != nil {
 			errList.Add(err)
 		}
+		t.addErrorWriteRequests(unprocessed)
 	}
 
 	t.putSpool = nil
 	if errList.HasError() {
 		t.service.Errorf("errors on `Put` operations; table=%s; errors=[%s];", t.nameWithPrefix, errList.Error())
-		return errList
+		return res, errList
 	}
-	return nil
+	return res, nil
+}
+
+// batchWrite executes BatchWriteItem operation and retries UnprocessedItems with exponential backoff.
+// It returns write requests which are not processed after all of the retries.
+func (t *Table) batchWrite(requestItems map[string][]*SDK.WriteRequest, res *WriteResult) ([]*SDK.WriteRequest, error) {
+	for _, wr := range requestItems[t.nameWithPrefix] {
+		switch {
+		case wr.PutRequest != nil:
+			t.invalidateCache(wr.PutRequest.Item)
+		case wr.DeleteRequest != nil:
+			t.invalidateCache(wr.DeleteRequest.Key)
+		}
+	}
+
+	maxRetry := t.service.batchMaxRetry
+	for retry := 0; ; retry++ {
+		out, err := t.service.client.BatchWriteItem(&SDK.BatchWriteItemInput{
+			RequestItems:           requestItems,
+			ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+		})
+		if err != nil {
+			t.service.recordError("BatchWriteItem", t.nameWithPrefix, true, err)
+			return requestItems[t.nameWithPrefix], err
+		}
+		res.ConsumedCapacity = append(res.ConsumedCapacity, t.service.recordCapacity("BatchWriteItem", true, out.ConsumedCapacity...)...)
+
+		requestItems = out.UnprocessedItems
+		unprocessed := requestItems[t.nameWithPrefix]
+		if len(unprocessed) != 0 {
+			// unprocessed items are caused by exceeding the throughput.
+			t.service.recordThrottle("BatchWriteItem", t.nameWithPrefix, true, nil)
+		}
+		switch {
+		case len(unprocessed) == 0:
+			return nil, nil
+		case retry >= maxRetry:
+			return unprocessed, fmt.Errorf("error on `BatchWriteItem`; unprocessed items remain after retries; table=%s; retry=%d; unprocessed=%d", t.nameWithPrefix, retry, len(unprocessed))
+		}
+
+		t.service.Infof("retry on `BatchWriteItem` operation; table=%s; retry=%d; unprocessed=%d;", t.nameWithPrefix, retry+1, len(unprocessed))
+		waitBackoff(retry)
+	}
+}
+
+// addErrorWriteRequests adds put requests into errorItems.
+func (t *Table) addErrorWriteRequests(list []*SDK.WriteRequest) {
+	for _, wr := range list {
+		if wr == nil || wr.PutRequest == nil {
+			continue
+		}
+		t.errorItems = append(t.errorItems, &SDK.PutItemInput{
+			TableName: pointers.String(t.nameWithPrefix),
+			Item:      wr.PutRequest.Item,
+		})
+	}
+}
+
+// GetErrorItems returns the items failed to write on Put or BatchPut.
+func (t *Table) GetErrorItems() []*SDK.PutItemInput {
+	return t.errorItems
 }
 
 // removeErroredSpoolByIndices removes elements which have index in validation error indices list from putSpool.
@@ -270,6 +550,11 @@ func (t *Table) ScanWithCondition(cond *ConditionList) (*QueryResult, error) {
 
 // scan executes Scan operation.
 func (t *Table) scan(cond *ConditionList, in *SDK.ScanInput) (*QueryResult, error) {
+	return t.scanWithContext(aws.BackgroundContext(), cond, in)
+}
+
+// scanWithContext executes Scan operation with context.
+func (t *Table) scanWithContext(ctx aws.Context, cond *ConditionList, in *SDK.ScanInput) (*QueryResult, error) {
 	if cond.HasFilter() {
 		in.FilterExpression = cond.FormatFilter()
 		in.ExpressionAttributeValues = cond.FormatValues()
@@ -288,8 +573,12 @@ func (t *Table) scan(cond *ConditionList, in *SDK.ScanInput) (*QueryResult, erro
 
 	in.ExclusiveStartKey = cond.startKey
 	in.TableName = pointers.String(t.nameWithPrefix)
-	req, err := t.service.client.Scan(in)
+	if in.ReturnConsumedCapacity == nil {
+		in.ReturnConsumedCapacity = pointers.String(SDK.ReturnConsumedCapacityIndexes)
+	}
+	req, err := t.service.client.ScanWithContext(ctx, in)
 	if err != nil {
+		t.service.recordError("Scan", t.nameWithPrefix, false, err)
 		t.service.Errorf("error on `Scan` operation; table=%s; error=%s;", t.nameWithPrefix, err.Error())
 		return nil, err
 	}
@@ -299,7 +588,9 @@ func (t *Table) scan(cond *ConditionList, in *SDK.ScanInput) (*QueryResult, erro
 		LastEvaluatedKey: req.LastEvaluatedKey,
 		Count:            *req.Count,
 		ScannedCount:     *req.ScannedCount,
+		ConsumedCapacity: newConsumedCapacity(req.ConsumedCapacity),
 	}
+	t.service.recordCapacity("Scan", false, req.ConsumedCapacity)
 	return res, nil
 }
 
@@ -320,6 +611,11 @@ func (t *Table) Count(cond *ConditionList) (*QueryResult, error) {
 }
 
 func (t *Table) query(cond *ConditionList, in *SDK.QueryInput) (*QueryResult, error) {
+	return t.queryWithContext(aws.BackgroundContext(), cond, in)
+}
+
+// queryWithContext executes Query operation with context.
+func (t *Table) queryWithContext(ctx aws.Context, cond *ConditionList, in *SDK.QueryInput) (*QueryResult, error) {
 	if !cond.HasCondition() {
 		err := fmt.Errorf("condition is missing, you must specify at least one condition")
 		t.service.Errorf("error on `query`; table=%s; error=%s", t.nameWithPrefix, err.Error())
@@ -346,8 +642,12 @@ func (t *Table) query(cond *ConditionList, in *SDK.QueryInput) (*QueryResult, er
 
 	in.ExclusiveStartKey = cond.startKey
 	in.TableName = pointers.String(t.nameWithPrefix)
-	req, err := t.service.client.Query(in)
+	if in.ReturnConsumedCapacity == nil {
+		in.ReturnConsumedCapacity = pointers.String(SDK.ReturnConsumedCapacityIndexes)
+	}
+	req, err := t.service.client.QueryWithContext(ctx, in)
 	if err != nil {
+		t.service.recordError("Query", t.nameWithPrefix, false, err)
 		t.service.Errorf("error on `Query` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
 		return nil, err
 	}
@@ -357,7 +657,9 @@ func (t *Table) query(cond *ConditionList, in *SDK.QueryInput) (*QueryResult, er
 		LastEvaluatedKey: req.LastEvaluatedKey,
 		Count:            *req.Count,
 		ScannedCount:     *req.ScannedCount,
+		ConsumedCapacity: newConsumedCapacity(req.ConsumedCapacity),
 	}
+	t.service.recordCapacity("Query", false, req.ConsumedCapacity)
 	return res, nil
 }
 
@@ -373,13 +675,13 @@ func (t *Table) NewConditionList() *ConditionList {
 // GetOne retrieves a single item by GetOne(HashKey [, RangeKey])
 func (t *Table) GetOne(hashValue interface{}, rangeValue ...interface{}) (map[string]interface{}, error) {
 	in := &SDK.GetItemInput{
-		TableName: pointers.String(t.nameWithPrefix),
-		Key:       t.design.keyAttributeValue(hashValue, rangeValue...),
+		TableName:              pointers.String(t.nameWithPrefix),
+		Key:                    t.design.keyAttributeValue(hashValue, rangeValue...),
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
 	}
-	req, err := t.service.client.GetItem(in)
+	req, err := t.getItem(in)
 	switch {
 	case err != nil:
-		t.service.Errorf("error on `GetItem` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
 		return nil, err
 	case req.Item == nil:
 		return nil, nil
@@ -388,6 +690,55 @@ func (t *Table) GetOne(hashValue interface{}, rangeValue ...interface{}) (map[st
 	return UnmarshalAttributeValue(req.Item), nil
 }
 
+// getItem executes GetItem operation and records consumed capacity.
+// The cached item is returned when read cache is enabled.
+func (t *Table) getItem(in *SDK.GetItemInput) (*SDK.GetItemOutput, error) {
+	isCacheable := isCacheableRead(in.ConsistentRead, in.ProjectionExpression, in.AttributesToGet)
+	if isCacheable {
+		if item, ok := t.getCachedItem(in.Key); ok {
+			return &SDK.GetItemOutput{Item: item}, nil
+		}
+	}
+
+	out, err := t.service.client.GetItem(in)
+	if err != nil {
+		t.service.recordError("GetItem", t.nameWithPrefix, false, err)
+		t.service.Errorf("error on `GetItem` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return nil, err
+	}
+	t.service.recordCapacity("GetItem", false, out.ConsumedCapacity)
+	if isCacheable {
+		t.setCachedItem(out.Item)
+	}
+	return out, nil
+}
+
+// GetOneInto retrieves a single item and unmarshals it into the struct pointer.
+// It returns false when the item does not exist.
+// The struct tag `dynamodb:""` is used to unmarshal.
+func (t *Table) GetOneInto(v interface{}, hashValue interface{}, rangeValue ...interface{}) (bool, error) {
+	in := &SDK.GetItemInput{
+		TableName:              pointers.String(t.nameWithPrefix),
+		Key:                    t.design.keyAttributeValue(hashValue, rangeValue...),
+		ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityIndexes),
+	}
+	req, err := t.getItem(in)
+	switch {
+	case err != nil:
+		return false, err
+	case req.Item == nil:
+		return false, nil
+	}
+
+	decoder := dynamodbattribute.NewDecoder()
+	decoder.TagKey = defaultResultTag
+	if err := decoder.Decode(&SDK.AttributeValue{M: req.Item}, v); err != nil {
+		t.service.Errorf("error on `GetOneInto`; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return true, err
+	}
+	return true, nil
+}
+
 // ---------------------------------
 // Delete
 // ---------------------------------
@@ -398,28 +749,48 @@ func (t *Table) Delete(hashValue interface{}, rangeValue ...interface{}) error {
 		TableName: pointers.String(t.nameWithPrefix),
 		Key:       t.design.keyAttributeValue(hashValue, rangeValue...),
 	}
+	return t.deleteItem(in)
+}
 
-	_, err := t.service.client.DeleteItem(in)
+// deleteItem executes DeleteItem operation and records consumed capacity.
+func (t *Table) deleteItem(in *SDK.DeleteItemInput) error {
+	in.ReturnConsumedCapacity = pointers.String(SDK.ReturnConsumedCapacityIndexes)
+	out, err := t.service.client.DeleteItem(in)
+	t.invalidateCache(in.Key)
 	if err != nil {
+		t.service.recordError("DeleteItem", t.nameWithPrefix, true, err)
 		t.service.Errorf("error on `DeleteItem` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
 		return err
 	}
+	t.service.recordCapacity("DeleteItem", true, out.ConsumedCapacity)
 	return nil
 }
 
+// DeleteWithCondition deletes the item when the condition expression is satisfied.
+func (t *Table) DeleteWithCondition(e Expr, hashValue interface{}, rangeValue ...interface{}) error {
+	conds := newItemConditions("dc")
+	conds.AddCondition(e)
+	in := &SDK.DeleteItemInput{
+		TableName:                 pointers.String(t.nameWithPrefix),
+		Key:                       t.design.keyAttributeValue(hashValue, rangeValue...),
+		ConditionExpression:       conds.FormatCondition(),
+		ExpressionAttributeNames:  conds.FormatNames(),
+		ExpressionAttributeValues: conds.FormatValues(),
+	}
+
+	return t.deleteItem(in)
+}
+
 // ForceDeleteAll deltes all data in the table.
-// This performs scan all item and delete it each one by one.
+// This performs scan all pages of items and delete it each one by one.
 func (t *Table) ForceDeleteAll() error {
 	hashkey := t.design.GetHashKeyName()
 	rangekey := t.design.GetRangeKeyName()
 
-	result, err := t.Scan()
-	if err != nil {
-		return err
-	}
-
 	errData := newErrors()
-	for _, item := range result.ToSliceMap() {
+	iter := t.ScanIter(t.NewConditionList())
+	for iter.Next() {
+		item := iter.Item()
 		var e error
 		switch rangekey {
 		case "":
@@ -432,6 +803,9 @@ func (t *Table) ForceDeleteAll() error {
 			errData.Add(e)
 		}
 	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
 
 	if errData.HasError() {
 		return errData
@@ -0,0 +1,236 @@
+package dynamodb
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ReadCache is a size-bounded LRU cache of items with TTL.
+type ReadCache struct {
+	mu      sync.Mutex
+	maxSize int
+	ttl     time.Duration
+	list    *list.List
+	entries map[string]*list.Element
+	stats   CacheStats
+
+	now func() time.Time
+}
+
+// CacheStats is statistics of ReadCache.
+type CacheStats struct {
+	Size        int
+	Hits        int64
+	Misses      int64
+	Evictions   int64
+	Expirations int64
+}
+
+type cacheEntry struct {
+	key       string
+	item      map[string]*SDK.AttributeValue
+	expiresAt time.Time
+}
+
+// NewReadCache returns initialized *ReadCache.
+// ttl=0 means entries are never expired.
+func NewReadCache(maxSize int, ttl time.Duration) *ReadCache {
+	return &ReadCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		list:    list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached item.
+func (c *ReadCache) Get(key string) (map[string]*SDK.AttributeValue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	e := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && !c.now().Before(e.expiresAt) {
+		c.removeElement(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.list.MoveToFront(elem)
+	c.stats.Hits++
+	return copyItem(e.item), true
+}
+
+// Set adds the item into the cache, and evicts the least recently used item when the cache is full.
+func (c *ReadCache) Set(key string, item map[string]*SDK.AttributeValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*cacheEntry)
+		e.item = copyItem(item)
+		e.expiresAt = expiresAt
+		c.list.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.list.PushFront(&cacheEntry{
+		key:       key,
+		item:      copyItem(item),
+		expiresAt: expiresAt,
+	})
+	for c.maxSize > 0 && c.list.Len() > c.maxSize {
+		c.removeElement(c.list.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Delete removes the item from the cache.
+func (c *ReadCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Purge removes all of the items from the cache.
+func (c *ReadCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Stats returns the statistics of the cache.
+func (c *ReadCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.list.Len()
+	return stats
+}
+
+func (c *ReadCache) removeElement(elem *list.Element) {
+	c.list.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// copyItem returns shallow copy of the item to prevent modification of the cached map.
+func copyItem(item map[string]*SDK.AttributeValue) map[string]*SDK.AttributeValue {
+	m := make(map[string]*SDK.AttributeValue, len(item))
+	for k, v := range item {
+		m[k] = v
+	}
+	return m
+}
+
+// ---------------------------------
+// Table
+// ---------------------------------
+
+// EnableCache enables read-through cache for GetOne, GetOneInto and BatchGet.
+// Put, BatchPut, Update, Delete and committed transactions through the same Table invalidate the cached items.
+// The items written by the others are not invalidated until the TTL expires.
+func (t *Table) EnableCache(maxSize int, ttl time.Duration) {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+	t.cache = NewReadCache(maxSize, ttl)
+}
+
+// DisableCache disables read cache.
+func (t *Table) DisableCache() {
+	t.cacheMu.Lock()
+	defer t.cacheMu.Unlock()
+	t.cache = nil
+}
+
+// readCache returns the current read cache, or nil when the cache is disabled.
+func (t *Table) readCache() *ReadCache {
+	t.cacheMu.RLock()
+	defer t.cacheMu.RUnlock()
+	return t.cache
+}
+
+// PurgeCache removes all of the cached items.
+func (t *Table) PurgeCache() {
+	if c := t.readCache(); c != nil {
+		c.Purge()
+	}
+}
+
+// GetCacheStats returns the statistics of read cache.
+func (t *Table) GetCacheStats() CacheStats {
+	c := t.readCache()
+	if c == nil {
+		return CacheStats{}
+	}
+	return c.Stats()
+}
+
+// getCachedItem returns the cached item of the key.
+func (t *Table) getCachedItem(key map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, bool) {
+	c := t.readCache()
+	if c == nil {
+		return nil, false
+	}
+	return c.Get(t.cacheKey(key))
+}
+
+// setCachedItem adds the item into the cache.
+func (t *Table) setCachedItem(item map[string]*SDK.AttributeValue) {
+	if c := t.readCache(); c != nil && item != nil {
+		c.Set(t.cacheKey(item), item)
+	}
+}
+
+// invalidateCache removes the cached item of the key. (the item can be used as the key)
+func (t *Table) invalidateCache(key map[string]*SDK.AttributeValue) {
+	if c := t.readCache(); c != nil {
+		c.Delete(t.cacheKey(key))
+	}
+}
+
+// cacheKey returns the string of the primary key values.
+func (t *Table) cacheKey(item map[string]*SDK.AttributeValue) string {
+	keys := []string{t.design.GetHashKeyName()}
+	if rangeKey := t.design.GetRangeKeyName(); rangeKey != "" {
+		keys = append(keys, rangeKey)
+	}
+
+	b := strings.Builder{}
+	for _, k := range keys {
+		v := item[k]
+		switch {
+		case v == nil:
+		case v.S != nil:
+			b.WriteString("S:" + *v.S)
+		case v.N != nil:
+			b.WriteString("N:" + *v.N)
+		case v.B != nil:
+			b.WriteString("B:" + string(v.B))
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// isCacheableRead checks if the read request can use the cache or not.
+// Strongly consistent read and projection bypass the cache.
+func isCacheableRead(consistentRead *bool, projection *string, attributesToGet []*string) bool {
+	return (consistentRead == nil || !*consistentRead) &&
+		(projection == nil || *projection == "") &&
+		len(attributesToGet) == 0
+}
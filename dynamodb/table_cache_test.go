@@ -0,0 +1,196 @@
+package dynamodb
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func TestReadCache(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	c := NewReadCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	item := func(v string) map[string]*SDK.AttributeValue {
+		return map[string]*SDK.AttributeValue{"v": {S: pointers.String(v)}}
+	}
+	c.Set("a", item("a"))
+	c.Set("b", item("b"))
+	_, ok := c.Get("a")
+	assert.True(ok)
+
+	// "b" is the least recently used.
+	c.Set("c", item("c"))
+	_, ok = c.Get("b")
+	assert.False(ok)
+	v, ok := c.Get("a")
+	assert.True(ok)
+	assert.Equal("a", *v["v"].S)
+
+	// the returned item can be modified.
+	delete(v, "v")
+	v, _ = c.Get("a")
+	assert.Contains(v, "v")
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("c")
+	assert.False(ok, "expired")
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(ok)
+
+	assert.Equal(CacheStats{
+		Size:        0,
+		Hits:        3,
+		Misses:      3,
+		Evictions:   1,
+		Expirations: 1,
+	}, c.Stats())
+
+	c.Set("d", item("d"))
+	c.Purge()
+	assert.Equal(0, c.Stats().Size)
+}
+
+func TestTableCache(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.EnableCache(100, time.Minute)
+
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	putMemoryTestItem(tbl, 2, 1, "b", "")
+	assert.NoError(tbl.Put())
+
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal("a", result["group"])
+	_, err = tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(int64(1), tbl.GetCacheStats().Hits)
+
+	// the item updated by the other client is not invalidated.
+	_, err = tbl.service.client.PutItem(&SDK.PutItemInput{
+		TableName: pointers.String(tbl.nameWithPrefix),
+		Item:      Marshal(map[string]interface{}{"id": 1, "time": 1, "group": "x"}),
+	})
+	assert.NoError(err)
+	result, _ = tbl.GetOne(1, 1)
+	assert.Equal("a", result["group"])
+
+	// update through the table invalidates the item.
+	u := tbl.Update(1, 1)
+	u.Set("group", "c")
+	_, err = u.Exec()
+	assert.NoError(err)
+	result, _ = tbl.GetOne(1, 1)
+	assert.Equal("c", result["group"])
+
+	// BatchGet uses the cached items and caches the fetched items.
+	res, err := tbl.BatchGet(BatchGetRequest{
+		RequestItems: KeysAndAttributes{Keys: []map[string]AttributeValue{
+			{"id": {Number: "1"}, "time": {Number: "1"}},
+			{"id": {Number: "2"}, "time": {Number: "1"}},
+		}},
+	})
+	assert.NoError(err)
+	assert.Len(res.Responses, 2)
+	stats := tbl.GetCacheStats()
+	assert.Equal(int64(3), stats.Hits)
+	assert.Equal(2, stats.Size)
+
+	var v struct {
+		Group string `dynamodb:"group"`
+	}
+	_, err = tbl.GetOneInto(&v, 2, 1)
+	assert.NoError(err)
+	assert.Equal("b", v.Group)
+	assert.Equal(int64(4), tbl.GetCacheStats().Hits)
+
+	assert.NoError(tbl.Delete(2, 1))
+	result, err = tbl.GetOne(2, 1)
+	assert.NoError(err)
+	assert.Nil(result)
+
+	putMemoryTestItem(tbl, 1, 1, "d", "")
+	assert.NoError(tbl.BatchPut())
+	result, _ = tbl.GetOne(1, 1)
+	assert.Equal("d", result["group"])
+
+	tbl.PurgeCache()
+	assert.Equal(0, tbl.GetCacheStats().Size)
+	tbl.DisableCache()
+	assert.Equal(CacheStats{}, tbl.GetCacheStats())
+}
+
+func TestTableCacheTransaction(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.EnableCache(100, time.Minute)
+
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	putMemoryTestItem(tbl, 2, 1, "b", "")
+	putMemoryTestItem(tbl, 3, 1, "c", "")
+	assert.NoError(tbl.Put())
+	for i := 1; i <= 3; i++ {
+		_, err := tbl.GetOne(i, 1)
+		assert.NoError(err)
+	}
+	assert.Equal(3, tbl.GetCacheStats().Size)
+
+	// the items written by the transaction are invalidated.
+	tx := tbl.service.Transact()
+	item := NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 1)
+	item.AddAttribute("group", "x")
+	tx.Put(tbl, item)
+	u := tbl.Update(2, 1)
+	u.Set("group", "y")
+	tx.Update(u)
+	tx.Delete(tbl, 3, 1)
+	assert.NoError(tx.Commit())
+	assert.Equal(0, tbl.GetCacheStats().Size)
+
+	result, _ := tbl.GetOne(1, 1)
+	assert.Equal("x", result["group"])
+	result, _ = tbl.GetOne(2, 1)
+	assert.Equal("y", result["group"])
+	result, _ = tbl.GetOne(3, 1)
+	assert.Nil(result)
+}
+
+func TestTableCacheConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	assert.NoError(tbl.Put())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				tbl.EnableCache(10, time.Minute)
+				tbl.DisableCache()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				result, err := tbl.GetOne(1, 1)
+				assert.NoError(err)
+				assert.Equal("a", result["group"])
+			}
+		}()
+	}
+	wg.Wait()
+}
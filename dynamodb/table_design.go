@@ -23,6 +23,16 @@ type TableDesign struct {
 	GSI           []*SDK.GlobalSecondaryIndex
 	Attributes    map[string]*SDK.AttributeDefinition
 
+	// options for create or update table
+	billingMode string
+	stream      *SDK.StreamSpecification
+	sse         *SDK.SSESpecification
+	ttl         *SDK.TimeToLiveSpecification
+	pitr        *SDK.PointInTimeRecoverySpecification
+
+	// for optimistic locking
+	versionAttribute string
+
 	// for table description
 	itemCount              int64
 	status                 string
@@ -74,6 +84,15 @@ func newTableDesignFromDescription(desc TableDescription) *TableDesign {
 	for _, attr := range desc.AttributeDefinitions {
 		d.Attributes[attr.Name] = attr.ToSDKType()
 	}
+	if desc.BillingModeSummary.BillingMode != "" {
+		d.billingMode = desc.BillingModeSummary.BillingMode
+	}
+	if desc.StreamSpecification.StreamEnabled {
+		d.SetStream(desc.StreamSpecification.StreamViewType)
+	}
+	if !desc.SSEDescription.IsEmpty() {
+		d.SetSSE(isSSEEnabledStatus(desc.SSEDescription.Status))
+	}
 	for _, schema := range desc.KeySchema {
 		switch schema.KeyType {
 		case "HASH":
@@ -253,6 +272,112 @@ func (d *TableDesign) GetNumberOfDecreasesToday() int64 {
 	return d.numberOfDecreasesToday
 }
 
+// SetBillingMode sets billing mode of the table. (PROVISIONED or PAY_PER_REQUEST)
+func (d *TableDesign) SetBillingMode(mode string) {
+	d.billingMode = mode
+}
+
+// GetBillingMode returns billing mode of the table.
+func (d *TableDesign) GetBillingMode() string {
+	if d.billingMode == "" {
+		return BillingModeProvisioned
+	}
+	return d.billingMode
+}
+
+// IsPayPerRequest checks if the billing mode is PAY_PER_REQUEST or not.
+func (d *TableDesign) IsPayPerRequest() bool {
+	return d.GetBillingMode() == BillingModePayPerRequest
+}
+
+// SetVersionAttribute sets the number attribute for optimistic locking.
+// Put, PutStruct and Update check the stored version and increment it.
+func (d *TableDesign) SetVersionAttribute(name string) {
+	d.versionAttribute = name
+}
+
+// GetVersionAttribute returns the attribute name for optimistic locking.
+func (d *TableDesign) GetVersionAttribute() string {
+	return d.versionAttribute
+}
+
+// ---------------------------------
+// Stream, SSE, TTL and PITR
+// ---------------------------------
+
+// SetStream enables DynamoDB Streams with the view type.
+// (KEYS_ONLY, NEW_IMAGE, OLD_IMAGE or NEW_AND_OLD_IMAGES)
+func (d *TableDesign) SetStream(viewType string) {
+	d.stream = &SDK.StreamSpecification{
+		StreamEnabled:  pointers.Bool(true),
+		StreamViewType: pointers.String(viewType),
+	}
+}
+
+// DisableStream disables DynamoDB Streams.
+func (d *TableDesign) DisableStream() {
+	d.stream = &SDK.StreamSpecification{
+		StreamEnabled: pointers.Bool(false),
+	}
+}
+
+// GetStream returns the stream setting. It returns nil when the setting is not specified.
+func (d *TableDesign) GetStream() *SDK.StreamSpecification {
+	return d.stream
+}
+
+// SetSSE enables or disables server-side encryption with KMS.
+func (d *TableDesign) SetSSE(enabled bool) {
+	d.sse = &SDK.SSESpecification{
+		Enabled: pointers.Bool(enabled),
+	}
+}
+
+// GetSSE returns the server-side encryption setting. It returns nil when the setting is not specified.
+func (d *TableDesign) GetSSE() *SDK.SSESpecification {
+	return d.sse
+}
+
+// SetTTL enables Time to Live on the attribute.
+func (d *TableDesign) SetTTL(attributeName string) {
+	d.ttl = &SDK.TimeToLiveSpecification{
+		AttributeName: pointers.String(attributeName),
+		Enabled:       pointers.Bool(true),
+	}
+}
+
+// DisableTTL disables Time to Live.
+func (d *TableDesign) DisableTTL() {
+	d.ttl = &SDK.TimeToLiveSpecification{
+		Enabled: pointers.Bool(false),
+	}
+}
+
+// GetTTL returns the Time to Live setting. It returns nil when the setting is not specified.
+func (d *TableDesign) GetTTL() *SDK.TimeToLiveSpecification {
+	return d.ttl
+}
+
+// SetPITR enables or disables point-in-time recovery.
+func (d *TableDesign) SetPITR(enabled bool) {
+	d.pitr = &SDK.PointInTimeRecoverySpecification{
+		PointInTimeRecoveryEnabled: pointers.Bool(enabled),
+	}
+}
+
+// GetPITR returns the point-in-time recovery setting. It returns nil when the setting is not specified.
+func (d *TableDesign) GetPITR() *SDK.PointInTimeRecoverySpecification {
+	return d.pitr
+}
+
+func isSSEEnabledStatus(status string) bool {
+	switch status {
+	case SDK.SSEStatusEnabled, SDK.SSEStatusEnabling, SDK.SSEStatusUpdating:
+		return true
+	}
+	return false
+}
+
 // ---------------------------------
 // misc
 // ---------------------------------
@@ -270,6 +395,11 @@ func (d *TableDesign) CreateTableInput() *SDK.CreateTableInput {
 		KeySchema:             keys,
 		AttributeDefinitions:  d.AttributeList(),
 		ProvisionedThroughput: newProvisionedThroughput(d.readCapacity, d.writeCapacity),
+		StreamSpecification:   d.stream,
+		SSESpecification:      d.sse,
+	}
+	if d.stream != nil && !*d.stream.StreamEnabled {
+		in.StreamSpecification = nil
 	}
 
 	if d.HasLSI() {
@@ -278,6 +408,19 @@ func (d *TableDesign) CreateTableInput() *SDK.CreateTableInput {
 	if d.HasGSI() {
 		in.GlobalSecondaryIndexes = d.ListGSI()
 	}
+
+	if d.IsPayPerRequest() {
+		// throughput cannot be set on PAY_PER_REQUEST.
+		in.BillingMode = pointers.String(BillingModePayPerRequest)
+		in.ProvisionedThroughput = nil
+		list := make([]*SDK.GlobalSecondaryIndex, len(in.GlobalSecondaryIndexes))
+		for i, gsi := range in.GlobalSecondaryIndexes {
+			v := *gsi
+			v.ProvisionedThroughput = nil
+			list[i] = &v
+		}
+		in.GlobalSecondaryIndexes = list
+	}
 	return in
 }
 
@@ -0,0 +1,131 @@
+package dynamodb
+
+import (
+	"time"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// DescribePITR returns the status of point-in-time recovery.
+func (t *Table) DescribePITR() (PointInTimeRecoveryDescription, error) {
+	out, err := t.service.client.DescribeContinuousBackups(&SDK.DescribeContinuousBackupsInput{
+		TableName: pointers.String(t.nameWithPrefix),
+	})
+	if err != nil {
+		t.service.Errorf("error on `DescribeContinuousBackups` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return PointInTimeRecoveryDescription{}, err
+	}
+	if out.ContinuousBackupsDescription == nil {
+		return PointInTimeRecoveryDescription{}, nil
+	}
+	return NewPointInTimeRecoveryDescription(out.ContinuousBackupsDescription.PointInTimeRecoveryDescription), nil
+}
+
+// EnablePITR enables point-in-time recovery.
+func (t *Table) EnablePITR() error {
+	return t.updatePITR(true)
+}
+
+// DisablePITR disables point-in-time recovery.
+func (t *Table) DisablePITR() error {
+	return t.updatePITR(false)
+}
+
+func (t *Table) updatePITR(enabled bool) error {
+	_, err := t.service.client.UpdateContinuousBackups(&SDK.UpdateContinuousBackupsInput{
+		TableName: pointers.String(t.nameWithPrefix),
+		PointInTimeRecoverySpecification: &SDK.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: pointers.Bool(enabled),
+		},
+	})
+	if err != nil {
+		t.service.Errorf("error on `UpdateContinuousBackups` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return err
+	}
+	return nil
+}
+
+// CreateBackup creates on-demand backup of the table.
+func (t *Table) CreateBackup(backupName string) (BackupSummary, error) {
+	out, err := t.service.client.CreateBackup(&SDK.CreateBackupInput{
+		TableName:  pointers.String(t.nameWithPrefix),
+		BackupName: pointers.String(backupName),
+	})
+	if err != nil {
+		t.service.Errorf("error on `CreateBackup` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return BackupSummary{}, err
+	}
+
+	v := NewBackupSummaryFromDetails(out.BackupDetails)
+	v.TableName = t.nameWithPrefix
+	return v, nil
+}
+
+// ListBackups returns all of the backups of the table.
+func (t *Table) ListBackups() ([]BackupSummary, error) {
+	var list []BackupSummary
+	in := &SDK.ListBackupsInput{
+		TableName: pointers.String(t.nameWithPrefix),
+	}
+	for {
+		out, err := t.service.client.ListBackups(in)
+		if err != nil {
+			t.service.Errorf("error on `ListBackups` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+			return nil, err
+		}
+		for _, v := range out.BackupSummaries {
+			list = append(list, NewBackupSummary(v))
+		}
+
+		if out.LastEvaluatedBackupArn == nil {
+			return list, nil
+		}
+		in.ExclusiveStartBackupArn = out.LastEvaluatedBackupArn
+	}
+}
+
+// RestoreFromBackup creates a new table from the backup.
+// The table prefix is added to targetName.
+func (t *Table) RestoreFromBackup(targetName, backupARN string) (TableDescription, error) {
+	targetTable := t.service.prefix + targetName
+	out, err := t.service.client.RestoreTableFromBackup(&SDK.RestoreTableFromBackupInput{
+		TargetTableName: pointers.String(targetTable),
+		BackupArn:       pointers.String(backupARN),
+	})
+	if err != nil {
+		t.service.Errorf("error on `RestoreTableFromBackup` operation; table=%s; error=%s", targetTable, err.Error())
+		return TableDescription{}, err
+	}
+	return NewTableDescription(out.TableDescription), nil
+}
+
+// RestoreToPointInTime creates a new table from the table at the time.
+// Point-in-time recovery must be enabled. The table prefix is added to targetName.
+func (t *Table) RestoreToPointInTime(targetName string, restoreTime time.Time) (TableDescription, error) {
+	return t.restoreToPointInTime(targetName, &SDK.RestoreTableToPointInTimeInput{
+		RestoreDateTime: &restoreTime,
+	})
+}
+
+// RestoreToLatest creates a new table from the table at the latest restorable time.
+// Point-in-time recovery must be enabled. The table prefix is added to targetName.
+func (t *Table) RestoreToLatest(targetName string) (TableDescription, error) {
+	return t.restoreToPointInTime(targetName, &SDK.RestoreTableToPointInTimeInput{
+		UseLatestRestorableTime: pointers.Bool(true),
+	})
+}
+
+func (t *Table) restoreToPointInTime(targetName string, in *SDK.RestoreTableToPointInTimeInput) (TableDescription, error) {
+	targetTable := t.service.prefix + targetName
+	in.SourceTableName = pointers.String(t.nameWithPrefix)
+	in.TargetTableName = pointers.String(targetTable)
+
+	out, err := t.service.client.RestoreTableToPointInTime(in)
+	if err != nil {
+		t.service.Errorf("error on `RestoreTableToPointInTime` operation; table=%s; error=%s", targetTable, err.Error())
+		return TableDescription{}, err
+	}
+	return NewTableDescription(out.TableDescription), nil
+}
@@ -0,0 +1,83 @@
+package dynamodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPITR(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "g1", "")
+	assert.NoError(tbl.Put())
+
+	pitr, err := tbl.DescribePITR()
+	assert.NoError(err)
+	assert.False(pitr.IsEnabled())
+	_, err = tbl.RestoreToLatest("mem_table_restored")
+	assert.Error(err, "restore without PITR should be error")
+
+	assert.NoError(tbl.EnablePITR())
+	pitr, err = tbl.DescribePITR()
+	assert.NoError(err)
+	assert.True(pitr.IsEnabled())
+	assert.False(pitr.EarliestRestorableDateTime.IsZero())
+
+	_, err = tbl.RestoreToPointInTime("mem_table_restored", pitr.EarliestRestorableDateTime.Add(-time.Hour))
+	assert.Error(err, "restore time before the earliest time should be error")
+
+	desc, err := tbl.RestoreToPointInTime("mem_table_restored", pitr.LatestRestorableDateTime)
+	assert.NoError(err)
+	assert.Equal("mem_table_restored", desc.TableName)
+	assert.Equal(int64(1), desc.ItemCount)
+	assert.Equal(tbl.GetDesign().GetName(), desc.RestoreSummary.SourceTableARN[len(memTableARNPrefix):])
+	assert.Len(desc.GlobalSecondaryIndexes, 1)
+
+	_, err = tbl.RestoreToLatest("mem_table_restored")
+	assert.Error(err, "existing table should be error")
+
+	assert.NoError(tbl.DisablePITR())
+	pitr, err = tbl.DescribePITR()
+	assert.NoError(err)
+	assert.Equal(PITRStatusDisabled, pitr.Status)
+}
+
+func TestBackup(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "", "")
+	assert.NoError(tbl.Put())
+
+	backup, err := tbl.CreateBackup("backup1")
+	assert.NoError(err)
+	assert.Equal("backup1", backup.BackupName)
+	assert.Equal("mem_table", backup.TableName)
+	assert.NotEmpty(backup.BackupARN)
+
+	putMemoryTestItem(tbl, 2, 1, "", "")
+	assert.NoError(tbl.Put())
+	_, err = tbl.CreateBackup("backup2")
+	assert.NoError(err)
+
+	list, err := tbl.ListBackups()
+	assert.NoError(err)
+	assert.Len(list, 2)
+	assert.Equal("backup1", list[0].BackupName)
+	assert.Equal("backup2", list[1].BackupName)
+
+	desc, err := tbl.RestoreFromBackup("mem_table_backup1", backup.BackupARN)
+	assert.NoError(err)
+	assert.Equal(int64(1), desc.ItemCount)
+	assert.Equal(backup.BackupARN, desc.RestoreSummary.SourceBackupARN)
+
+	restored, err := tbl.service.GetTable("mem_table_backup1")
+	assert.NoError(err)
+	result, err := restored.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(1, result["id"])
+
+	_, err = tbl.RestoreFromBackup("mem_table_backup2", "unknown")
+	assert.Error(err)
+}
@@ -6,12 +6,34 @@ import (
 )
 
 // BatchGet executes batch_get_item operation
+// The keys are split into chunks of 100 keys and the chunks are executed concurrently. (see DynamoDB.BatchGetAll)
+// The cached items are used when read cache is enabled.
 func (t *Table) BatchGet(req BatchGetRequest) (*BatchGetResponse, error) {
-	res, err := t.service.client.BatchGetItem(req.ToInput(t.nameWithPrefix))
-	if err != nil {
-		return nil, err
+	in := req.ToInput(t.nameWithPrefix)
+	ka := in.RequestItems[t.nameWithPrefix]
+	isCacheable := t.readCache() != nil && isCacheableRead(ka.ConsistentRead, ka.ProjectionExpression, ka.AttributesToGet)
+
+	var cached []map[string]*SDK.AttributeValue
+	if isCacheable {
+		keys := make([]map[string]*SDK.AttributeValue, 0, len(ka.Keys))
+		for _, key := range ka.Keys {
+			if item, ok := t.getCachedItem(key); ok {
+				cached = append(cached, item)
+				continue
+			}
+			keys = append(keys, key)
+		}
+		ka.Keys = keys
+	}
+
+	res, err := t.service.batchGetItem(in)
+	if isCacheable {
+		for _, item := range res.Responses[t.nameWithPrefix] {
+			t.setCachedItem(item)
+		}
+		res.Responses[t.nameWithPrefix] = append(res.Responses[t.nameWithPrefix], cached...)
 	}
-	return newBatchGetResponse(res, t.nameWithPrefix), nil
+	return newBatchGetResponse(res, t.nameWithPrefix), err
 }
 
 type BatchGetRequest struct {
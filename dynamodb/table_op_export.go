@@ -0,0 +1,392 @@
+package dynamodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Export formats.
+const (
+	// DynamoDB JSON lines. e.g.) {"Item":{"id":{"N":"1"},"name":{"S":"foo"}}}
+	ExportFormatDynamoDBJSON = "DYNAMODB_JSON"
+	// plain JSON lines. e.g.) {"id":1,"name":"foo"}
+	// Sets and binary are exported as lists and base64 strings, and they are not restored on import.
+	ExportFormatJSON = "JSON"
+)
+
+const (
+	defaultExportSegments = 4
+	// max size of a line on import. (the max item size is 400KB, but DynamoDB JSON has overheads)
+	maxImportLineSize = 4 * 1024 * 1024
+)
+
+// Export exports all of the items in the table as DynamoDB JSON lines.
+func (t *Table) Export(w io.Writer) (int64, error) {
+	return t.NewExporter(w).Exec()
+}
+
+// Import imports items from DynamoDB JSON lines into the table.
+func (t *Table) Import(r io.Reader) (int64, error) {
+	return t.NewImporter(r).Exec()
+}
+
+// Exporter exports items of the table by segmented scan.
+type Exporter struct {
+	table    *Table
+	writer   io.Writer
+	format   string
+	segments int
+	cond     *ConditionList
+}
+
+// NewExporter returns initialized *Exporter.
+func (t *Table) NewExporter(w io.Writer) *Exporter {
+	return &Exporter{
+		table:    t,
+		writer:   w,
+		format:   ExportFormatDynamoDBJSON,
+		segments: defaultExportSegments,
+	}
+}
+
+// SetFormat sets the format of the lines. (default: ExportFormatDynamoDBJSON)
+func (e *Exporter) SetFormat(format string) {
+	e.format = format
+}
+
+// SetSegments sets the number of segments of the scan.
+func (e *Exporter) SetSegments(n int) {
+	e.segments = n
+}
+
+// SetCondition sets the filter condition of the scan.
+func (e *Exporter) SetCondition(cond *ConditionList) {
+	e.cond = cond
+}
+
+// Exec executes export and returns the number of the exported items.
+// The order of the items is not guaranteed.
+func (e *Exporter) Exec() (int64, error) {
+	t := e.table
+	if err := validateExportFormat(e.format); err != nil {
+		t.service.Errorf("error on `Export`; table=%s; error=%s;", t.nameWithPrefix, err.Error())
+		return 0, err
+	}
+
+	cond := e.cond
+	if cond == nil {
+		cond = t.NewConditionList()
+	}
+
+	var mu sync.Mutex
+	var count int64
+	_, err := t.parallelScan(context.Background(), cond, e.segments, func(item map[string]*SDK.AttributeValue) error {
+		line, err := marshalExportLine(e.format, item)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := e.writer.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		t.service.Errorf("error on `Export`; table=%s; count=%d; error=%s;", t.nameWithPrefix, count, err.Error())
+		return count, err
+	}
+	return count, nil
+}
+
+// Importer imports items into the table by BatchWriteItem.
+type Importer struct {
+	table      *Table
+	reader     io.Reader
+	format     string
+	keyMapping map[string]string
+	transform  func(map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, error)
+	writeRate  int
+}
+
+// NewImporter returns initialized *Importer.
+func (t *Table) NewImporter(r io.Reader) *Importer {
+	return &Importer{
+		table:  t,
+		reader: r,
+		format: ExportFormatDynamoDBJSON,
+	}
+}
+
+// SetFormat sets the format of the lines. (default: ExportFormatDynamoDBJSON)
+func (im *Importer) SetFormat(format string) {
+	im.format = format
+}
+
+// SetKeyMapping sets the mapping of the attribute names from the source to the table.
+// e.g.) map[string]string{"user_id": "pk"}
+func (im *Importer) SetKeyMapping(m map[string]string) {
+	im.keyMapping = m
+}
+
+// SetTransform sets the function to modify the items before writing.
+// The item is skipped when the function returns nil.
+func (im *Importer) SetTransform(fn func(map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, error)) {
+	im.transform = fn
+}
+
+// SetWriteRate sets max number of the written items per second.
+// 0 means unlimited.
+func (im *Importer) SetWriteRate(itemsPerSecond int) {
+	im.writeRate = itemsPerSecond
+}
+
+// Exec executes import and returns the number of the written items.
+// The items failed to write are saved into the error items of the table. (see GetErrorItems)
+func (im *Importer) Exec() (int64, error) {
+	t := im.table
+	if err := validateExportFormat(im.format); err != nil {
+		t.service.Errorf("error on `Import`; table=%s; error=%s;", t.nameWithPrefix, err.Error())
+		return 0, err
+	}
+
+	errList := newErrors()
+	res := &WriteResult{}
+	started := time.Now()
+	var count int64
+	chunk := make([]*SDK.WriteRequest, 0, batchWriteItemMax)
+	flush := func() {
+		if len(chunk) == 0 {
+			return
+		}
+		im.waitRate(started, count)
+		unprocessed, err := t.batchWrite(map[string][]*SDK.WriteRequest{t.nameWithPrefix: chunk}, res)
+		if err != nil {
+			errList.Add(err)
+		}
+		t.addErrorWriteRequests(unprocessed)
+		count += int64(len(chunk) - len(unprocessed))
+		chunk = make([]*SDK.WriteRequest, 0, batchWriteItemMax)
+	}
+
+	scanner := bufio.NewScanner(im.reader)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		item, err := im.convert(line)
+		if err != nil {
+			errList.Add(fmt.Errorf("line=%d; error=%s", lineNumber, err.Error()))
+			continue
+		}
+		if item == nil {
+			continue
+		}
+
+		chunk = append(chunk, &SDK.WriteRequest{PutRequest: &SDK.PutRequest{Item: item}})
+		if len(chunk) == batchWriteItemMax {
+			flush()
+		}
+	}
+	flush()
+	if err := scanner.Err(); err != nil {
+		errList.Add(err)
+	}
+
+	if errList.HasError() {
+		t.service.Errorf("errors on `Import`; table=%s; count=%d; errors=[%s];", t.nameWithPrefix, count, errList.Error())
+		return count, errList
+	}
+	return count, nil
+}
+
+// convert converts the line into the item of the table.
+func (im *Importer) convert(line []byte) (map[string]*SDK.AttributeValue, error) {
+	item, err := unmarshalExportLine(im.format, line)
+	if err != nil {
+		return nil, err
+	}
+
+	for from, to := range im.keyMapping {
+		if v, ok := item[from]; ok {
+			delete(item, from)
+			item[to] = v
+		}
+	}
+	if im.transform != nil {
+		item, err = im.transform(item)
+		if err != nil || item == nil {
+			return nil, err
+		}
+	}
+
+	err = im.table.validatePutItem(&SDK.PutItemInput{Item: item})
+	return item, err
+}
+
+// waitRate sleeps to keep the write rate.
+func (im *Importer) waitRate(started time.Time, written int64) {
+	if im.writeRate < 1 {
+		return
+	}
+
+	expected := time.Duration(float64(written) / float64(im.writeRate) * float64(time.Second))
+	if d := expected - time.Since(started); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+func validateExportFormat(format string) error {
+	switch format {
+	case ExportFormatDynamoDBJSON, ExportFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("unknown format; format=%s;", format)
+}
+
+// exportLine is a line of DynamoDB JSON.
+type exportLine struct {
+	Item map[string]*SDK.AttributeValue
+}
+
+func marshalExportLine(format string, item map[string]*SDK.AttributeValue) ([]byte, error) {
+	m := make(map[string]interface{}, len(item))
+	if format == ExportFormatJSON {
+		for k, v := range item {
+			m[k] = toPlainJSON(v)
+		}
+		return json.Marshal(m)
+	}
+
+	for k, v := range item {
+		m[k] = toDynamoDBJSON(v)
+	}
+	return json.Marshal(map[string]interface{}{"Item": m})
+}
+
+func unmarshalExportLine(format string, line []byte) (map[string]*SDK.AttributeValue, error) {
+	if format == ExportFormatJSON {
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		var v map[string]interface{}
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		return dynamodbattribute.MarshalMap(convertJSONNumber(v))
+	}
+
+	var v exportLine
+	if err := json.Unmarshal(line, &v); err != nil {
+		return nil, err
+	}
+	if len(v.Item) == 0 {
+		return nil, fmt.Errorf("`Item` is empty")
+	}
+	return v.Item, nil
+}
+
+// toDynamoDBJSON converts the attribute value into the structure of DynamoDB JSON.
+func toDynamoDBJSON(v *SDK.AttributeValue) map[string]interface{} {
+	switch {
+	case v.S != nil:
+		return map[string]interface{}{"S": *v.S}
+	case v.N != nil:
+		return map[string]interface{}{"N": *v.N}
+	case v.B != nil:
+		return map[string]interface{}{"B": v.B}
+	case v.BOOL != nil:
+		return map[string]interface{}{"BOOL": *v.BOOL}
+	case v.NULL != nil:
+		return map[string]interface{}{"NULL": *v.NULL}
+	case v.SS != nil:
+		return map[string]interface{}{"SS": v.SS}
+	case v.NS != nil:
+		return map[string]interface{}{"NS": v.NS}
+	case v.BS != nil:
+		return map[string]interface{}{"BS": v.BS}
+	case v.L != nil:
+		list := make([]interface{}, len(v.L))
+		for i, vv := range v.L {
+			list[i] = toDynamoDBJSON(vv)
+		}
+		return map[string]interface{}{"L": list}
+	case v.M != nil:
+		m := make(map[string]interface{}, len(v.M))
+		for k, vv := range v.M {
+			m[k] = toDynamoDBJSON(vv)
+		}
+		return map[string]interface{}{"M": m}
+	}
+	return map[string]interface{}{"NULL": true}
+}
+
+// toPlainJSON converts the attribute value into the value of plain JSON.
+// Numbers are converted into json.Number to keep the precision.
+func toPlainJSON(v *SDK.AttributeValue) interface{} {
+	switch {
+	case v.S != nil:
+		return *v.S
+	case v.N != nil:
+		return json.Number(*v.N)
+	case v.B != nil:
+		return v.B
+	case v.BOOL != nil:
+		return *v.BOOL
+	case v.SS != nil:
+		return v.SS
+	case v.NS != nil:
+		list := make([]json.Number, len(v.NS))
+		for i, n := range v.NS {
+			list[i] = json.Number(*n)
+		}
+		return list
+	case v.BS != nil:
+		return v.BS
+	case v.L != nil:
+		list := make([]interface{}, len(v.L))
+		for i, vv := range v.L {
+			list[i] = toPlainJSON(vv)
+		}
+		return list
+	case v.M != nil:
+		m := make(map[string]interface{}, len(v.M))
+		for k, vv := range v.M {
+			m[k] = toPlainJSON(vv)
+		}
+		return m
+	}
+	return nil
+}
+
+// convertJSONNumber converts json.Number into dynamodbattribute.Number to keep the precision.
+func convertJSONNumber(v interface{}) interface{} {
+	switch vv := v.(type) {
+	case json.Number:
+		return dynamodbattribute.Number(vv.String())
+	case []interface{}:
+		for i := range vv {
+			vv[i] = convertJSONNumber(vv[i])
+		}
+	case map[string]interface{}:
+		for k := range vv {
+			vv[k] = convertJSONNumber(vv[k])
+		}
+	}
+	return v
+}
@@ -0,0 +1,111 @@
+package dynamodb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func TestExportImport(t *testing.T) {
+	assert := assert.New(t)
+	src := getMemoryTestTable(t)
+	for i := 1; i <= 30; i++ {
+		item := NewPutItem()
+		item.AddAttribute("id", i)
+		item.AddAttribute("time", 1)
+		item.AddAttribute("score", 1.5)
+		item.AddAttribute("tags", []string{"a", "b"})
+		item.AddAttribute("data", []byte("bin"))
+		item.AddAttribute("nested", map[string]interface{}{"flag": true})
+		src.AddItem(item)
+	}
+	assert.NoError(src.BatchPut())
+
+	buf := &bytes.Buffer{}
+	n, err := src.Export(buf)
+	assert.NoError(err)
+	assert.Equal(int64(30), n)
+	assert.Equal(30, strings.Count(buf.String(), "\n"))
+
+	// import into the table which has the other prefix and the other key name.
+	svc := NewInMemory()
+	svc.SetPrefix("stg_")
+	design := NewTableDesignWithHashKeyN("mem_table", "user_id")
+	design.AddRangeKeyN("time")
+	assert.NoError(svc.CreateTable(design))
+	dst, err := svc.GetTable("mem_table")
+	assert.NoError(err)
+
+	im := dst.NewImporter(buf)
+	im.SetKeyMapping(map[string]string{"id": "user_id"})
+	im.SetTransform(func(item map[string]*SDK.AttributeValue) (map[string]*SDK.AttributeValue, error) {
+		if *item["user_id"].N == "30" {
+			return nil, nil
+		}
+		return item, nil
+	})
+	im.SetWriteRate(1000)
+	n, err = im.Exec()
+	assert.NoError(err)
+	assert.Equal(int64(29), n)
+
+	result, err := dst.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal([]*string{pointers.String("a"), pointers.String("b")}, result["tags"])
+	assert.Equal([]byte("bin"), result["data"])
+	assert.Equal(map[string]interface{}{"flag": true}, result["nested"])
+	assert.Nil(result["id"])
+	result, err = dst.GetOne(30, 1)
+	assert.NoError(err)
+	assert.Nil(result)
+
+	v := struct {
+		Score float64 `dynamodb:"score"`
+	}{}
+	_, err = dst.GetOneInto(&v, 2, 1)
+	assert.NoError(err)
+	assert.Equal(1.5, v.Score)
+}
+
+func TestExportImportJSON(t *testing.T) {
+	assert := assert.New(t)
+	src := getMemoryTestTable(t)
+	item := NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 12345678901234567)
+	item.AddAttribute("name", "foo")
+	src.AddItem(item)
+	assert.NoError(src.Put())
+
+	buf := &bytes.Buffer{}
+	ex := src.NewExporter(buf)
+	ex.SetFormat(ExportFormatJSON)
+	ex.SetSegments(2)
+	n, err := ex.Exec()
+	assert.NoError(err)
+	assert.Equal(int64(1), n)
+	assert.JSONEq(`{"id":1,"time":12345678901234567,"name":"foo"}`, buf.String())
+
+	dst := getMemoryTestTable(t)
+	im := dst.NewImporter(strings.NewReader(buf.String() + "\n{\"id\":2}\nbroken\n"))
+	im.SetFormat(ExportFormatJSON)
+	n, err = im.Exec()
+	assert.Error(err, "missing range key and invalid JSON")
+	assert.Equal(int64(1), n)
+
+	result, err := dst.GetOne(1, 12345678901234567)
+	assert.NoError(err)
+	assert.Equal("foo", result["name"])
+
+	_, err = dst.NewImporter(strings.NewReader("")).Exec()
+	assert.NoError(err)
+	ex = src.NewExporter(buf)
+	ex.SetFormat("CSV")
+	_, err = ex.Exec()
+	assert.Error(err)
+}
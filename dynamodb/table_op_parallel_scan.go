@@ -0,0 +1,174 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const defaultParallelScanWorkers = 8
+
+// ParallelScan executes segmented Scan operations concurrently and calls fn for each item.
+// fn is called from multiple goroutines, so it must be safe for concurrent use.
+// All of the segments stop on the first error.
+func (t *Table) ParallelScan(cond *ConditionList, segments int, fn func(map[string]interface{}) error) (*ParallelScanResult, error) {
+	return t.ParallelScanWithContext(context.Background(), cond, segments, fn)
+}
+
+// ParallelScanWithContext executes segmented Scan operations concurrently with context.
+func (t *Table) ParallelScanWithContext(ctx context.Context, cond *ConditionList, segments int, fn func(map[string]interface{}) error) (*ParallelScanResult, error) {
+	return t.parallelScan(ctx, cond, segments, func(item map[string]*SDK.AttributeValue) error {
+		return fn(UnmarshalAttributeValue(item))
+	})
+}
+
+// parallelScan executes segmented Scan operations concurrently and calls fn for each raw item.
+func (t *Table) parallelScan(ctx context.Context, cond *ConditionList, segments int, fn func(map[string]*SDK.AttributeValue) error) (*ParallelScanResult, error) {
+	if segments < 1 {
+		err := fmt.Errorf("segments must be greater than zero; segments=%d;", segments)
+		t.service.Errorf("error on `ParallelScan`; table=%s; error=%s;", t.nameWithPrefix, err.Error())
+		return nil, err
+	}
+
+	workers := t.service.parallelScanWorkers
+	if workers < 1 || workers > segments {
+		workers = segments
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &ParallelScanResult{
+		Segments: make([]SegmentResult, segments),
+	}
+	segmentCh := make(chan int, segments)
+	for i := 0; i < segments; i++ {
+		result.Segments[i].Segment = i
+		segmentCh <- i
+	}
+	close(segmentCh)
+
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for segment := range segmentCh {
+				if ctx.Err() != nil {
+					return
+				}
+				res, err := t.scanSegment(ctx, cond, segment, segments, fn)
+				result.Segments[segment] = res
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil && !result.isCompleted() {
+		// some segments are skipped by the canceled context.
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		t.service.Errorf("error on `ParallelScan`; table=%s; error=%s;", t.nameWithPrefix, firstErr.Error())
+		return result, firstErr
+	}
+	return result, nil
+}
+
+// scanSegment scans all pages of the segment.
+func (t *Table) scanSegment(ctx context.Context, cond *ConditionList, segment, total int, fn func(map[string]*SDK.AttributeValue) error) (SegmentResult, error) {
+	res := SegmentResult{
+		Segment: segment,
+	}
+	iter := newIterator(cond, nil, func(ctx aws.Context, c *ConditionList) (*QueryResult, error) {
+		return t.scanWithContext(ctx, c, &SDK.ScanInput{
+			Segment:                pointers.Long(segment),
+			TotalSegments:          pointers.Long(total),
+			ReturnConsumedCapacity: pointers.String(SDK.ReturnConsumedCapacityTotal),
+		})
+	})
+	iter.SetContext(ctx)
+
+	for iter.NextPage() {
+		page := iter.Page()
+		res.Pages++
+		res.Count += page.Count
+		res.ScannedCount += page.ScannedCount
+		res.ConsumedCapacity += page.ConsumedCapacity.CapacityUnits
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return res, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return res, err
+	}
+	res.IsCompleted = true
+	return res, nil
+}
+
+// ParallelScanResult is struct for result of ParallelScan.
+type ParallelScanResult struct {
+	Segments []SegmentResult
+}
+
+// Count returns total count of the items.
+func (r ParallelScanResult) Count() int64 {
+	var n int64
+	for _, s := range r.Segments {
+		n += s.Count
+	}
+	return n
+}
+
+// ScannedCount returns total count of the scanned items.
+func (r ParallelScanResult) ScannedCount() int64 {
+	var n int64
+	for _, s := range r.Segments {
+		n += s.ScannedCount
+	}
+	return n
+}
+
+// ConsumedCapacity returns total consumed capacity units.
+func (r ParallelScanResult) ConsumedCapacity() float64 {
+	var n float64
+	for _, s := range r.Segments {
+		n += s.ConsumedCapacity
+	}
+	return n
+}
+
+func (r ParallelScanResult) isCompleted() bool {
+	for _, s := range r.Segments {
+		if !s.IsCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// SegmentResult is struct for result of a segment on ParallelScan.
+type SegmentResult struct {
+	Segment          int
+	Pages            int
+	Count            int64
+	ScannedCount     int64
+	ConsumedCapacity float64
+	IsCompleted      bool
+}
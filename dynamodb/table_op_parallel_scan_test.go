@@ -0,0 +1,70 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelScanResult(t *testing.T) {
+	assert := assert.New(t)
+
+	r := ParallelScanResult{
+		Segments: []SegmentResult{
+			{Segment: 0, Count: 3, ScannedCount: 5, ConsumedCapacity: 1.5, IsCompleted: true},
+			{Segment: 1, Count: 2, ScannedCount: 2, ConsumedCapacity: 0.5, IsCompleted: true},
+		},
+	}
+	assert.EqualValues(5, r.Count())
+	assert.EqualValues(7, r.ScannedCount())
+	assert.Equal(2.0, r.ConsumedCapacity())
+	assert.True(r.isCompleted())
+
+	r.Segments[1].IsCompleted = false
+	assert.False(r.isCompleted())
+}
+
+func TestParallelScan(t *testing.T) {
+	assert := assert.New(t)
+	resetTestTable(t)
+	tbl := getTestTable(t)
+	for i := 0; i < 30; i++ {
+		putTestTable(tbl, 100+i%3, i)
+	}
+
+	_, err := tbl.ParallelScan(tbl.NewConditionList(), 0, nil)
+	assert.Error(err)
+
+	var mu sync.Mutex
+	ids := make(map[int]int)
+	cond := tbl.NewConditionList()
+	cond.SetLimit(5)
+	res, err := tbl.ParallelScan(cond, 4, func(item map[string]interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ids[item["id"].(int)]++
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(res.Segments, 4)
+	assert.EqualValues(30, res.Count())
+	assert.EqualValues(30, res.ScannedCount())
+	assert.Len(ids, 3)
+	assert.Equal(10, ids[100])
+
+	errStop := errors.New("stop")
+	_, err = tbl.ParallelScan(cond, 4, func(item map[string]interface{}) error {
+		return errStop
+	})
+	assert.Equal(errStop, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tbl.ParallelScanWithContext(ctx, cond, 4, func(item map[string]interface{}) error {
+		return nil
+	})
+	assert.Error(err)
+}
@@ -0,0 +1,330 @@
+package dynamodb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// update actions for UpdateExpression.
+const (
+	updateActionSet    = "SET"
+	updateActionRemove = "REMOVE"
+	updateActionAdd    = "ADD"
+	updateActionDelete = "DELETE"
+)
+
+// operations for SET action.
+const (
+	setOperationNone        = ""
+	setOperationIfNotExists = "if_not_exists"
+	setOperationListAppend  = "list_append"
+	setOperationIncrement   = "+"
+	setOperationDecrement   = "-"
+)
+
+// Update returns initialized *UpdateItem for the item of given keys.
+// When the table design has the version attribute, the version is incremented on the update.
+func (t *Table) Update(hashValue interface{}, rangeValue ...interface{}) *UpdateItem {
+	u := &UpdateItem{
+		ItemConditions: newItemConditions("uc"),
+		table:          t,
+		key:            t.design.keyAttributeValue(hashValue, rangeValue...),
+	}
+	if name := t.design.GetVersionAttribute(); name != "" {
+		u.versionAttribute = name
+		u.Increment(name, 1)
+	}
+	return u
+}
+
+// UpdateItem is a builder for `UpdateItem` operation.
+type UpdateItem struct {
+	ItemConditions
+
+	table *Table
+	key   map[string]*SDK.AttributeValue
+
+	actions      []*updateAction
+	returnValues string
+
+	versionAttribute string
+	isVersioned      bool
+}
+
+// Set adds SET action to replace the attribute value.
+func (u *UpdateItem) Set(name string, value interface{}) {
+	u.addAction(updateActionSet, setOperationNone, name, value)
+}
+
+// SetIfNotExists adds SET action to put the attribute value only when the attribute does not exist.
+func (u *UpdateItem) SetIfNotExists(name string, value interface{}) {
+	u.addAction(updateActionSet, setOperationIfNotExists, name, value)
+}
+
+// ListAppend adds SET action to append values to the list attribute.
+// The attribute is created when it does not exist.
+func (u *UpdateItem) ListAppend(name string, values interface{}) {
+	a := u.addAction(updateActionSet, setOperationListAppend, name, values)
+	a.subValue = []interface{}{}
+}
+
+// Increment adds SET action to increase the number attribute.
+// The attribute is treated as zero when it does not exist.
+func (u *UpdateItem) Increment(name string, num interface{}) {
+	a := u.addAction(updateActionSet, setOperationIncrement, name, num)
+	a.subValue = 0
+}
+
+// Decrement adds SET action to decrease the number attribute.
+// The attribute is treated as zero when it does not exist.
+func (u *UpdateItem) Decrement(name string, num interface{}) {
+	a := u.addAction(updateActionSet, setOperationDecrement, name, num)
+	a.subValue = 0
+}
+
+// Remove adds REMOVE action to delete the attribute.
+func (u *UpdateItem) Remove(name string) {
+	u.addAction(updateActionRemove, setOperationNone, name, nil)
+}
+
+// Add adds ADD action to add the number to the attribute or add the elements to the set attribute.
+func (u *UpdateItem) Add(name string, value interface{}) {
+	u.addAction(updateActionAdd, setOperationNone, name, value)
+}
+
+// DeleteFromSet adds DELETE action to remove the elements from the set attribute.
+func (u *UpdateItem) DeleteFromSet(name string, value interface{}) {
+	u.addAction(updateActionDelete, setOperationNone, name, value)
+}
+
+func (u *UpdateItem) addAction(action, operation, name string, value interface{}) *updateAction {
+	a := &updateAction{
+		action:    action,
+		operation: operation,
+		Key:       name,
+		Value:     value,
+	}
+	u.actions = append(u.actions, a)
+	return a
+}
+
+// ExpectVersion adds the condition that the stored version equals the given version.
+// Zero means the item does not exist yet. Exec returns ErrVersionConflict when the condition fails.
+// The table design must have the version attribute.
+func (u *UpdateItem) ExpectVersion(version int64) {
+	if u.versionAttribute == "" || u.isVersioned {
+		return
+	}
+
+	u.isVersioned = true
+	if version == 0 {
+		u.AddCondition(ExprAttributeNotExists(u.versionAttribute))
+		return
+	}
+	u.AddCondition(ExprEQ(u.versionAttribute, version))
+}
+
+// HasAction checks if at least one action is set or not.
+func (u *UpdateItem) HasAction() bool {
+	return len(u.actions) != 0
+}
+
+// SetReturnValues sets ReturnValues. (e.g. `ALL_NEW`, `UPDATED_OLD`)
+func (u *UpdateItem) SetReturnValues(v string) {
+	u.returnValues = v
+}
+
+// FormatUpdate returns string pointer for UpdateExpression.
+func (u *UpdateItem) FormatUpdate() *string {
+	e, _ := u.build()
+	return e
+}
+
+// FormatValues returns the parameter for ExpressionAttributeValues.
+func (u *UpdateItem) FormatValues() map[string]*SDK.AttributeValue {
+	m := make(map[string]*SDK.AttributeValue)
+	_, b := u.build()
+	for k, v := range b.values {
+		m[k] = v
+	}
+	_, b = u.ItemConditions.build()
+	for k, v := range b.values {
+		m[k] = v
+	}
+
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// FormatNames returns the parameter for ExpressionAttributeNames.
+func (u *UpdateItem) FormatNames() map[string]*string {
+	m := make(map[string]*string)
+	_, b := u.build()
+	for k, v := range b.names {
+		m[k] = v
+	}
+	_, b = u.ItemConditions.build()
+	for k, v := range b.names {
+		m[k] = v
+	}
+	return m
+}
+
+// build returns UpdateExpression and the builder which contains names and values.
+// The placeholders are indexed, so any attribute name can be used and the same attribute can be used in multiple actions.
+func (u *UpdateItem) build() (*string, *exprBuilder) {
+	b := newExprBuilder("u", nil)
+	if !u.HasAction() {
+		return nil, b
+	}
+
+	order := []string{updateActionSet, updateActionRemove, updateActionAdd, updateActionDelete}
+	grouped := make(map[string][]string)
+	for _, a := range u.actions {
+		grouped[a.action] = append(grouped[a.action], a.expression(b))
+	}
+
+	expression := make([]string, 0, len(grouped))
+	for _, action := range order {
+		list, ok := grouped[action]
+		if !ok {
+			continue
+		}
+		expression = append(expression, action+" "+strings.Join(list, ", "))
+	}
+	e := strings.Join(expression, " ")
+	return &e, b
+}
+
+// UpdateItemInput creates *SDK.UpdateItemInput from the UpdateItem.
+func (u *UpdateItem) UpdateItemInput() *SDK.UpdateItemInput {
+	in := &SDK.UpdateItemInput{
+		TableName:                 pointers.String(u.table.nameWithPrefix),
+		Key:                       u.key,
+		UpdateExpression:          u.FormatUpdate(),
+		ConditionExpression:       u.ItemConditions.FormatCondition(),
+		ExpressionAttributeNames:  u.FormatNames(),
+		ExpressionAttributeValues: u.FormatValues(),
+		ReturnConsumedCapacity:    pointers.String(SDK.ReturnConsumedCapacityIndexes),
+	}
+	if u.returnValues != "" {
+		in.ReturnValues = pointers.String(u.returnValues)
+	}
+	return in
+}
+
+// Exec executes `UpdateItem` operation.
+func (u *UpdateItem) Exec() (*UpdateItemResult, error) {
+	return u.table.updateItem(u)
+}
+
+func (t *Table) updateItem(u *UpdateItem) (*UpdateItemResult, error) {
+	if !u.HasAction() {
+		err := fmt.Errorf("action is missing, you must specify at least one action")
+		t.service.Errorf("error on `updateItem`; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return nil, err
+	}
+
+	out, err := t.service.client.UpdateItem(u.UpdateItemInput())
+	t.invalidateCache(u.key)
+	t.service.recordError("UpdateItem", t.nameWithPrefix, true, err)
+	switch {
+	case u.isVersioned && isConditionalCheckFailedError(err):
+		t.service.Errorf("error on `UpdateItem` operation; table=%s; error=%s", t.nameWithPrefix, ErrVersionConflict.Error())
+		return nil, ErrVersionConflict
+	case err != nil:
+		t.service.Errorf("error on `UpdateItem` operation; table=%s; error=%s", t.nameWithPrefix, err.Error())
+		return nil, err
+	}
+	t.service.recordCapacity("UpdateItem", true, out.ConsumedCapacity)
+	return &UpdateItemResult{
+		Attributes:       out.Attributes,
+		ConsumedCapacity: newConsumedCapacity(out.ConsumedCapacity),
+	}, nil
+}
+
+// UpdateItemResult is struct for result of `UpdateItem` operation.
+type UpdateItemResult struct {
+	Attributes       map[string]*SDK.AttributeValue
+	ConsumedCapacity ConsumedCapacity
+}
+
+// ToMap converts returned attributes to map.
+func (r UpdateItemResult) ToMap() map[string]interface{} {
+	return UnmarshalAttributeValue(r.Attributes)
+}
+
+// Unmarshal unmarshals given struct pointer from returned attributes.
+// The struct tag `dynamodb:""` is used to unmarshal.
+func (r UpdateItemResult) Unmarshal(v interface{}) error {
+	return r.UnmarshalWithTagName(v, defaultResultTag)
+}
+
+// UnmarshalWithTagName unmarshals given struct pointer and tag name from returned attributes.
+func (r UpdateItemResult) UnmarshalWithTagName(v interface{}, structTag string) error {
+	decoder := dynamodbattribute.NewDecoder()
+	decoder.TagKey = structTag
+	return decoder.Decode(&SDK.AttributeValue{M: r.Attributes}, v)
+}
+
+// updateAction contains an action of UpdateExpression.
+type updateAction struct {
+	action    string
+	operation string
+	Key       string
+	Value     interface{}
+	subValue  interface{}
+}
+
+// expression returns the action with the placeholders registered in the builder.
+func (a *updateAction) expression(b *exprBuilder) string {
+	name := b.attributeName(a.Key)
+	switch a.action {
+	case updateActionRemove:
+		return name
+	case updateActionAdd, updateActionDelete:
+		return fmt.Sprintf("%s %s", name, a.value(b, a.Value))
+	}
+
+	switch a.operation {
+	case setOperationIfNotExists:
+		return fmt.Sprintf("%s = if_not_exists(%s, %s)", name, name, a.value(b, a.Value))
+	case setOperationListAppend:
+		sub := a.value(b, a.subValue)
+		return fmt.Sprintf("%s = list_append(if_not_exists(%s, %s), %s)", name, name, sub, a.value(b, a.Value))
+	case setOperationIncrement, setOperationDecrement:
+		sub := a.value(b, a.subValue)
+		return fmt.Sprintf("%s = if_not_exists(%s, %s) %s %s", name, name, sub, a.operation, a.value(b, a.Value))
+	default:
+		return fmt.Sprintf("%s = %s", name, a.value(b, a.Value))
+	}
+}
+
+func (a *updateAction) value(b *exprBuilder, v interface{}) string {
+	if a.operation == setOperationListAppend {
+		return b.value(a.Key, createListAttributeValue(v))
+	}
+	return b.value(a.Key, v)
+}
+
+// createListAttributeValue creates List type AttributeValue from the slice.
+func createListAttributeValue(v interface{}) *SDK.AttributeValue {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return &SDK.AttributeValue{L: []*SDK.AttributeValue{createAttributeValue(v)}}
+	}
+
+	list := make([]*SDK.AttributeValue, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		list[i] = createAttributeValue(rv.Index(i).Interface())
+	}
+	return &SDK.AttributeValue{L: list}
+}
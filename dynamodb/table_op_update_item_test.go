@@ -0,0 +1,160 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getUpdateTestTable() *Table {
+	design := NewTableDesignWithHashKeyN("foo_table", "id")
+	design.AddRangeKeyN("time")
+	return &Table{
+		name:           "foo_table",
+		nameWithPrefix: "foo_table",
+		design:         design,
+	}
+}
+
+func TestUpdateItemFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getUpdateTestTable()
+	u := tbl.Update(100, 1)
+	assert.False(u.HasAction())
+	assert.Nil(u.FormatUpdate())
+	assert.Nil(u.FormatCondition())
+
+	u.Set("name", "foo")
+	u.Remove("old")
+	u.Add("tags", []string{"a"})
+	u.SetIfNotExists("created", 10)
+	u.DeleteFromSet("flags", []string{"b"})
+	u.Increment("count", 1)
+	u.Decrement("stock", 2)
+	u.ListAppend("logs", []string{"x", "y"})
+	u.AddConditionExist("id")
+	u.AddConditionEQ("status", "active")
+	assert.True(u.HasAction())
+
+	assert.Equal("SET #u0 = :u0, "+
+		"#u3 = if_not_exists(#u3, :u2), "+
+		"#u5 = if_not_exists(#u5, :u4) + :u5, "+
+		"#u6 = if_not_exists(#u6, :u6) - :u7, "+
+		"#u7 = list_append(if_not_exists(#u7, :u8), :u9) "+
+		"REMOVE #u1 ADD #u2 :u1 DELETE #u4 :u3", *u.FormatUpdate())
+	assert.Equal("attribute_exists(#uc0) AND #uc1 = :uc0", *u.FormatCondition())
+
+	names := u.FormatNames()
+	assert.Len(names, 10)
+	assert.Equal("name", *names["#u0"])
+	assert.Equal("old", *names["#u1"])
+	assert.Equal("status", *names["#uc1"])
+
+	values := u.FormatValues()
+	assert.Len(values, 11)
+	assert.Equal("foo", *values[":u0"].S)
+	assert.Equal("0", *values[":u4"].N)
+	assert.Equal("1", *values[":u5"].N)
+	assert.Len(values[":u9"].L, 2)
+	assert.Equal("x", *values[":u9"].L[0].S)
+	assert.Len(values[":u8"].L, 0)
+	assert.Equal("active", *values[":uc0"].S)
+
+	in := u.UpdateItemInput()
+	assert.Equal("foo_table", *in.TableName)
+	assert.Equal("100", *in.Key["id"].N)
+	assert.Equal("1", *in.Key["time"].N)
+	assert.Nil(in.ReturnValues)
+
+	u.SetReturnValues(ReturnValueAllNew)
+	in = u.UpdateItemInput()
+	assert.Equal(ReturnValueAllNew, *in.ReturnValues)
+}
+
+func TestUpdateItemExec(t *testing.T) {
+	assert := assert.New(t)
+	resetTestTable(t)
+	tbl := getTestTable(t)
+	putTestTable(tbl, 100, 1)
+
+	u := tbl.Update(100, 1)
+	_, err := u.Exec()
+	assert.Error(err, "update without action should be error")
+
+	u.Set("name", "foo")
+	u.Increment("count", 3)
+	u.AddConditionExist("id")
+	u.SetReturnValues(ReturnValueAllNew)
+	res, err := u.Exec()
+	assert.NoError(err)
+
+	m := res.ToMap()
+	assert.Equal("foo", m["name"])
+	assert.Equal(3, m["count"])
+	assert.Equal("lsi_value", m["lsi_key"])
+
+	u = tbl.Update(100, 1)
+	u.Remove("name")
+	u.Decrement("count", 1)
+	u.SetReturnValues(ReturnValueUpdatedOld)
+	res, err = u.Exec()
+	assert.NoError(err)
+
+	var old struct {
+		Name  string `dynamodb:"name"`
+		Count int    `dynamodb:"count"`
+	}
+	assert.NoError(res.Unmarshal(&old))
+	assert.Equal("foo", old.Name)
+	assert.Equal(3, old.Count)
+
+	result, err := tbl.GetOne(100, 1)
+	assert.NoError(err)
+	assert.Equal(2, result["count"])
+	assert.Nil(result["name"])
+
+	u = tbl.Update(999, 1)
+	u.Set("name", "bar")
+	u.AddConditionExist("id")
+	_, err = u.Exec()
+	assert.Error(err, "conditional check should fail on non-exist item")
+}
+
+func TestUpdateItemSpecialAttributeName(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := getUpdateTestTable()
+	u := tbl.Update(100, 1)
+	u.Set("user-id", "foo")
+	u.Set("user id", "bar")
+	u.SetIfNotExists("count", 1)
+	u.Increment("count", 2)
+	assert.Equal("SET #u0 = :u0, #u1 = :u1, #u2 = if_not_exists(#u2, :u2), #u2 = if_not_exists(#u2, :u3) + :u4", *u.FormatUpdate())
+
+	names := u.FormatNames()
+	assert.Len(names, 3)
+	assert.Equal("user-id", *names["#u0"])
+	assert.Equal("user id", *names["#u1"])
+	values := u.FormatValues()
+	assert.Len(values, 5)
+	assert.Equal("1", *values[":u2"].N)
+	assert.Equal("2", *values[":u4"].N)
+
+	tbl = getMemoryTestTable(t)
+	putMemoryTestItem(tbl, 1, 1, "a", "")
+	assert.NoError(tbl.Put())
+
+	u = tbl.Update(1, 1)
+	u.Set("user-id", "foo")
+	u.Set("a.b", "bar")
+	u.Increment("login-count", 1)
+	_, err := u.Exec()
+	assert.NoError(err)
+
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal("foo", result["user-id"])
+	assert.Equal("bar", result["a.b"])
+	assert.Equal(1, result["login-count"])
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,6 +59,52 @@ func TestUpdateWriteThroughput(t *testing.T) {
 	assert.Equal(w, design.writeCapacity)
 }
 
+func TestUpdateBillingMode(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+
+	err := tbl.UpdateBillingMode(BillingModePayPerRequest)
+	assert.NoError(err)
+	assert.True(tbl.GetDesign().IsPayPerRequest())
+	assert.Equal(int64(0), tbl.GetDesign().GetReadCapacity())
+
+	err = tbl.UpdateBillingMode(BillingModeProvisioned)
+	assert.Error(err, "throughput is required for PROVISIONED")
+	assert.True(tbl.GetDesign().IsPayPerRequest())
+	tbl.GetDesign().SetThroughput(3, 0)
+	err = tbl.UpdateBillingMode(BillingModeProvisioned)
+	assert.Error(err, "throughput is required for PROVISIONED")
+
+	tbl.GetDesign().SetThroughput(3, 4)
+	err = tbl.UpdateBillingMode(BillingModeProvisioned)
+	assert.NoError(err)
+	design := tbl.GetDesign()
+	assert.False(design.IsPayPerRequest())
+	assert.Equal(int64(3), design.GetReadCapacity())
+	assert.Equal(int64(4), design.GetWriteCapacity())
+}
+
+func TestTTL(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+
+	ttl, err := tbl.DescribeTTL()
+	assert.NoError(err)
+	assert.False(ttl.IsEnabled())
+	assert.NoError(tbl.DisableTTL(), "disabled TTL should be ignored")
+
+	assert.NoError(tbl.EnableTTL("expired_at"))
+	ttl, err = tbl.DescribeTTL()
+	assert.NoError(err)
+	assert.True(ttl.IsEnabled())
+	assert.Equal("expired_at", ttl.AttributeName)
+
+	assert.NoError(tbl.DisableTTL())
+	ttl, err = tbl.DescribeTTL()
+	assert.NoError(err)
+	assert.Equal(TTLStatusDisabled, ttl.Status)
+}
+
 func TestAddItem(t *testing.T) {
 	assert := assert.New(t)
 
@@ -130,6 +177,29 @@ func TestBatchPut(t *testing.T) {
 	tbl.AddItem(item)
 	err := tbl.BatchPut()
 	assert.Error(err)
+	assert.Len(tbl.GetErrorItems(), 0)
+}
+
+func TestAddErrorWriteRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	tbl := &Table{nameWithPrefix: "foo_table"}
+	assert.Len(tbl.GetErrorItems(), 0)
+
+	item := NewPutItem()
+	item.AddAttribute("id", 100)
+	item.AddAttribute("time", 1)
+	tbl.addErrorWriteRequests([]*SDK.WriteRequest{
+		{PutRequest: &SDK.PutRequest{Item: item.data}},
+		{DeleteRequest: &SDK.DeleteRequest{}},
+		nil,
+	})
+
+	list := tbl.GetErrorItems()
+	assert.Len(list, 1)
+	assert.Equal("foo_table", *list[0].TableName)
+	assert.Equal("100", *list[0].Item["id"].N)
+	assert.Equal("1", *list[0].Item["time"].N)
 }
 
 func TestGetOne(t *testing.T) {
@@ -144,6 +214,38 @@ func TestGetOne(t *testing.T) {
 	assert.Equal(1, result["time"])
 }
 
+func TestPutStructAndGetOneInto(t *testing.T) {
+	assert := assert.New(t)
+	resetTestTable(t)
+	tbl := getTestTable(t)
+
+	err := tbl.PutStruct(testStructItem{
+		ID:     100,
+		Time:   1,
+		Name:   "foo",
+		Tags:   []string{"a", "b"},
+		Nested: testStructNested{Value: "bar"},
+	})
+	assert.NoError(err)
+
+	var v testStructItem
+	ok, err := tbl.GetOneInto(&v, 100, 1)
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal("foo", v.Name)
+	assert.Equal([]string{"a", "b"}, v.Tags)
+	assert.Equal("bar", v.Nested.Value)
+
+	ok, err = tbl.GetOneInto(&v, 999, 1)
+	assert.NoError(err)
+	assert.False(ok)
+
+	err = tbl.PutStruct(struct {
+		Name string `dynamodb:"name"`
+	}{Name: "foo"})
+	assert.Error(err, "item without primary keys should be error")
+}
+
 func TestScan(t *testing.T) {
 	assert := assert.New(t)
 	resetTestTable(t)
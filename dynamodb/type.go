@@ -8,6 +8,86 @@ import (
 	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
 )
 
+// BackupSummary contains details for the backup.
+type BackupSummary struct {
+	BackupARN              string
+	BackupName             string
+	BackupStatus           string
+	BackupType             string
+	BackupSizeBytes        int64
+	BackupCreationDateTime time.Time
+	BackupExpiryDateTime   time.Time
+	TableARN               string
+	TableID                string
+	TableName              string
+}
+
+// NewBackupSummary creates BackupSummary from SDK's output.
+func NewBackupSummary(out *SDK.BackupSummary) BackupSummary {
+	v := BackupSummary{}
+	if out == nil {
+		return v
+	}
+
+	if out.BackupArn != nil {
+		v.BackupARN = *out.BackupArn
+	}
+	if out.BackupName != nil {
+		v.BackupName = *out.BackupName
+	}
+	if out.BackupStatus != nil {
+		v.BackupStatus = *out.BackupStatus
+	}
+	if out.BackupType != nil {
+		v.BackupType = *out.BackupType
+	}
+	if out.BackupSizeBytes != nil {
+		v.BackupSizeBytes = *out.BackupSizeBytes
+	}
+	if out.BackupCreationDateTime != nil {
+		v.BackupCreationDateTime = *out.BackupCreationDateTime
+	}
+	if out.BackupExpiryDateTime != nil {
+		v.BackupExpiryDateTime = *out.BackupExpiryDateTime
+	}
+	if out.TableArn != nil {
+		v.TableARN = *out.TableArn
+	}
+	if out.TableId != nil {
+		v.TableID = *out.TableId
+	}
+	if out.TableName != nil {
+		v.TableName = *out.TableName
+	}
+	return v
+}
+
+// NewBackupSummaryFromDetails creates BackupSummary from SDK's BackupDetails.
+func NewBackupSummaryFromDetails(out *SDK.BackupDetails) BackupSummary {
+	if out == nil {
+		return BackupSummary{}
+	}
+	return NewBackupSummary(&SDK.BackupSummary{
+		BackupArn:              out.BackupArn,
+		BackupName:             out.BackupName,
+		BackupStatus:           out.BackupStatus,
+		BackupType:             out.BackupType,
+		BackupSizeBytes:        out.BackupSizeBytes,
+		BackupCreationDateTime: out.BackupCreationDateTime,
+		BackupExpiryDateTime:   out.BackupExpiryDateTime,
+	})
+}
+
+// IsEmpty checks if the data is empty or not.
+func (s BackupSummary) IsEmpty() bool {
+	switch {
+	case s.BackupARN != "",
+		s.BackupName != "":
+		return false
+	}
+	return true
+}
+
 // BillingModeSummary contains the details for the read/write capacity mode.
 type BillingModeSummary struct {
 	BillingMode               string
@@ -274,6 +354,37 @@ func NewLSIDescriptionList(list []*SDK.LocalSecondaryIndexDescription) []LSIDesc
 	return result
 }
 
+// PointInTimeRecoveryDescription contains the status and the restorable period of point-in-time recovery.
+type PointInTimeRecoveryDescription struct {
+	Status                     string
+	EarliestRestorableDateTime time.Time
+	LatestRestorableDateTime   time.Time
+}
+
+// NewPointInTimeRecoveryDescription creates PointInTimeRecoveryDescription from SDK's output.
+func NewPointInTimeRecoveryDescription(out *SDK.PointInTimeRecoveryDescription) PointInTimeRecoveryDescription {
+	v := PointInTimeRecoveryDescription{}
+	if out == nil {
+		return v
+	}
+
+	if out.PointInTimeRecoveryStatus != nil {
+		v.Status = *out.PointInTimeRecoveryStatus
+	}
+	if out.EarliestRestorableDateTime != nil {
+		v.EarliestRestorableDateTime = *out.EarliestRestorableDateTime
+	}
+	if out.LatestRestorableDateTime != nil {
+		v.LatestRestorableDateTime = *out.LatestRestorableDateTime
+	}
+	return v
+}
+
+// IsEnabled checks if point-in-time recovery is enabled or not.
+func (d PointInTimeRecoveryDescription) IsEnabled() bool {
+	return d.Status == PITRStatusEnabled
+}
+
 // Projection represents attributes that are copied (projected) from the table into an index.
 type Projection struct {
 	NonKeyAttributes []string
@@ -497,6 +608,33 @@ func (s StreamSpecification) IsEmpty() bool {
 	return true
 }
 
+// TimeToLiveDescription contains the status of Time to Live.
+type TimeToLiveDescription struct {
+	AttributeName string
+	Status        string
+}
+
+// NewTimeToLiveDescription creates TimeToLiveDescription from SDK's output.
+func NewTimeToLiveDescription(out *SDK.TimeToLiveDescription) TimeToLiveDescription {
+	v := TimeToLiveDescription{}
+	if out == nil {
+		return v
+	}
+
+	if out.AttributeName != nil {
+		v.AttributeName = *out.AttributeName
+	}
+	if out.TimeToLiveStatus != nil {
+		v.Status = *out.TimeToLiveStatus
+	}
+	return v
+}
+
+// IsEnabled checks if Time to Live is enabled or not.
+func (d TimeToLiveDescription) IsEnabled() bool {
+	return d.Status == TTLStatusEnabled || d.Status == TTLStatusEnabling
+}
+
 type KeysAndAttributes struct {
 	AttributesToGet          []string
 	ConsistentRead           bool
@@ -76,6 +76,7 @@ func NewTableDescription(out *SDK.TableDescription) TableDescription {
 	v.LocalSecondaryIndexes = NewLSIDescriptionList(out.LocalSecondaryIndexes)
 	v.ProvisionedThroughput = NewProvisionedThroughputDescription(out.ProvisionedThroughput)
 	v.RestoreSummary = NewRestoreSummary(out.RestoreSummary)
+	v.SSEDescription = NewSSEDescription(out.SSEDescription)
 	v.StreamSpecification = NewStreamSpecification(out.StreamSpecification)
 	return v
 }
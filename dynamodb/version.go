@@ -0,0 +1,155 @@
+package dynamodb
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	SDK "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// tagOptionVersion is the option of struct tag for the version attribute. e.g.) `dynamodb:"version,version"`
+const tagOptionVersion = "version"
+
+// ErrVersionConflict is returned when the stored version does not equal the expected version on optimistic locking.
+var ErrVersionConflict = errors.New("version conflict: the item was updated by another writer")
+
+// IsVersionConflict checks if the error is caused by the version conflict or not.
+func IsVersionConflict(err error) bool {
+	return err == ErrVersionConflict
+}
+
+func isConditionalCheckFailedError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == SDK.ErrCodeConditionalCheckFailedException
+}
+
+// applyVersion sets the condition that the stored version equals the version of the item,
+// and increments the version of the item.
+// The item without the version is treated as a new item, and the version starts from 1.
+// The base version is kept on the first call, so putting the same item again does not increment the version twice.
+func (item *PutItem) applyVersion(name string) int64 {
+	if !item.isVersionApplied {
+		item.isVersionApplied = true
+		item.baseVersion = getVersionValue(item.data[name])
+	}
+
+	if item.baseVersion == 0 {
+		item.versionCondition = ExprAttributeNotExists(name)
+	} else {
+		item.versionCondition = ExprEQ(name, item.baseVersion)
+	}
+
+	next := item.baseVersion + 1
+	item.data[name] = &SDK.AttributeValue{N: pointers.String(strconv.FormatInt(next, 10))}
+	return next
+}
+
+func getVersionValue(v *SDK.AttributeValue) int64 {
+	if v == nil || v.N == nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(*v.N, 10, 64)
+	return n
+}
+
+// findVersionField returns the index and the attribute name of the version field in the struct.
+func findVersionField(v interface{}, structTag string) (index int, name string, ok bool) {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return 0, "", false
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		parts := strings.Split(f.Tag.Get(structTag), ",")
+		for _, opt := range parts[1:] {
+			if opt != tagOptionVersion {
+				continue
+			}
+			name = parts[0]
+			if name == "" {
+				name = f.Name
+			}
+			return i, name, true
+		}
+	}
+	return 0, "", false
+}
+
+// findAttributeField returns the index of the struct field which is marshaled as the attribute name.
+func findAttributeField(v interface{}, structTag, name string) (index int, ok bool) {
+	rt := reflect.TypeOf(v)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		attr := strings.Split(f.Tag.Get(structTag), ",")[0]
+		switch {
+		case attr == "-":
+			continue
+		case attr == "":
+			attr = f.Name
+		}
+		if attr == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// setStructVersion sets the version of the put item into the version field of the struct pointer.
+// When the struct does not have the field with `version` option, the field of the version attribute is used.
+func setStructVersion(v interface{}, item *PutItem) {
+	name := item.versionAttribute
+	if name == "" {
+		return
+	}
+
+	index, tagName, ok := findVersionField(v, defaultResultTag)
+	switch {
+	case ok && tagName != name:
+		return
+	case !ok:
+		index, ok = findAttributeField(v, defaultResultTag, name)
+		if !ok {
+			return
+		}
+	}
+	setVersionField(v, index, getVersionValue(item.data[name]))
+}
+
+// setVersionField sets the version into the version field of the struct pointer.
+func setVersionField(v interface{}, index int, version int64) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+
+	f := rv.Field(index)
+	if !f.CanSet() {
+		return
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(version)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(uint64(version))
+	}
+}
@@ -0,0 +1,261 @@
+package dynamodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testVersionItem struct {
+	ID      int    `dynamodb:"id"`
+	Time    int    `dynamodb:"time"`
+	Name    string `dynamodb:"name"`
+	Version int64  `dynamodb:"ver,version"`
+}
+
+func TestVersionPut(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.GetDesign().SetVersionAttribute("version")
+
+	newItem := func(version int) *PutItem {
+		item := NewPutItem()
+		item.AddAttribute("id", 1)
+		item.AddAttribute("time", 1)
+		if version != 0 {
+			item.AddAttribute("version", version)
+		}
+		return item
+	}
+
+	tbl.AddItem(newItem(0))
+	assert.NoError(tbl.Put())
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(1, result["version"])
+
+	tbl.AddItem(newItem(0))
+	err = tbl.Put()
+	assert.Equal(ErrVersionConflict, err, "existing item should be conflict")
+	assert.True(IsVersionConflict(err))
+
+	tbl.AddItem(newItem(1))
+	assert.NoError(tbl.Put())
+	tbl.AddItem(newItem(1))
+	assert.Equal(ErrVersionConflict, tbl.Put(), "stale version should be conflict")
+
+	// version is kept after refreshing the design.
+	_, err = tbl.RefreshDesign()
+	assert.NoError(err)
+	assert.Equal("version", tbl.GetDesign().GetVersionAttribute())
+
+	tbl.AddItem(newItem(2))
+	err = tbl.BatchPut()
+	assert.Error(err)
+	assert.False(IsVersionConflict(err), "BatchPut does not support versioned items")
+
+	// the other errors are returned as the error list.
+	tbl.AddItem(newItem(1))
+	invalid := NewPutItem()
+	invalid.AddAttribute("id", 2)
+	tbl.AddItem(invalid)
+	err = tbl.Put()
+	assert.Error(err)
+	assert.False(IsVersionConflict(err))
+}
+
+func TestVersionPutRetry(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.GetDesign().SetVersionAttribute("version")
+
+	item := NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 1)
+	item.AddAttribute("version", 3)
+
+	// applying the version again does not increment the version and stack the conditions.
+	assert.True(tbl.applyVersion(item))
+	assert.True(tbl.applyVersion(item))
+	assert.Equal("4", *item.data["version"].N)
+	conds := item.toItemConditions("pc")
+	assert.Equal("#pc0 = :pc0", *conds.FormatCondition())
+	assert.Equal("3", *conds.FormatValues()[":pc0"].N)
+
+	// retrying the new item puts the same version.
+	item = NewPutItem()
+	item.AddAttribute("id", 1)
+	item.AddAttribute("time", 1)
+	tbl.AddItem(item)
+	assert.NoError(tbl.Put())
+	tbl.AddItem(item)
+	assert.Equal(ErrVersionConflict, tbl.Put(), "the item is already put")
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(1, result["version"])
+}
+
+func TestVersionPutStruct(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+
+	v := &testVersionItem{ID: 1, Time: 1, Name: "foo"}
+	assert.NoError(tbl.PutStruct(v))
+	assert.Equal(int64(1), v.Version)
+
+	stale := *v
+	v.Name = "bar"
+	assert.NoError(tbl.PutStruct(v))
+	assert.Equal(int64(2), v.Version)
+
+	err := tbl.PutStruct(&stale)
+	assert.Equal(ErrVersionConflict, err)
+	assert.Equal(int64(1), stale.Version, "version should not be changed on conflict")
+
+	result := testVersionItem{}
+	_, err = tbl.GetOneInto(&result, 1, 1)
+	assert.NoError(err)
+	assert.Equal("bar", result.Name)
+	assert.Equal(int64(2), result.Version)
+}
+
+func TestVersionPutStructWithDesign(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.GetDesign().SetVersionAttribute("version")
+
+	// the field of the version attribute is used without `version` option.
+	v := &struct {
+		ID      int    `dynamodb:"id"`
+		Time    int    `dynamodb:"time"`
+		Name    string `dynamodb:"name"`
+		Version int64  `dynamodb:"version"`
+	}{ID: 1, Time: 1, Name: "foo"}
+	assert.NoError(tbl.PutStruct(v))
+	assert.Equal(int64(1), v.Version)
+
+	v.Name = "bar"
+	assert.NoError(tbl.PutStruct(v))
+	assert.Equal(int64(2), v.Version)
+
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal("bar", result["name"])
+	assert.Equal(2, result["version"])
+}
+
+func TestVersionUpdate(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.GetDesign().SetVersionAttribute("version")
+
+	u := tbl.Update(1, 1)
+	u.Set("name", "foo")
+	u.ExpectVersion(0)
+	_, err := u.Exec()
+	assert.NoError(err)
+
+	u = tbl.Update(1, 1)
+	u.Set("name", "bar")
+	u.ExpectVersion(0)
+	_, err = u.Exec()
+	assert.Equal(ErrVersionConflict, err)
+
+	u = tbl.Update(1, 1)
+	u.Set("name", "bar")
+	u.ExpectVersion(1)
+	u.SetReturnValues(ReturnValueAllNew)
+	out, err := u.Exec()
+	assert.NoError(err)
+	assert.Equal(2, out.ToMap()["version"])
+	assert.Equal("bar", out.ToMap()["name"])
+
+	// the version is incremented without the condition.
+	u = tbl.Update(1, 1)
+	u.Set("name", "baz")
+	u.SetReturnValues(ReturnValueUpdatedNew)
+	out, err = u.Exec()
+	assert.NoError(err)
+	assert.Equal(3, out.ToMap()["version"])
+}
+
+func TestFindVersionField(t *testing.T) {
+	assert := assert.New(t)
+
+	index, name, ok := findVersionField(&testVersionItem{}, defaultResultTag)
+	assert.True(ok)
+	assert.Equal(3, index)
+	assert.Equal("ver", name)
+
+	_, name, ok = findVersionField(struct {
+		Rev int `dynamodb:",version"`
+	}{}, defaultResultTag)
+	assert.True(ok)
+	assert.Equal("Rev", name)
+
+	_, _, ok = findVersionField(testStructItem{}, defaultResultTag)
+	assert.False(ok)
+	_, _, ok = findVersionField(map[string]interface{}{}, defaultResultTag)
+	assert.False(ok)
+}
+
+func TestVersionTransaction(t *testing.T) {
+	assert := assert.New(t)
+	tbl := getMemoryTestTable(t)
+	tbl.GetDesign().SetVersionAttribute("version")
+	svc := tbl.service
+
+	newItem := func(version int) *PutItem {
+		item := NewPutItem()
+		item.AddAttribute("id", 1)
+		item.AddAttribute("time", 1)
+		if version != 0 {
+			item.AddAttribute("version", version)
+		}
+		return item
+	}
+
+	tx := svc.Transact()
+	tx.Put(tbl, newItem(0))
+	assert.NoError(tx.Commit())
+	result, err := tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(1, result["version"])
+
+	tx = svc.Transact()
+	tx.Put(tbl, newItem(0))
+	err = tx.Commit()
+	assert.Equal(ErrVersionConflict, err, "existing item should be conflict")
+
+	tx = svc.Transact()
+	tx.Put(tbl, newItem(1))
+	assert.NoError(tx.Commit())
+
+	tx = svc.Transact()
+	u := tbl.Update(1, 1)
+	u.Set("name", "foo")
+	u.ExpectVersion(1)
+	tx.Update(u)
+	assert.Equal(ErrVersionConflict, tx.Commit(), "stale version should be conflict")
+
+	tx = svc.Transact()
+	u = tbl.Update(1, 1)
+	u.Set("name", "foo")
+	u.ExpectVersion(2)
+	tx.Update(u)
+	assert.NoError(tx.Commit())
+	result, err = tbl.GetOne(1, 1)
+	assert.NoError(err)
+	assert.Equal(3, result["version"])
+	assert.Equal("foo", result["name"])
+
+	// the other failures are returned as TransactionCanceledError.
+	tx = svc.Transact()
+	tx.Put(tbl, newItem(1))
+	check := tx.ConditionCheck(tbl, 2, 1)
+	check.AddConditionEQ("name", "bar")
+	err = tx.Commit()
+	assert.False(IsVersionConflict(err))
+	_, ok := err.(*TransactionCanceledError)
+	assert.True(ok)
+}
@@ -3,7 +3,7 @@ module github.com/evalphobia/aws-sdk-go-wrapper
 go 1.14
 
 require (
-	github.com/aws/aws-sdk-go v1.29.23
+	github.com/aws/aws-sdk-go v1.42.11
 	github.com/stretchr/testify v1.5.1
 	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
 )
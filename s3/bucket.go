@@ -75,16 +75,7 @@ func (b *Bucket) addObject(obj *PutObject, path, acl string) {
 	b.putSpoolMu.Lock()
 	defer b.putSpoolMu.Unlock()
 
-	size := obj.Size()
-	req := &SDK.PutObjectInput{
-		ACL:           &acl,
-		Bucket:        &b.nameWithPrefix,
-		Body:          obj.data,
-		ContentLength: &size,
-		ContentType:   pointers.String(obj.FileType()),
-		Key:           pointers.String(path),
-	}
-	b.putSpool = append(b.putSpool, req)
+	b.putSpool = append(b.putSpool, obj.toInput(b.nameWithPrefix, path, acl))
 }
 
 // PutAll executes PutObject operation in the put spool.
@@ -111,17 +102,7 @@ func (b *Bucket) PutAll() error {
 
 // PutOne executes PutObject operation in the put spool.
 func (b *Bucket) PutOne(obj *PutObject, path, acl string) error {
-	size := obj.Size()
-	req := &SDK.PutObjectInput{
-		ACL:           &acl,
-		Bucket:        &b.nameWithPrefix,
-		Body:          obj.data,
-		ContentLength: &size,
-		ContentType:   pointers.String(obj.FileType()),
-		Key:           pointers.String(path),
-	}
-
-	_, err := b.service.client.PutObject(req)
+	_, err := b.service.client.PutObject(obj.toInput(b.nameWithPrefix, path, acl))
 	if err != nil {
 		b.service.Errorf("error on `PutObject` operation; bucket=%s; error=%s;", b.nameWithPrefix, err.Error())
 	}
@@ -134,6 +115,8 @@ func (b *Bucket) GetObjectByte(path string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer r.Close() // nolint:errcheck
+
 	buf := new(bytes.Buffer)
 	_, err = buf.ReadFrom(r)
 	if err != nil {
@@ -152,11 +135,13 @@ func (b *Bucket) GetObjectVersionID(path string) (string, error) {
 		b.service.Errorf("error on `GetObject` operation; bucket=%s; error=%s;", b.nameWithPrefix, err.Error())
 		return "", err
 	}
+	out.Body.Close() // nolint:errcheck,gosec
 	return aws.StringValue(out.VersionId), nil
 }
 
-// getObject fetches object from target S3 path
-func (b *Bucket) getObject(path string) (io.Reader, error) {
+// getObject fetches object from target S3 path.
+// The caller must close the returned body.
+func (b *Bucket) getObject(path string) (io.ReadCloser, error) {
 	out, err := b.service.client.GetObject(&SDK.GetObjectInput{
 		Bucket: &b.nameWithPrefix,
 		Key:    &path,
@@ -179,13 +164,11 @@ func (b *Bucket) GetSecretURL(path string) (string, error) {
 }
 
 // GetSecretURLWithExpire fetches a url of target S3 object w/ ACL permission (url expires in `expire` value seconds)
-// ** this isn't work **
 func (b *Bucket) GetSecretURLWithExpire(path string, expire int) (string, error) {
-	req, _ := b.service.client.GetObjectRequest(&SDK.GetObjectInput{
-		Bucket: pointers.String(b.nameWithPrefix),
-		Key:    pointers.String(path),
+	res, err := b.PresignGet(path, PresignRequest{
+		Expire: time.Duration(expire) * time.Second,
 	})
-	return req.Presign(time.Duration(expire) * time.Second)
+	return res.URL, err
 }
 
 // HeadObject executes HeadObject operation.
@@ -196,6 +179,42 @@ func (b *Bucket) HeadObject(path string) (*SDK.HeadObjectOutput, error) {
 	})
 }
 
+// GetObjectInfo returns the attributes of the object by HeadObject operation.
+// The keys of Metadata are lower-cased as S3 stores them.
+// Tags are not included; use GetObjectTags to fetch them.
+func (b *Bucket) GetObjectInfo(path string) (ObjectInfo, error) {
+	head, err := b.HeadObject(path)
+	if err != nil {
+		b.service.Errorf("error on `HeadObject` operation; bucket=%s; path=%s; error=%s;", b.nameWithPrefix, path, err.Error())
+		return ObjectInfo{}, err
+	}
+
+	info := NewObjectInfo(head)
+	info.Key = path
+	return info, nil
+}
+
+// GetObjectTags returns the tags of the object by GetObjectTagging operation.
+// It requires `s3:GetObjectTagging` permission.
+func (b *Bucket) GetObjectTags(path string) (map[string]string, error) {
+	out, err := b.service.client.GetObjectTagging(&SDK.GetObjectTaggingInput{
+		Bucket: pointers.String(b.nameWithPrefix),
+		Key:    pointers.String(path),
+	})
+	if err != nil {
+		b.service.Errorf("error on `GetObjectTagging` operation; bucket=%s; path=%s; error=%s;", b.nameWithPrefix, path, err.Error())
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(out.TagSet))
+	for _, t := range out.TagSet {
+		if t.Key != nil && t.Value != nil {
+			tags[*t.Key] = *t.Value
+		}
+	}
+	return tags, nil
+}
+
 // IsExists checks if an object exists on the given path.
 func (b *Bucket) IsExists(path string) bool {
 	_, err := b.HeadObject(path)
@@ -203,23 +222,14 @@ func (b *Bucket) IsExists(path string) bool {
 }
 
 // ListAllObjects fetches a list of all of the objects in the bucket and prefix.
+// Use Walk for the large number of objects.
 func (b *Bucket) ListAllObjects(prefix string) ([]Object, error) {
 	var contents []Object
-	nextToken := ""
-	for {
-		resp, err := b.ListObjectsV2(ListObjectsRequest{
-			Prefix:            prefix,
-			ContinuationToken: nextToken,
-		})
-		if err != nil {
-			return contents, err
-		}
-		contents = append(contents, resp.Contents...)
-		if !resp.IsTruncated {
-			return contents, nil
-		}
-		nextToken = resp.NextContinuationToken
-	}
+	err := b.Walk(prefix, func(obj Object) error {
+		contents = append(contents, obj)
+		return nil
+	})
+	return contents, err
 }
 
 // ListObjectsV2 executes ListObjectsV2 operation.
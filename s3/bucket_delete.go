@@ -0,0 +1,112 @@
+package s3
+
+import (
+	"errors"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// max number of keys in a DeleteObjects request.
+const deleteObjectsMax = 1000
+
+// DeleteObjects deletes the objects of the paths by DeleteObjects operation.
+// The paths are split into chunks of 1000 keys, and the failed keys are reported in the response and the error.
+func (b *Bucket) DeleteObjects(paths []string) (DeleteObjectsResponse, error) {
+	ids := make([]*SDK.ObjectIdentifier, len(paths))
+	for i, path := range paths {
+		ids[i] = &SDK.ObjectIdentifier{Key: pointers.String(path)}
+	}
+	return b.deleteObjects(ids)
+}
+
+// DeletePrefix deletes all of the objects under the prefix.
+// On a versioned bucket, delete markers are added and the old versions remain.
+func (b *Bucket) DeletePrefix(prefix string) (DeleteObjectsResponse, error) {
+	if prefix == "" {
+		return DeleteObjectsResponse{}, errors.New("prefix must not be empty")
+	}
+
+	list, err := b.ListAllObjects(prefix)
+	if err != nil {
+		return DeleteObjectsResponse{}, err
+	}
+
+	paths := make([]string, len(list))
+	for i, obj := range list {
+		paths[i] = obj.Key
+	}
+	return b.DeleteObjects(paths)
+}
+
+// PurgeAllVersions deletes all of the object versions and delete markers in the bucket.
+func (b *Bucket) PurgeAllVersions() (DeleteObjectsResponse, error) {
+	var res DeleteObjectsResponse
+	errList := newErrors()
+	err := b.service.client.ListObjectVersionsPages(&SDK.ListObjectVersionsInput{
+		Bucket: pointers.String(b.nameWithPrefix),
+	}, func(out *SDK.ListObjectVersionsOutput, _ bool) bool {
+		ids := make([]*SDK.ObjectIdentifier, 0, len(out.Versions)+len(out.DeleteMarkers))
+		for _, v := range out.Versions {
+			ids = append(ids, &SDK.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, v := range out.DeleteMarkers {
+			ids = append(ids, &SDK.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+
+		r, err := b.deleteObjects(ids)
+		res.Deleted = append(res.Deleted, r.Deleted...)
+		res.Errors = append(res.Errors, r.Errors...)
+		if err != nil {
+			errList.Add(err)
+		}
+		return true
+	})
+	if err != nil {
+		b.service.Errorf("error on `ListObjectVersions` operation; bucket=%s; error=%s;", b.nameWithPrefix, err.Error())
+		errList.Add(err)
+	}
+
+	if errList.HasError() {
+		return res, errList
+	}
+	return res, nil
+}
+
+func (b *Bucket) deleteObjects(ids []*SDK.ObjectIdentifier) (DeleteObjectsResponse, error) {
+	var res DeleteObjectsResponse
+	errList := newErrors()
+	cli := b.service.client
+	for start := 0; start < len(ids); start += deleteObjectsMax {
+		end := start + deleteObjectsMax
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		out, err := cli.DeleteObjects(&SDK.DeleteObjectsInput{
+			Bucket: pointers.String(b.nameWithPrefix),
+			Delete: &SDK.Delete{
+				Objects: ids[start:end],
+			},
+		})
+		if err != nil {
+			b.service.Errorf("error on `DeleteObjects` operation; bucket=%s; error=%s;", b.nameWithPrefix, err.Error())
+			errList.Add(err)
+			continue
+		}
+
+		r := NewDeleteObjectsResponse(out)
+		for _, e := range r.Errors {
+			b.service.Errorf("error on `DeleteObjects` operation; bucket=%s; key=%s; version_id=%s; code=%s; message=%s;", b.nameWithPrefix, e.Key, e.VersionID, e.Code, e.Message)
+			errList.Add(e)
+		}
+		res.Deleted = append(res.Deleted, r.Deleted...)
+		res.Errors = append(res.Errors, r.Errors...)
+	}
+
+	if errList.HasError() {
+		return res, errList
+	}
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package s3
+
+import (
+	"fmt"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const testVersionedBucketName = "test-versioned-bucket"
+
+func TestDeleteObjects(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	paths := []string{"delete_objects/a", "delete_objects/b", "deny/c"}
+	for _, path := range paths {
+		assert.NoError(b.PutOne(NewPutObjectString("data"), path, ACLPrivate))
+	}
+	// split into the chunks of 1000 keys.
+	for i := 0; i < 1000; i++ {
+		paths = append(paths, fmt.Sprintf("delete_objects/non_exist/%d", i))
+	}
+
+	res, err := b.DeleteObjects(paths)
+	assert.Error(err)
+	assert.Len(res.Deleted, 1002)
+	assert.Len(res.Errors, 1)
+	assert.Equal("deny/c", res.Errors[0].Key)
+	assert.Equal("AccessDenied", res.Errors[0].Code)
+	assert.False(b.IsExists("delete_objects/a"))
+	assert.False(b.IsExists("delete_objects/b"))
+	assert.True(b.IsExists("deny/c"))
+
+	res, err = b.DeleteObjects(nil)
+	assert.NoError(err)
+	assert.Empty(res.Deleted)
+}
+
+func TestDeletePrefix(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	for _, path := range []string{"delete_prefix/a", "delete_prefix/b/c", "delete_prefix_other"} {
+		assert.NoError(b.PutOne(NewPutObjectString("data"), path, ACLPrivate))
+	}
+
+	res, err := b.DeletePrefix("delete_prefix/")
+	assert.NoError(err)
+	assert.Len(res.Deleted, 2)
+	assert.False(b.IsExists("delete_prefix/a"))
+	assert.False(b.IsExists("delete_prefix/b/c"))
+	assert.True(b.IsExists("delete_prefix_other"))
+
+	_, err = b.DeletePrefix("")
+	assert.Error(err)
+}
+
+func TestPurgeAllVersions(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testVersionedBucketName)
+
+	svc := getTestClient(t)
+	_, err := svc.client.PutBucketVersioning(&SDK.PutBucketVersioningInput{
+		Bucket: pointers.String(testVersionedBucketName),
+		VersioningConfiguration: &SDK.VersioningConfiguration{
+			Status: pointers.String(SDK.BucketVersioningStatusEnabled),
+		},
+	})
+	assert.NoError(err)
+
+	b, err := svc.GetBucket(testVersionedBucketName)
+	assert.NoError(err)
+	_, err = b.PurgeAllVersions()
+	assert.NoError(err)
+
+	assert.NoError(b.PutOne(NewPutObjectString("v1"), "purge/a", ACLPrivate))
+	assert.NoError(b.PutOne(NewPutObjectString("v2"), "purge/a", ACLPrivate))
+	assert.NoError(b.PutOne(NewPutObjectString("v1"), "purge/b", ACLPrivate))
+
+	// add a delete marker.
+	res, err := b.DeletePrefix("purge/b")
+	assert.NoError(err)
+	assert.True(res.Deleted[0].DeleteMarker)
+
+	res, err = b.PurgeAllVersions()
+	assert.NoError(err)
+	assert.Len(res.Deleted, 4)
+
+	out, err := svc.client.ListObjectVersions(&SDK.ListObjectVersionsInput{
+		Bucket: pointers.String(testVersionedBucketName),
+	})
+	assert.NoError(err)
+	assert.Empty(out.Versions)
+	assert.Empty(out.DeleteMarkers)
+
+	// the bucket which has objects is deleted.
+	assert.NoError(b.PutOne(NewPutObjectString("v1"), "purge/a", ACLPrivate))
+	assert.Error(svc.ForceDeleteBucket(testVersionedBucketName), "bucket is not empty")
+	assert.NoError(svc.PurgeAndDeleteBucket(testVersionedBucketName))
+	has, err := svc.IsExistBucket(testVersionedBucketName)
+	assert.NoError(err)
+	assert.False(has)
+}
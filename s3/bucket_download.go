@@ -0,0 +1,275 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const (
+	defaultDownloadPartSize    = 8 * 1024 * 1024
+	defaultDownloadConcurrency = 4
+)
+
+// Download downloads the object of the path into the writer by parallel ranged GET requests.
+// Each range is requested with If-Match on the ETag, so the download fails when the object is modified on the way.
+func (b *Bucket) Download(ctx context.Context, path string, w io.WriterAt, opt ...DownloadRequest) (DownloadResponse, error) {
+	var o DownloadRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+	o.Bucket = b.nameWithPrefix
+	o.Key = path
+
+	res, err := b.download(ctx, w, o)
+	if err != nil {
+		b.service.Errorf("error on `Download`; bucket=%s; path=%s; error=%s;", b.nameWithPrefix, path, err.Error())
+	}
+	return res, err
+}
+
+func (b *Bucket) download(ctx context.Context, w io.WriterAt, o DownloadRequest) (DownloadResponse, error) {
+	head, err := b.service.client.HeadObjectWithContext(ctx, o.ToHeadObjectInput())
+	if err != nil {
+		return DownloadResponse{}, err
+	}
+	res := NewDownloadResponse(head)
+	size := res.Size
+
+	// fix the object to read consistent content.
+	if res.ETag != "" {
+		o.IfMatch = res.ETag
+	}
+	if res.VersionID != "" {
+		o.VersionID = res.VersionID
+	}
+
+	partSize := o.PartSize
+	if partSize <= 0 {
+		partSize = defaultDownloadPartSize
+	}
+	concurrency := o.Concurrency
+	if concurrency < 1 {
+		concurrency = defaultDownloadConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	rangeCh := make(chan int64)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, partSize)
+			for start := range rangeCh {
+				end := start + partSize
+				if end > size {
+					end = size
+				}
+				err := b.downloadRange(ctx, w, o, start, buf[:end-start])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+loop:
+	for start := int64(0); start < size; start += partSize {
+		select {
+		case rangeCh <- start:
+			res.Parts++
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(rangeCh)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return res, firstErr
+}
+
+// downloadRange downloads the range of the object into the buffer and writes it to the writer.
+func (b *Bucket) downloadRange(ctx context.Context, w io.WriterAt, o DownloadRequest, start int64, buf []byte) error {
+	in := o.ToGetObjectInput()
+	in.SetRange(fmt.Sprintf("bytes=%d-%d", start, start+int64(len(buf))-1))
+
+	out, err := b.service.client.GetObjectWithContext(ctx, in)
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close() // nolint:errcheck
+
+	if _, err := io.ReadFull(out.Body, buf); err != nil {
+		return err
+	}
+	_, err = w.WriteAt(buf, start)
+	return err
+}
+
+// OpenReader returns ObjectReader of the object.
+// ObjectReader reads the object lazily and issues Range request on Seek.
+func (b *Bucket) OpenReader(path string) (*ObjectReader, error) {
+	out, err := b.HeadObject(path)
+	if err != nil {
+		b.service.Errorf("error on `HeadObject` operation; bucket=%s; path=%s; error=%s;", b.nameWithPrefix, path, err.Error())
+		return nil, err
+	}
+
+	r := &ObjectReader{
+		bucket: b,
+		path:   path,
+	}
+	if out.ContentLength != nil {
+		r.size = *out.ContentLength
+	}
+	if out.ETag != nil {
+		r.etag = *out.ETag
+	}
+	if out.VersionId != nil {
+		r.versionID = *out.VersionId
+	}
+	return r, nil
+}
+
+// ObjectReader is io.ReadSeekCloser of S3 object.
+// Every request has If-Match on the ETag of the object at opening, to read the consistent object.
+type ObjectReader struct {
+	bucket    *Bucket
+	path      string
+	size      int64
+	etag      string
+	versionID string
+
+	offset int64
+	body   io.ReadCloser
+	closed bool
+}
+
+// Size returns the size of the object.
+func (r *ObjectReader) Size() int64 {
+	return r.size
+}
+
+// ETag returns the ETag of the object.
+func (r *ObjectReader) ETag() string {
+	return r.etag
+}
+
+// Read reads the object from the current offset.
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	switch {
+	case r.closed:
+		return 0, errors.New("ObjectReader is already closed")
+	case r.offset >= r.size:
+		return 0, io.EOF
+	case len(p) == 0:
+		return 0, nil
+	}
+
+	if r.body == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	if err == io.EOF && r.offset < r.size {
+		// the body is closed before the end of the object.
+		r.closeBody() // nolint:errcheck
+		err = nil
+		if n == 0 {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	return n, err
+}
+
+// open requests the object from the current offset.
+func (r *ObjectReader) open() error {
+	in := &SDK.GetObjectInput{
+		Bucket: pointers.String(r.bucket.nameWithPrefix),
+		Key:    pointers.String(r.path),
+		Range:  pointers.String(fmt.Sprintf("bytes=%d-", r.offset)),
+	}
+	if r.etag != "" {
+		in.SetIfMatch(r.etag)
+	}
+	if r.versionID != "" {
+		in.SetVersionId(r.versionID)
+	}
+
+	out, err := r.bucket.service.client.GetObject(in)
+	if err != nil {
+		r.bucket.service.Errorf("error on `GetObject` operation; bucket=%s; path=%s; error=%s;", r.bucket.nameWithPrefix, r.path, err.Error())
+		return err
+	}
+	r.body = out.Body
+	return nil
+}
+
+// Seek sets the offset for the next Read.
+// The current request is discarded when the offset is changed, and the new Range request is issued on the next Read.
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	if r.closed {
+		return 0, errors.New("ObjectReader is already closed")
+	}
+
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = r.offset + offset
+	case io.SeekEnd:
+		next = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: [%d]", whence)
+	}
+	if next < 0 {
+		return 0, fmt.Errorf("negative position: [%d]", next)
+	}
+
+	if next != r.offset {
+		r.closeBody() // nolint:errcheck
+		r.offset = next
+	}
+	return next, nil
+}
+
+// Close closes the current request.
+func (r *ObjectReader) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.closeBody()
+}
+
+func (r *ObjectReader) closeBody() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
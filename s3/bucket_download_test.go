@@ -0,0 +1,97 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	data := testUploadData(3*1024*1024 + 100)
+	_, err = b.Upload(context.Background(), "download_test", bytes.NewReader(data))
+	assert.NoError(err)
+
+	f, err := ioutil.TempFile("", "s3_download")
+	assert.NoError(err)
+	defer os.Remove(f.Name()) // nolint:errcheck
+	defer f.Close()           // nolint:errcheck
+
+	res, err := b.Download(context.Background(), "download_test", f, DownloadRequest{
+		PartSize:    1024 * 1024,
+		Concurrency: 3,
+	})
+	assert.NoError(err)
+	assert.Equal(int64(len(data)), res.Size)
+	assert.Equal(4, res.Parts)
+	assert.NotEmpty(res.ETag)
+
+	result, err := ioutil.ReadFile(f.Name())
+	assert.NoError(err)
+	assert.Equal(data, result)
+
+	_, err = b.Download(context.Background(), "download_not_found", f)
+	assert.Error(err)
+}
+
+func TestOpenReader(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	data := testUploadData(1024)
+	_, err = b.Upload(context.Background(), "open_reader_test", bytes.NewReader(data))
+	assert.NoError(err)
+
+	r, err := b.OpenReader("open_reader_test")
+	assert.NoError(err)
+	assert.Equal(int64(len(data)), r.Size())
+
+	result, err := ioutil.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal(data, result)
+
+	pos, err := r.Seek(100, io.SeekStart)
+	assert.NoError(err)
+	assert.Equal(int64(100), pos)
+	buf := make([]byte, 10)
+	_, err = io.ReadFull(r, buf)
+	assert.NoError(err)
+	assert.Equal(data[100:110], buf)
+
+	pos, err = r.Seek(-4, io.SeekEnd)
+	assert.NoError(err)
+	assert.Equal(int64(1020), pos)
+	result, err = ioutil.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal(data[1020:], result)
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.Error(err)
+
+	// the modified object is not read.
+	_, err = r.Seek(0, io.SeekStart)
+	assert.NoError(err)
+	_, err = b.Upload(context.Background(), "open_reader_test", bytes.NewReader([]byte("modified")))
+	assert.NoError(err)
+	_, err = r.Read(buf)
+	assert.Error(err)
+
+	assert.NoError(r.Close())
+	_, err = r.Read(buf)
+	assert.Error(err)
+}
@@ -0,0 +1,108 @@
+package s3
+
+import (
+	"errors"
+	"strings"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+// ErrStopWalk is used as a return value from the walk functions to stop the iteration without error.
+var ErrStopWalk = errors.New("stop walk")
+
+// Walk calls fn for each of the objects under the prefix in the order of the keys.
+// The objects are fetched page by page, so the whole list is not loaded into memory.
+// Walk stops when fn returns an error, and returns the error except ErrStopWalk.
+func (b *Bucket) Walk(prefix string, fn func(obj Object) error) error {
+	nextToken := ""
+	for {
+		resp, err := b.ListObjectsV2(ListObjectsRequest{
+			Prefix:            prefix,
+			ContinuationToken: nextToken,
+		})
+		if err != nil {
+			b.service.Errorf("error on `ListObjectsV2` operation; bucket=%s; prefix=%s; error=%s;", b.nameWithPrefix, prefix, err.Error())
+			return err
+		}
+
+		for _, obj := range resp.Contents {
+			if err := fn(obj); err != nil {
+				if err == ErrStopWalk {
+					return nil
+				}
+				return err
+			}
+		}
+		if !resp.IsTruncated {
+			return nil
+		}
+		nextToken = resp.NextContinuationToken
+	}
+}
+
+// ListDir returns the sub directories and the objects just under the prefix, like `ls`.
+// The prefix is treated as a directory. (e.g. "foo" lists "foo/")
+func (b *Bucket) ListDir(prefix string) (ListDirResponse, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	res := ListDirResponse{Prefix: prefix}
+	nextToken := ""
+	for {
+		resp, err := b.ListObjectsV2(ListObjectsRequest{
+			Prefix:            prefix,
+			Delimiter:         "/",
+			ContinuationToken: nextToken,
+		})
+		if err != nil {
+			b.service.Errorf("error on `ListObjectsV2` operation; bucket=%s; prefix=%s; error=%s;", b.nameWithPrefix, prefix, err.Error())
+			return res, err
+		}
+
+		res.Dirs = append(res.Dirs, resp.CommonPrefixes...)
+		for _, obj := range resp.Contents {
+			// skip the placeholder of the directory itself.
+			if obj.Key == prefix {
+				continue
+			}
+			res.Objects = append(res.Objects, obj)
+		}
+		if !resp.IsTruncated {
+			return res, nil
+		}
+		nextToken = resp.NextContinuationToken
+	}
+}
+
+// WalkVersions calls fn for each of the object versions and delete markers under the prefix.
+// The versions of a key are in the order from the latest.
+// WalkVersions stops when fn returns an error, and returns the error except ErrStopWalk.
+func (b *Bucket) WalkVersions(prefix string, fn func(v ObjectVersion) error) error {
+	in := &SDK.ListObjectVersionsInput{
+		Bucket: pointers.String(b.nameWithPrefix),
+	}
+	if prefix != "" {
+		in.SetPrefix(prefix)
+	}
+
+	var fnErr error
+	err := b.service.client.ListObjectVersionsPages(in, func(out *SDK.ListObjectVersionsOutput, _ bool) bool {
+		for _, v := range NewObjectVersions(out) {
+			if fnErr = fn(v); fnErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		b.service.Errorf("error on `ListObjectVersions` operation; bucket=%s; prefix=%s; error=%s;", b.nameWithPrefix, prefix, err.Error())
+		return err
+	}
+	if fnErr == ErrStopWalk {
+		return nil
+	}
+	return fnErr
+}
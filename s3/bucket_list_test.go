@@ -0,0 +1,150 @@
+package s3
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const testListBucketName = "test-list-bucket"
+
+func TestWalk(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testListBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testListBucketName)
+	assert.NoError(err)
+	_, err = b.PurgeAllVersions()
+	assert.NoError(err)
+
+	// more than a page of ListObjectsV2.
+	paths := make([]string, 0, 1005)
+	for i := 0; i < 1005; i++ {
+		paths = append(paths, fmt.Sprintf("walk/%04d", i))
+	}
+	for _, path := range paths {
+		assert.NoError(b.PutOne(NewPutObjectString("data"), path, ACLPrivate))
+	}
+	assert.NoError(b.PutOne(NewPutObjectString("data"), "other", ACLPrivate))
+
+	var keys []string
+	err = b.Walk("walk/", func(obj Object) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(paths, keys)
+
+	// stop the iteration.
+	count := 0
+	err = b.Walk("walk/", func(obj Object) error {
+		count++
+		if count == 3 {
+			return ErrStopWalk
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(3, count)
+
+	errTest := errors.New("test error")
+	err = b.Walk("walk/", func(obj Object) error {
+		return errTest
+	})
+	assert.Equal(errTest, err)
+
+	list, err := b.ListAllObjects("walk/")
+	assert.NoError(err)
+	assert.Len(list, 1005)
+}
+
+func TestListDir(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testListBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testListBucketName)
+	assert.NoError(err)
+
+	for _, path := range []string{"dir/", "dir/a.txt", "dir/b.txt", "dir/sub1/c.txt", "dir/sub2/d/e.txt", "dir2/f.txt"} {
+		assert.NoError(b.PutOne(NewPutObjectString("data"), path, ACLPrivate))
+	}
+
+	res, err := b.ListDir("dir")
+	assert.NoError(err)
+	assert.Equal("dir/", res.Prefix)
+	assert.Equal([]string{"dir/sub1/", "dir/sub2/"}, res.Dirs)
+	assert.Len(res.Objects, 2)
+	assert.Equal("dir/a.txt", res.Objects[0].Key)
+	assert.Equal("dir/b.txt", res.Objects[1].Key)
+
+	res, err = b.ListDir("dir/sub2/")
+	assert.NoError(err)
+	assert.Equal([]string{"dir/sub2/d/"}, res.Dirs)
+	assert.Empty(res.Objects)
+
+	res, err = b.ListDir("")
+	assert.NoError(err)
+	assert.Contains(res.Dirs, "dir/")
+	assert.Contains(res.Dirs, "dir2/")
+}
+
+func TestWalkVersions(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testVersionedBucketName)
+
+	svc := getTestClient(t)
+	_, err := svc.client.PutBucketVersioning(&SDK.PutBucketVersioningInput{
+		Bucket: pointers.String(testVersionedBucketName),
+		VersioningConfiguration: &SDK.VersioningConfiguration{
+			Status: pointers.String(SDK.BucketVersioningStatusEnabled),
+		},
+	})
+	assert.NoError(err)
+
+	b, err := svc.GetBucket(testVersionedBucketName)
+	assert.NoError(err)
+	_, err = b.PurgeAllVersions()
+	assert.NoError(err)
+
+	assert.NoError(b.PutOne(NewPutObjectString("v1"), "versions/a", ACLPrivate))
+	assert.NoError(b.PutOne(NewPutObjectString("v2-"), "versions/a", ACLPrivate))
+	assert.NoError(b.PutOne(NewPutObjectString("v1"), "versions/b", ACLPrivate))
+	assert.NoError(b.DeleteObject("versions/b"))
+
+	var list []ObjectVersion
+	err = b.WalkVersions("versions/", func(v ObjectVersion) error {
+		list = append(list, v)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(list, 4)
+
+	assert.Equal("versions/a", list[0].Key)
+	assert.True(list[0].IsLatest)
+	assert.Equal(int64(3), list[0].Size)
+	assert.NotEmpty(list[0].VersionID)
+	assert.Equal("versions/a", list[1].Key)
+	assert.False(list[1].IsLatest)
+
+	assert.Equal("versions/b", list[2].Key)
+	assert.True(list[2].IsLatest)
+	assert.True(list[2].IsDeleteMarker)
+	assert.Equal("versions/b", list[3].Key)
+	assert.False(list[3].IsDeleteMarker)
+
+	// stop the iteration.
+	count := 0
+	err = b.WalkVersions("versions/", func(v ObjectVersion) error {
+		count++
+		return ErrStopWalk
+	})
+	assert.NoError(err)
+	assert.Equal(1, count)
+}
@@ -0,0 +1,234 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	postPolicyAlgorithm = "AWS4-HMAC-SHA256"
+	postPolicyService   = "s3"
+)
+
+// PresignGet returns presigned URL to get the object.
+func (b *Bucket) PresignGet(path string, opt ...PresignRequest) (PresignResponse, error) {
+	var o PresignRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	in := o.ToGetObjectInput()
+	in.SetBucket(b.nameWithPrefix)
+	in.SetKey(path)
+	req, _ := b.service.client.GetObjectRequest(in)
+	return b.presign("GetObject", path, req, o)
+}
+
+// PresignPut returns presigned URL to put the object.
+// The client must send the headers of `SignedHeader` along with the request.
+func (b *Bucket) PresignPut(path string, opt ...PresignRequest) (PresignResponse, error) {
+	var o PresignRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	in := o.ToPutObjectInput()
+	in.SetBucket(b.nameWithPrefix)
+	in.SetKey(path)
+	req, _ := b.service.client.PutObjectRequest(in)
+	return b.presign("PutObject", path, req, o)
+}
+
+// PresignDelete returns presigned URL to delete the object.
+func (b *Bucket) PresignDelete(path string, opt ...PresignRequest) (PresignResponse, error) {
+	var o PresignRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	in := o.ToDeleteObjectInput()
+	in.SetBucket(b.nameWithPrefix)
+	in.SetKey(path)
+	req, _ := b.service.client.DeleteObjectRequest(in)
+	return b.presign("DeleteObject", path, req, o)
+}
+
+func (b *Bucket) presign(op, path string, req *request.Request, o PresignRequest) (PresignResponse, error) {
+	if req.Error != nil {
+		b.service.Errorf("error on `%s` presign; bucket=%s; path=%s; error=%s;", op, b.nameWithPrefix, path, req.Error.Error())
+		return PresignResponse{}, req.Error
+	}
+	for k, v := range o.Headers {
+		req.HTTPRequest.Header.Set(k, v)
+	}
+
+	url, header, err := req.PresignRequest(b.getExpire(o.Expire))
+	if err != nil {
+		b.service.Errorf("error on `%s` presign; bucket=%s; path=%s; error=%s;", op, b.nameWithPrefix, path, err.Error())
+		return PresignResponse{}, err
+	}
+
+	// canonicalize the lower-case keys from the signer.
+	signedHeader := make(http.Header, len(header))
+	for k, vv := range header {
+		for _, v := range vv {
+			signedHeader.Add(k, v)
+		}
+	}
+	return PresignResponse{
+		URL:          url,
+		Method:       req.HTTPRequest.Method,
+		SignedHeader: signedHeader,
+	}, nil
+}
+
+// PresignPost returns URL and form fields to upload a file by POST from the browsers.
+// The value of `key` field can contain `${filename}` to use the name of the uploaded file.
+// When `KeyPrefix` is set, the browsers can change the key within the prefix.
+func (b *Bucket) PresignPost(key string, opt ...PostPolicyRequest) (PostPolicyResponse, error) {
+	var o PostPolicyRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	res, err := b.presignPost(key, o, time.Now().UTC())
+	if err != nil {
+		b.service.Errorf("error on `PresignPost`; bucket=%s; key=%s; error=%s;", b.nameWithPrefix, key, err.Error())
+	}
+	return res, err
+}
+
+func (b *Bucket) presignPost(key string, o PostPolicyRequest, now time.Time) (PostPolicyResponse, error) {
+	if o.KeyPrefix != "" && !strings.HasPrefix(key, o.KeyPrefix) {
+		return PostPolicyResponse{}, fmt.Errorf("key must start with KeyPrefix; key=[%s] prefix=[%s]", key, o.KeyPrefix)
+	}
+	if o.ContentLengthMax > 0 && o.ContentLengthMin > o.ContentLengthMax {
+		return PostPolicyResponse{}, fmt.Errorf("ContentLengthMin is larger than ContentLengthMax; min=[%d] max=[%d]", o.ContentLengthMin, o.ContentLengthMax)
+	}
+
+	cli := b.service.client
+	cred, err := cli.Config.Credentials.Get()
+	if err != nil {
+		return PostPolicyResponse{}, err
+	}
+	if cred.AccessKeyID == "" || cred.SecretAccessKey == "" {
+		return PostPolicyResponse{}, errors.New("credentials are empty")
+	}
+
+	region := cli.SigningRegion
+	if region == "" {
+		region = aws.StringValue(cli.Config.Region)
+	}
+	date := now.Format("20060102")
+	scope := strings.Join([]string{date, region, postPolicyService, "aws4_request"}, "/")
+	expiration := now.Add(b.getExpire(o.Expire))
+
+	fields := map[string]string{
+		"key":              key,
+		"x-amz-algorithm":  postPolicyAlgorithm,
+		"x-amz-credential": cred.AccessKeyID + "/" + scope,
+		"x-amz-date":       now.Format("20060102T150405Z"),
+	}
+	if cred.SessionToken != "" {
+		fields["x-amz-security-token"] = cred.SessionToken
+	}
+	optionalFields := map[string]string{
+		"acl":                          o.ACL,
+		"Cache-Control":                o.CacheControl,
+		"Content-Disposition":          o.ContentDisposition,
+		"Content-Type":                 o.ContentType,
+		"x-amz-server-side-encryption": o.ServerSideEncryption,
+		"x-amz-server-side-encryption-aws-kms-key-id": o.SSEKMSKeyID,
+		"x-amz-storage-class":                         o.StorageClass,
+		"success_action_redirect":                     o.SuccessActionRedirect,
+		"success_action_status":                       o.SuccessActionStatus,
+	}
+	for k, v := range optionalFields {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	for k, v := range o.Metadata {
+		fields["x-amz-meta-"+k] = v
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conditions := []interface{}{
+		map[string]string{"bucket": b.nameWithPrefix},
+	}
+	for _, k := range keys {
+		v := fields[k]
+		switch {
+		case k == "key" && o.KeyPrefix != "":
+			conditions = append(conditions, []string{"starts-with", "$key", o.KeyPrefix})
+		case k == "Content-Type" && o.ContentTypePrefix != "":
+			// use starts-with condition instead.
+		default:
+			conditions = append(conditions, map[string]string{k: v})
+		}
+	}
+	if o.ContentTypePrefix != "" {
+		conditions = append(conditions, []string{"starts-with", "$Content-Type", o.ContentTypePrefix})
+	}
+	if o.ContentLengthMax > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", o.ContentLengthMin, o.ContentLengthMax})
+	}
+
+	policy, err := json.Marshal(map[string]interface{}{
+		"expiration": expiration.Format("2006-01-02T15:04:05.000Z"),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return PostPolicyResponse{}, err
+	}
+
+	encodedPolicy := base64.StdEncoding.EncodeToString(policy)
+	fields["policy"] = encodedPolicy
+	fields["x-amz-signature"] = hex.EncodeToString(
+		hmacSHA256(signingKey(cred.SecretAccessKey, date, region), []byte(encodedPolicy)),
+	)
+
+	return PostPolicyResponse{
+		URL:        fmt.Sprintf("%s/%s", b.endpoint, b.nameWithPrefix),
+		Fields:     fields,
+		Expiration: expiration,
+	}, nil
+}
+
+func (b *Bucket) getExpire(expire time.Duration) time.Duration {
+	if expire > 0 {
+		return expire
+	}
+	return time.Duration(b.expireSecond) * time.Second
+}
+
+// signingKey derives the signing key of AWS Signature Version 4.
+func signingKey(secret, date, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(postPolicyService))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
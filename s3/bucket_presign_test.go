@@ -0,0 +1,157 @@
+package s3
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresignGet(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+	assert.NoError(b.PutOne(NewPutObjectString("presign content"), "presign_get", ACLPrivate))
+
+	res, err := b.PresignGet("presign_get", PresignRequest{
+		Expire:                     time.Minute,
+		ResponseContentDisposition: "attachment; filename=test.txt",
+		VersionID:                  "null",
+	})
+	assert.NoError(err)
+	assert.Equal("GET", res.Method)
+	assert.Contains(res.URL, testBaseURL+"/presign_get?")
+	assert.Contains(res.URL, "X-Amz-Expires=60")
+	assert.Contains(res.URL, "response-content-disposition=attachment")
+	assert.Contains(res.URL, "versionId=null")
+
+	res, err = b.PresignGet("presign_get")
+	assert.NoError(err)
+	resp, err := http.Get(res.URL)
+	assert.NoError(err)
+	defer resp.Body.Close() // nolint:errcheck
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal("presign content", string(body))
+}
+
+func TestPresignPutAndDelete(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	res, err := b.PresignPut("presign_put", PresignRequest{
+		ContentType: "text/plain",
+		Metadata:    map[string]string{"owner": "test"},
+		Headers:     map[string]string{"X-Amz-Meta-Custom": "custom"},
+	})
+	assert.NoError(err)
+	assert.Equal("PUT", res.Method)
+	assert.Equal("text/plain", res.SignedHeader.Get("Content-Type"))
+
+	req, err := http.NewRequest(res.Method, res.URL, strings.NewReader("put content"))
+	assert.NoError(err)
+	for k, v := range res.SignedHeader {
+		req.Header[k] = v
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(err)
+	resp.Body.Close() // nolint:errcheck,gosec
+	assert.Equal(http.StatusOK, resp.StatusCode)
+
+	data, err := b.GetObjectByte("presign_put")
+	assert.NoError(err)
+	assert.Equal("put content", string(data))
+
+	res, err = b.PresignDelete("presign_put")
+	assert.NoError(err)
+	assert.Equal("DELETE", res.Method)
+	req, err = http.NewRequest(res.Method, res.URL, nil)
+	assert.NoError(err)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(err)
+	resp.Body.Close() // nolint:errcheck,gosec
+	assert.False(b.IsExists("presign_put"))
+}
+
+func TestPresignPost(t *testing.T) {
+	assert := assert.New(t)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	res, err := b.presignPost("uploads/${filename}", PostPolicyRequest{
+		Expire:            time.Hour,
+		KeyPrefix:         "uploads/",
+		ContentLengthMin:  1,
+		ContentLengthMax:  1024,
+		ContentTypePrefix: "image/",
+		ACL:               ACLPrivate,
+		Metadata:          map[string]string{"owner": "test"},
+	}, now)
+	assert.NoError(err)
+	assert.Equal(testBaseURL, res.URL)
+	assert.Equal(now.Add(time.Hour), res.Expiration)
+	assert.Equal("uploads/${filename}", res.Fields["key"])
+	assert.Equal("AWS4-HMAC-SHA256", res.Fields["x-amz-algorithm"])
+	assert.Equal("access/20200102/us-east-1/s3/aws4_request", res.Fields["x-amz-credential"])
+	assert.Equal("20200102T030405Z", res.Fields["x-amz-date"])
+	assert.Equal("private", res.Fields["acl"])
+	assert.Equal("test", res.Fields["x-amz-meta-owner"])
+	assert.Len(res.Fields["x-amz-signature"], 64)
+
+	b64, err := base64.StdEncoding.DecodeString(res.Fields["policy"])
+	assert.NoError(err)
+	var policy struct {
+		Expiration string        `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}
+	assert.NoError(json.Unmarshal(b64, &policy))
+	assert.Equal("2020-01-02T04:04:05.000Z", policy.Expiration)
+	assert.Contains(policy.Conditions, map[string]interface{}{"bucket": testPutBucketName})
+	assert.Contains(policy.Conditions, []interface{}{"starts-with", "$key", "uploads/"})
+	assert.Contains(policy.Conditions, []interface{}{"starts-with", "$Content-Type", "image/"})
+	assert.Contains(policy.Conditions, []interface{}{"content-length-range", float64(1), float64(1024)})
+	assert.Contains(policy.Conditions, map[string]interface{}{"acl": "private"})
+
+	// the same policy has the same signature.
+	res2, err := b.presignPost("uploads/${filename}", PostPolicyRequest{
+		Expire:            time.Hour,
+		KeyPrefix:         "uploads/",
+		ContentLengthMin:  1,
+		ContentLengthMax:  1024,
+		ContentTypePrefix: "image/",
+		ACL:               ACLPrivate,
+		Metadata:          map[string]string{"owner": "test"},
+	}, now)
+	assert.NoError(err)
+	assert.Equal(res.Fields["x-amz-signature"], res2.Fields["x-amz-signature"])
+
+	_, err = b.PresignPost("other/file", PostPolicyRequest{KeyPrefix: "uploads/"})
+	assert.Error(err)
+	_, err = b.PresignPost("file", PostPolicyRequest{ContentLengthMin: 10, ContentLengthMax: 1})
+	assert.Error(err)
+}
+
+func TestSigningKey(t *testing.T) {
+	assert := assert.New(t)
+
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-post-example.html
+	policy := "eyAiZXhwaXJhdGlvbiI6ICIyMDE1LTEyLTMwVDEyOjAwOjAwLjAwMFoiLA0KICAiY29uZGl0aW9ucyI6IFsNCiAgICB7ImJ1Y2tldCI6ICJzaWd2NGV4YW1wbGVidWNrZXQifSwNCiAgICBbInN0YXJ0cy13aXRoIiwgIiRrZXkiLCAidXNlci91c2VyMS8iXSwNCiAgICB7ImFjbCI6ICJwdWJsaWMtcmVhZCJ9LA0KICAgIHsic3VjY2Vzc19hY3Rpb25fcmVkaXJlY3QiOiAiaHR0cDovL3NpZ3Y0ZXhhbXBsZWJ1Y2tldC5zMy5hbWF6b25hd3MuY29tL3N1Y2Nlc3NmdWxfdXBsb2FkLmh0bWwifSwNCiAgICBbInN0YXJ0cy13aXRoIiwgIiRDb250ZW50LVR5cGUiLCAiaW1hZ2UvIl0sDQogICAgeyJ4LWFtei1tZXRhLXV1aWQiOiAiMTQzNjUxMjM2NTEyNzQifSwNCiAgICB7IngtYW16LXNlcnZlci1zaWRlLWVuY3J5cHRpb24iOiAiQUVTMjU2In0sDQogICAgWyJzdGFydHMtd2l0aCIsICIkeC1hbXotbWV0YS10YWciLCAiIl0sDQoNCiAgICB7IngtYW16LWNyZWRlbnRpYWwiOiAiQUtJQUlPU0ZPRE5ON0VYQU1QTEUvMjAxNTEyMjkvdXMtZWFzdC0xL3MzL2F3czRfcmVxdWVzdCJ9LA0KICAgIHsieC1hbXotYWxnb3JpdGhtIjogIkFXUzQtSE1BQy1TSEEyNTYifSwNCiAgICB7IngtYW16LWRhdGUiOiAiMjAxNTEyMjlUMDAwMDAwWiIgfQ0KICBdDQp9"
+	key := signingKey("wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY", "20151229", "us-east-1")
+	assert.Equal("8afdbf4008c03f22c2cd3cdb72e4afbb1f6a588f3255ac628749a66d7f09699e", hex.EncodeToString(hmacSHA256(key, []byte(policy))))
+}
@@ -0,0 +1,505 @@
+package s3
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/errors"
+)
+
+// compare modes for sync.
+const (
+	// SyncCompareSizeAndModTime treats the file as changed when the size differs or the source is newer.
+	SyncCompareSizeAndModTime = "size_and_mtime"
+	// SyncCompareSize treats the file as changed only when the size differs.
+	SyncCompareSize = "size"
+	// SyncCompareChecksum treats the file as changed when the size or MD5/ETag differs.
+	SyncCompareChecksum = "checksum"
+)
+
+const defaultSyncConcurrency = 4
+
+// SyncFromDir uploads the changed files in the local directory to the prefix.
+func (b *Bucket) SyncFromDir(localDir, prefix string, opt ...SyncRequest) (SyncResponse, error) {
+	var o SyncRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	s := newSyncer(b, localDir, prefix, o)
+	res, err := s.syncFromDir()
+	if err != nil {
+		b.service.Errorf("error on `SyncFromDir`; bucket=%s; dir=%s; prefix=%s; error=%s;", b.nameWithPrefix, localDir, prefix, err.Error())
+	}
+	return res, err
+}
+
+// SyncToDir downloads the changed objects under the prefix to the local directory.
+// The modification time of the downloaded file is set to LastModified of the object.
+func (b *Bucket) SyncToDir(prefix, localDir string, opt ...SyncRequest) (SyncResponse, error) {
+	var o SyncRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+
+	s := newSyncer(b, localDir, prefix, o)
+	res, err := s.syncToDir()
+	if err != nil {
+		b.service.Errorf("error on `SyncToDir`; bucket=%s; prefix=%s; dir=%s; error=%s;", b.nameWithPrefix, prefix, localDir, err.Error())
+	}
+	return res, err
+}
+
+// syncer compares and transfers the files between the local directory and the prefix.
+type syncer struct {
+	bucket   *Bucket
+	localDir string
+	prefix   string
+	req      SyncRequest
+
+	mu      sync.Mutex
+	res     SyncResponse
+	errList *errors.Errors
+}
+
+// syncEntry is a file or an object to sync.
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+func newSyncer(b *Bucket, localDir, prefix string, o SyncRequest) *syncer {
+	if o.CompareMode == "" {
+		o.CompareMode = SyncCompareSizeAndModTime
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = defaultSyncConcurrency
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &syncer{
+		bucket:   b,
+		localDir: localDir,
+		prefix:   prefix,
+		req:      o,
+		res:      SyncResponse{DryRun: o.DryRun},
+		errList:  newErrors(),
+	}
+}
+
+func (s *syncer) syncFromDir() (SyncResponse, error) {
+	local, err := s.listLocal()
+	if err != nil {
+		return s.res, err
+	}
+	remote, err := s.listRemote()
+	if err != nil {
+		return s.res, err
+	}
+
+	s.run(sortedKeys(local), func(rel string) {
+		src := local[rel]
+		dst, ok := remote[rel]
+		changed, err := s.isChanged(rel, src, dst, ok, true)
+		if err != nil {
+			s.addError(rel, err)
+			return
+		}
+		if !changed {
+			s.addSkipped(rel)
+			return
+		}
+		if !s.req.DryRun {
+			if err := s.upload(rel); err != nil {
+				s.addError(rel, err)
+				return
+			}
+		}
+		s.addTransferred(rel, src.size)
+	})
+
+	if s.req.Delete {
+		var keys []string
+		var rels []string
+		for _, rel := range sortedKeys(remote) {
+			if _, ok := local[rel]; !ok {
+				keys = append(keys, s.prefix+rel)
+				rels = append(rels, rel)
+			}
+		}
+		if len(keys) != 0 && !s.req.DryRun {
+			res, err := s.bucket.DeleteObjects(keys)
+			if err != nil {
+				s.errList.Add(err)
+			}
+			rels = rels[:0]
+			for _, d := range res.Deleted {
+				rels = append(rels, strings.TrimPrefix(d.Key, s.prefix))
+			}
+		}
+		s.res.Deleted = append(s.res.Deleted, rels...)
+	}
+	return s.result()
+}
+
+func (s *syncer) syncToDir() (SyncResponse, error) {
+	remote, err := s.listRemote()
+	if err != nil {
+		return s.res, err
+	}
+	local, err := s.listLocal()
+	if err != nil && !os.IsNotExist(err) {
+		return s.res, err
+	}
+
+	s.run(sortedKeys(remote), func(rel string) {
+		dst, ok := local[rel]
+		s.syncToLocal(rel, remote[rel], dst, ok)
+	})
+
+	if s.req.Delete {
+		for _, rel := range sortedKeys(local) {
+			if _, ok := remote[rel]; ok {
+				continue
+			}
+			if !s.req.DryRun {
+				if err := os.Remove(s.localPath(rel)); err != nil {
+					s.addError(rel, err)
+					continue
+				}
+			}
+			s.res.Deleted = append(s.res.Deleted, rel)
+		}
+	}
+	return s.result()
+}
+
+// syncToLocal downloads the object when it is changed.
+// The object is rejected when the local path is outside the local directory. (e.g. `prefix/../../file`)
+func (s *syncer) syncToLocal(rel string, src, dst syncEntry, exists bool) {
+	if !s.isInLocalDir(rel) {
+		s.addError(s.prefix+rel, fmt.Errorf("the local path is outside the local directory; dir=%s;", s.localDir))
+		return
+	}
+
+	changed, err := s.isChanged(rel, src, dst, exists, false)
+	if err != nil {
+		s.addError(rel, err)
+		return
+	}
+	if !changed {
+		s.addSkipped(rel)
+		return
+	}
+	if !s.req.DryRun {
+		if err := s.download(rel, src); err != nil {
+			s.addError(rel, err)
+			return
+		}
+	}
+	s.addTransferred(rel, src.size)
+}
+
+// run executes fn for each of the relative paths with bounded concurrency.
+func (s *syncer) run(rels []string, fn func(rel string)) {
+	ch := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < s.req.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range ch {
+				fn(rel)
+			}
+		}()
+	}
+	for _, rel := range rels {
+		ch <- rel
+	}
+	close(ch)
+	wg.Wait()
+}
+
+func (s *syncer) result() (SyncResponse, error) {
+	sort.Strings(s.res.Transferred)
+	sort.Strings(s.res.Skipped)
+	sort.Strings(s.res.Deleted)
+	if s.errList.HasError() {
+		return s.res, s.errList
+	}
+	return s.res, nil
+}
+
+func (s *syncer) addTransferred(rel string, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.res.Transferred = append(s.res.Transferred, rel)
+	s.res.TransferredSize += size
+}
+
+func (s *syncer) addSkipped(rel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.res.Skipped = append(s.res.Skipped, rel)
+}
+
+func (s *syncer) addError(rel string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errList.Add(fmt.Errorf("path=%s; error=%s;", rel, err.Error()))
+}
+
+// listLocal returns the files in the local directory with the slash-separated relative paths.
+func (s *syncer) listLocal() (map[string]syncEntry, error) {
+	files := make(map[string]syncEntry)
+	err := filepath.Walk(s.localDir, func(p string, fi os.FileInfo, err error) error {
+		switch {
+		case err != nil:
+			return err
+		case !fi.Mode().IsRegular():
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.localDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if s.isTarget(rel) {
+			files[rel] = syncEntry{
+				size:    fi.Size(),
+				modTime: fi.ModTime(),
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// listRemote returns the objects under the prefix with the relative paths.
+func (s *syncer) listRemote() (map[string]syncEntry, error) {
+	objects := make(map[string]syncEntry)
+	err := s.bucket.Walk(s.prefix, func(obj Object) error {
+		rel := strings.TrimPrefix(obj.Key, s.prefix)
+		// skip directory placeholders.
+		if rel == "" || strings.HasSuffix(rel, "/") || !s.isTarget(rel) {
+			return nil
+		}
+		objects[rel] = syncEntry{
+			size:    obj.Size,
+			modTime: obj.LastModified,
+			etag:    obj.ETag,
+		}
+		return nil
+	})
+	return objects, err
+}
+
+// isChanged checks the source is different from the destination or not.
+func (s *syncer) isChanged(rel string, src, dst syncEntry, exists, isUpload bool) (bool, error) {
+	switch {
+	case !exists,
+		src.size != dst.size:
+		return true, nil
+	}
+
+	switch s.req.CompareMode {
+	case SyncCompareSize:
+		return false, nil
+	case SyncCompareChecksum:
+		etag := dst.etag
+		if !isUpload {
+			etag = src.etag
+		}
+		sum, err := localETag(s.localPath(rel), etag)
+		if err != nil {
+			return false, err
+		}
+		return sum != strings.Trim(etag, `"`), nil
+	}
+
+	// S3 has the modification time in seconds.
+	srcTime := src.modTime.Truncate(time.Second)
+	dstTime := dst.modTime.Truncate(time.Second)
+	return srcTime.After(dstTime), nil
+}
+
+func (s *syncer) localPath(rel string) string {
+	return filepath.Join(s.localDir, filepath.FromSlash(rel))
+}
+
+// isInLocalDir checks if the local path of rel is a file inside the local directory or not.
+func (s *syncer) isInLocalDir(rel string) bool {
+	p, err := filepath.Rel(filepath.Clean(s.localDir), s.localPath(rel))
+	switch {
+	case err != nil,
+		p == ".",
+		p == "..",
+		strings.HasPrefix(p, ".."+string(filepath.Separator)):
+		return false
+	}
+	return true
+}
+
+func (s *syncer) upload(rel string) error {
+	f, err := os.Open(s.localPath(rel))
+	if err != nil {
+		return err
+	}
+	defer f.Close() // nolint:errcheck
+
+	contentType, err := detectContentType(rel, f)
+	if err != nil {
+		return err
+	}
+
+	acl := s.req.ACL
+	if acl == "" {
+		acl = ACLPrivate
+	}
+	_, err = s.bucket.Upload(context.Background(), s.prefix+rel, f, UploadRequest{
+		ACL:          acl,
+		CacheControl: s.req.CacheControl,
+		ContentType:  contentType,
+		Concurrency:  1,
+	})
+	return err
+}
+
+// download downloads the object into the temporary file and renames it to the destination.
+func (s *syncer) download(rel string, src syncEntry) error {
+	dst := s.localPath(rel)
+	dir := filepath.Dir(dst)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(dst)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint:errcheck
+
+	_, err = s.bucket.Download(context.Background(), s.prefix+rel, tmp, DownloadRequest{
+		Concurrency: 1,
+	})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, src.modTime, src.modTime)
+}
+
+// localETag computes ETag of the local file in the same way as the etag.
+// ETag of multipart upload is computed with the default part size of Upload.
+func localETag(localPath, etag string) (string, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // nolint:errcheck
+
+	if !strings.Contains(etag, "-") {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	partSize := int64(defaultUploadPartSize)
+	if fi, err := f.Stat(); err == nil && fi.Size()/partSize >= MaxUploadParts {
+		partSize = fi.Size()/MaxUploadParts + 1
+	}
+
+	sums := md5.New()
+	parts := 0
+	for {
+		h := md5.New()
+		n, err := io.CopyN(h, f, partSize)
+		if n > 0 {
+			sums.Write(h.Sum(nil))
+			parts++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sums.Sum(nil)), parts), nil
+}
+
+// isTarget checks the relative path matches the include and exclude patterns.
+// The pattern without slash matches the base name, and the pattern with slash matches the whole relative path.
+func (s *syncer) isTarget(rel string) bool {
+	if len(s.req.Include) != 0 && !matchSyncPattern(s.req.Include, rel) {
+		return false
+	}
+	return !matchSyncPattern(s.req.Exclude, rel)
+}
+
+func matchSyncPattern(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		target := rel
+		if !strings.Contains(p, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(p, target); ok {
+			return true
+		}
+		// the pattern of a directory matches the files under it. (e.g. "vendor/")
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(rel, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType detects MIME type from the extension, and from the content when the extension is unknown.
+func detectContentType(name string, r io.ReadSeeker) (string, error) {
+	if typ := mime.TypeByExtension(path.Ext(name)); typ != "" {
+		return typ, nil
+	}
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+func sortedKeys(m map[string]syncEntry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
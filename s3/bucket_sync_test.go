@@ -0,0 +1,256 @@
+package s3
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSyncBucketName = "test-sync-bucket"
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		assert.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+	}
+}
+
+func TestSyncFromDir(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testSyncBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testSyncBucketName)
+	assert.NoError(err)
+	_, err = b.PurgeAllVersions()
+	assert.NoError(err)
+
+	dir, err := ioutil.TempDir("", "s3_sync")
+	assert.NoError(err)
+	defer os.RemoveAll(dir) // nolint:errcheck
+
+	writeTestFiles(t, dir, map[string]string{
+		"index.html":     "<html></html>",
+		"css/app.css":    "body {}",
+		"data":           "<html><body>no extension</body></html>",
+		"tmp/cache.txt":  "cache",
+		"assets/a.tmp":   "tmp",
+		"assets/b.js":    "var b;",
+		"assets/c/d.svg": "<svg></svg>",
+	})
+	opt := SyncRequest{
+		Exclude: []string{"*.tmp", "tmp/"},
+	}
+
+	// dry run
+	res, err := b.SyncFromDir(dir, "site", SyncRequest{Exclude: opt.Exclude, DryRun: true})
+	assert.NoError(err)
+	assert.True(res.DryRun)
+	assert.Equal([]string{"assets/b.js", "assets/c/d.svg", "css/app.css", "data", "index.html"}, res.Transferred)
+	assert.False(b.IsExists("site/index.html"))
+
+	res, err = b.SyncFromDir(dir, "site", opt)
+	assert.NoError(err)
+	assert.Equal([]string{"assets/b.js", "assets/c/d.svg", "css/app.css", "data", "index.html"}, res.Transferred)
+	assert.Empty(res.Skipped)
+	assert.False(b.IsExists("site/tmp/cache.txt"))
+	assert.False(b.IsExists("site/assets/a.tmp"))
+
+	for path, typ := range map[string]string{
+		"site/index.html":     "text/html",
+		"site/css/app.css":    "text/css",
+		"site/data":           "text/html",
+		"site/assets/c/d.svg": "image/svg+xml",
+	} {
+		info, err := b.GetObjectInfo(path)
+		assert.NoError(err)
+		assert.True(strings.HasPrefix(info.ContentType, typ), "%s: %s", path, info.ContentType)
+	}
+
+	// unchanged files are skipped.
+	res, err = b.SyncFromDir(dir, "site/", opt)
+	assert.NoError(err)
+	assert.Empty(res.Transferred)
+	assert.Len(res.Skipped, 5)
+
+	// the changed and the removed files.
+	writeTestFiles(t, dir, map[string]string{"index.html": "<html>changed</html>"})
+	assert.NoError(os.Remove(filepath.Join(dir, "data")))
+	opt.Delete = true
+	res, err = b.SyncFromDir(dir, "site", opt)
+	assert.NoError(err)
+	assert.Equal([]string{"index.html"}, res.Transferred)
+	assert.Equal([]string{"data"}, res.Deleted)
+	assert.False(b.IsExists("site/data"))
+
+	data, err := b.GetObjectByte("site/index.html")
+	assert.NoError(err)
+	assert.Equal("<html>changed</html>", string(data))
+
+	// same size but newer file.
+	writeTestFiles(t, dir, map[string]string{"css/app.css": "body{}}"})
+	future := time.Now().Add(time.Hour)
+	assert.NoError(os.Chtimes(filepath.Join(dir, "css/app.css"), future, future))
+	res, err = b.SyncFromDir(dir, "site", SyncRequest{Exclude: opt.Exclude, CompareMode: SyncCompareSize})
+	assert.NoError(err)
+	assert.Empty(res.Transferred)
+	res, err = b.SyncFromDir(dir, "site", opt)
+	assert.NoError(err)
+	assert.Equal([]string{"css/app.css"}, res.Transferred)
+}
+
+func TestSyncToDir(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testSyncBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testSyncBucketName)
+	assert.NoError(err)
+	_, err = b.PurgeAllVersions()
+	assert.NoError(err)
+
+	for path, content := range map[string]string{
+		"config/app.json":     `{"a":1}`,
+		"config/db/main.yml":  "host: localhost",
+		"config/db/":          "",
+		"config_other/x.json": "{}",
+	} {
+		assert.NoError(b.PutOne(NewPutObjectString(content), path, ACLPrivate))
+	}
+
+	dir, err := ioutil.TempDir("", "s3_sync")
+	assert.NoError(err)
+	defer os.RemoveAll(dir) // nolint:errcheck
+	dest := filepath.Join(dir, "config")
+
+	res, err := b.SyncToDir("config", dest)
+	assert.NoError(err)
+	assert.Equal([]string{"app.json", "db/main.yml"}, res.Transferred)
+	assert.Equal(int64(22), res.TransferredSize)
+
+	data, err := ioutil.ReadFile(filepath.Join(dest, "db", "main.yml"))
+	assert.NoError(err)
+	assert.Equal("host: localhost", string(data))
+
+	// unchanged files are skipped.
+	res, err = b.SyncToDir("config", dest)
+	assert.NoError(err)
+	assert.Empty(res.Transferred)
+	assert.Len(res.Skipped, 2)
+
+	// the local file which has the same size is compared by checksum.
+	writeTestFiles(t, dest, map[string]string{"app.json": `{"a":2}`, "extra.txt": "extra"})
+	res, err = b.SyncToDir("config", dest, SyncRequest{
+		CompareMode: SyncCompareChecksum,
+		Include:     []string{"*.json", "*.txt"},
+		Delete:      true,
+		DryRun:      true,
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"app.json"}, res.Transferred)
+	assert.Equal([]string{"extra.txt"}, res.Deleted)
+	data, err = ioutil.ReadFile(filepath.Join(dest, "app.json"))
+	assert.NoError(err)
+	assert.Equal(`{"a":2}`, string(data))
+
+	res, err = b.SyncToDir("config", dest, SyncRequest{
+		CompareMode: SyncCompareChecksum,
+		Include:     []string{"*.json", "*.txt"},
+		Delete:      true,
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"app.json"}, res.Transferred)
+	data, err = ioutil.ReadFile(filepath.Join(dest, "app.json"))
+	assert.NoError(err)
+	assert.Equal(`{"a":1}`, string(data))
+	_, err = os.Stat(filepath.Join(dest, "extra.txt"))
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dest, "db", "main.yml"))
+	assert.NoError(err, "excluded file is not deleted")
+}
+
+func TestSyncToLocalOutsideDir(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "s3_sync")
+	assert.NoError(err)
+	defer os.RemoveAll(dir) // nolint:errcheck
+	dest := filepath.Join(dir, "config")
+
+	s := newSyncer(nil, dest, "config", SyncRequest{})
+	for _, rel := range []string{"../../x", "../config_other/x", "a/../../x", ".", "a/.."} {
+		assert.False(s.isInLocalDir(rel), rel)
+		s.syncToLocal(rel, syncEntry{size: 1}, syncEntry{}, false)
+	}
+	for _, rel := range []string{"a.json", "db/main.yml", "a/../b", "..a"} {
+		assert.True(s.isInLocalDir(rel), rel)
+	}
+
+	res, err := s.result()
+	assert.Error(err)
+	assert.Contains(err.Error(), "path=config/../../x;")
+	assert.Equal(5, strings.Count(err.Error(), "outside the local directory"))
+	assert.Empty(res.Transferred)
+	_, err = os.Stat(filepath.Join(dir, "x"))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestMatchSyncPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		patterns []string
+		rel      string
+		expected bool
+	}{
+		{[]string{"*.js"}, "a.js", true},
+		{[]string{"*.js"}, "dir/a.js", true},
+		{[]string{"dir/*.js"}, "dir/a.js", true},
+		{[]string{"dir/*.js"}, "dir/sub/a.js", false},
+		{[]string{"dir/"}, "dir/sub/a.js", true},
+		{[]string{"dir/"}, "dir2/a.js", false},
+		{[]string{"*.css", "*.js"}, "a.html", false},
+		{nil, "a.html", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(tt.expected, matchSyncPattern(tt.patterns, tt.rel), "%v %s", tt.patterns, tt.rel)
+	}
+}
+
+func TestLocalETag(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testSyncBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testSyncBucketName)
+	assert.NoError(err)
+
+	f, err := ioutil.TempFile("", "s3_etag")
+	assert.NoError(err)
+	defer os.Remove(f.Name()) // nolint:errcheck
+	_, err = f.Write(testUploadData(defaultUploadPartSize + 100))
+	assert.NoError(err)
+	assert.NoError(f.Close())
+
+	r, err := os.Open(f.Name())
+	assert.NoError(err)
+	defer r.Close() // nolint:errcheck
+	res, err := b.Upload(context.Background(), "etag_test", r)
+	assert.NoError(err)
+	assert.True(res.IsMultipart)
+
+	etag, err := localETag(f.Name(), res.ETag)
+	assert.NoError(err)
+	assert.Equal(strings.Trim(res.ETag, `"`), etag)
+
+	etag, err = localETag(f.Name(), `"single"`)
+	assert.NoError(err)
+	assert.Equal(res.Checksum, etag)
+}
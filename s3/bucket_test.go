@@ -357,3 +357,44 @@ func TestDeleteObject(t *testing.T) {
 	assert.NoError(err)
 	assert.Error(errAfter)
 }
+
+func TestGetObjectInfo(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	obj := NewPutObjectString("testString")
+	obj.SetTypeAsText()
+	obj.SetMetadata("owner", "test")
+	obj.AddTag("env", "dev")
+	obj.SetCacheControl("max-age=60")
+	obj.SetContentDisposition("attachment")
+	obj.SetStorageClass("STANDARD_IA")
+	obj.SetSSEKMS("key-id")
+	assert.NoError(obj.ComputeContentMD5())
+	assert.NoError(b.PutOne(obj, "object_info", ACLPrivate))
+
+	info, err := b.GetObjectInfo("object_info")
+	assert.NoError(err)
+	assert.Equal("object_info", info.Key)
+	assert.Equal(int64(10), info.Size)
+	assert.NotEmpty(info.ETag)
+	assert.False(info.LastModified.IsZero())
+	assert.Equal("text/plain", info.ContentType)
+	assert.Equal("max-age=60", info.CacheControl)
+	assert.Equal("attachment", info.ContentDisposition)
+	assert.Equal("STANDARD_IA", info.StorageClass)
+	assert.Equal("aws:kms", info.ServerSideEncryption)
+	assert.Equal("key-id", info.SSEKMSKeyID)
+	assert.Equal(map[string]string{"owner": "test"}, info.Metadata)
+
+	_, err = b.GetObjectInfo("non_exist/path")
+	assert.Error(err)
+
+	tags, err := b.GetObjectTags("object_info")
+	assert.NoError(err)
+	assert.Equal(map[string]string{"env": "dev"}, tags)
+}
@@ -0,0 +1,385 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+const (
+	// MinPartSize is the minimum size of a part on multipart upload, except the last part.
+	MinPartSize = 5 * 1024 * 1024
+	// MaxUploadParts is the maximum number of parts on multipart upload.
+	MaxUploadParts = 10000
+
+	defaultUploadPartSize    = 8 * 1024 * 1024
+	defaultUploadConcurrency = 4
+)
+
+// Upload uploads the content of the reader to the path.
+// The content larger than the part size is uploaded by multipart upload, and the parts are uploaded concurrently.
+// The multipart upload is aborted on failure, unless `LeavePartsOnError` is set.
+// When `UploadID` is set, the interrupted upload is resumed and the parts already uploaded are skipped.
+func (b *Bucket) Upload(ctx context.Context, path string, r io.Reader, opt ...UploadRequest) (UploadResponse, error) {
+	var o UploadRequest
+	if len(opt) != 0 {
+		o = opt[0]
+	}
+	o.Bucket = b.nameWithPrefix
+	o.Key = path
+
+	u := &uploader{
+		bucket:  b,
+		req:     o,
+		reader:  r,
+		md5hash: md5.New(),
+	}
+	u.init()
+	res, err := u.upload(ctx)
+	if err != nil {
+		b.service.Errorf("error on `Upload`; bucket=%s; path=%s; upload_id=%s; error=%s;", b.nameWithPrefix, path, res.UploadID, err.Error())
+	}
+	return res, err
+}
+
+// uploader uploads an object by PutObject or multipart upload.
+type uploader struct {
+	bucket  *Bucket
+	req     UploadRequest
+	reader  io.Reader
+	md5hash hashWriter
+
+	partSize    int64
+	concurrency int
+	// ETags of the uploaded parts to resume.
+	uploadedParts map[int64]*SDK.Part
+}
+
+type hashWriter interface {
+	io.Writer
+	Sum([]byte) []byte
+}
+
+func (u *uploader) init() {
+	u.partSize = u.req.PartSize
+	if u.partSize == 0 {
+		u.partSize = defaultUploadPartSize
+	}
+	if u.partSize < MinPartSize {
+		u.partSize = MinPartSize
+	}
+
+	// adjust part size to keep the number of parts.
+	if size, ok := readerSize(u.reader); ok && size/u.partSize >= MaxUploadParts {
+		u.partSize = size/MaxUploadParts + 1
+	}
+
+	u.concurrency = u.req.Concurrency
+	if u.concurrency < 1 {
+		u.concurrency = defaultUploadConcurrency
+	}
+}
+
+// readerSize returns the rest size of the reader if the size is known.
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case *bytes.Reader:
+		return int64(v.Len()), true
+	case *bytes.Buffer:
+		return int64(v.Len()), true
+	case *strings.Reader:
+		return int64(v.Len()), true
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - cur, true
+	}
+	return 0, false
+}
+
+func (u *uploader) upload(ctx context.Context) (UploadResponse, error) {
+	first, err := u.nextPart()
+	if err != nil && err != io.EOF {
+		return UploadResponse{}, err
+	}
+	if err == io.EOF && u.req.UploadID == "" {
+		// the content is smaller than the part size.
+		return u.putObject(ctx, first)
+	}
+	return u.multipartUpload(ctx, first, err == io.EOF)
+}
+
+// nextPart reads the next part from the reader.
+// It returns io.EOF with the last part.
+func (u *uploader) nextPart() ([]byte, error) {
+	buf := make([]byte, u.partSize)
+	n, err := io.ReadFull(u.reader, buf)
+	switch err {
+	case nil:
+		u.md5hash.Write(buf)
+		return buf, nil
+	case io.EOF, io.ErrUnexpectedEOF:
+		u.md5hash.Write(buf[:n])
+		return buf[:n], io.EOF
+	}
+	return nil, err
+}
+
+// putObject uploads the content by PutObject operation.
+func (u *uploader) putObject(ctx context.Context, data []byte) (UploadResponse, error) {
+	in := u.req.ToPutObjectInput()
+	in.Body = bytes.NewReader(data)
+	in.ContentLength = pointers.Long64(int64(len(data)))
+	out, err := u.bucket.service.client.PutObjectWithContext(ctx, in)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	checksum := md5.Sum(data)
+	res := UploadResponse{
+		Size:         int64(len(data)),
+		Checksum:     hex.EncodeToString(checksum[:]),
+		ComputedETag: fmt.Sprintf(`"%s"`, hex.EncodeToString(checksum[:])),
+	}
+	if out.ETag != nil {
+		res.ETag = *out.ETag
+	}
+	if out.VersionId != nil {
+		res.VersionID = *out.VersionId
+	}
+	return res, nil
+}
+
+// multipartUpload uploads the content by multipart upload.
+func (u *uploader) multipartUpload(ctx context.Context, first []byte, isLast bool) (res UploadResponse, err error) {
+	cli := u.bucket.service.client
+	res.IsMultipart = true
+	res.UploadID = u.req.UploadID
+	if res.UploadID == "" {
+		out, err := cli.CreateMultipartUploadWithContext(ctx, u.req.ToCreateMultipartUploadInput())
+		if err != nil {
+			return res, err
+		}
+		res.UploadID = *out.UploadId
+	} else if err := u.listUploadedParts(ctx, res.UploadID); err != nil {
+		return res, err
+	}
+
+	defer func() {
+		if err == nil || u.req.LeavePartsOnError {
+			return
+		}
+		_, abortErr := cli.AbortMultipartUpload(&SDK.AbortMultipartUploadInput{
+			Bucket:   pointers.String(u.req.Bucket),
+			Key:      pointers.String(u.req.Key),
+			UploadId: pointers.String(res.UploadID),
+		})
+		if abortErr != nil {
+			u.bucket.service.Errorf("error on `AbortMultipartUpload` operation; bucket=%s; path=%s; upload_id=%s; error=%s;", u.req.Bucket, u.req.Key, res.UploadID, abortErr.Error())
+		}
+	}()
+
+	parts, size, err := u.uploadParts(ctx, res.UploadID, first, isLast)
+	res.Size = size
+	if err != nil {
+		return res, err
+	}
+
+	out, err := cli.CompleteMultipartUploadWithContext(ctx, &SDK.CompleteMultipartUploadInput{
+		Bucket:          pointers.String(u.req.Bucket),
+		Key:             pointers.String(u.req.Key),
+		UploadId:        pointers.String(res.UploadID),
+		MultipartUpload: &SDK.CompletedMultipartUpload{Parts: parts},
+		RequestPayer:    nilIfEmpty(u.req.RequestPayer),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res.Parts = len(parts)
+	res.Checksum = hex.EncodeToString(u.md5hash.Sum(nil))
+	res.ComputedETag = computeMultipartETag(parts)
+	if out.ETag != nil {
+		res.ETag = *out.ETag
+	}
+	if out.VersionId != nil {
+		res.VersionID = *out.VersionId
+	}
+	return res, nil
+}
+
+// listUploadedParts fetches the parts uploaded before to resume.
+func (u *uploader) listUploadedParts(ctx context.Context, uploadID string) error {
+	u.uploadedParts = make(map[int64]*SDK.Part)
+	in := &SDK.ListPartsInput{
+		Bucket:   pointers.String(u.req.Bucket),
+		Key:      pointers.String(u.req.Key),
+		UploadId: pointers.String(uploadID),
+	}
+	return u.bucket.service.client.ListPartsPagesWithContext(ctx, in, func(out *SDK.ListPartsOutput, _ bool) bool {
+		for _, p := range out.Parts {
+			u.uploadedParts[*p.PartNumber] = p
+		}
+		return true
+	})
+}
+
+type uploadPart struct {
+	number int64
+	data   []byte
+}
+
+// uploadParts reads the parts from the reader and uploads them concurrently.
+func (u *uploader) uploadParts(ctx context.Context, uploadID string, first []byte, isLast bool) ([]*SDK.CompletedPart, int64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	var completed []*SDK.CompletedPart
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	partCh := make(chan uploadPart)
+	var wg sync.WaitGroup
+	for i := 0; i < u.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range partCh {
+				etag, err := u.uploadPart(ctx, uploadID, p)
+				if err != nil {
+					setErr(err)
+					continue
+				}
+				mu.Lock()
+				completed = append(completed, &SDK.CompletedPart{
+					ETag:       pointers.String(etag),
+					PartNumber: pointers.Long64(p.number),
+				})
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var size int64
+	data := first
+	for number := int64(1); ; number++ {
+		if number > MaxUploadParts {
+			setErr(fmt.Errorf("the number of parts exceeds the limit; max=%d; part_size=%d;", MaxUploadParts, u.partSize))
+			break
+		}
+		if len(data) != 0 || number == 1 {
+			size += int64(len(data))
+			select {
+			case partCh <- uploadPart{number: number, data: data}:
+			case <-ctx.Done():
+			}
+		}
+		if isLast || ctx.Err() != nil {
+			break
+		}
+
+		var err error
+		data, err = u.nextPart()
+		switch {
+		case err == io.EOF:
+			isLast = true
+		case err != nil:
+			setErr(err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(partCh)
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		return nil, size, firstErr
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return *completed[i].PartNumber < *completed[j].PartNumber
+	})
+	return completed, size, nil
+}
+
+// uploadPart uploads the part and returns the ETag.
+// The part is skipped when the same part has been uploaded already.
+func (u *uploader) uploadPart(ctx context.Context, uploadID string, p uploadPart) (string, error) {
+	sum := md5.Sum(p.data)
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:]))
+	if uploaded, ok := u.uploadedParts[p.number]; ok && uploaded.ETag != nil && *uploaded.ETag == etag {
+		return etag, nil
+	}
+
+	out, err := u.bucket.service.client.UploadPartWithContext(ctx, &SDK.UploadPartInput{
+		Bucket:               pointers.String(u.req.Bucket),
+		Key:                  pointers.String(u.req.Key),
+		UploadId:             pointers.String(uploadID),
+		PartNumber:           pointers.Long64(p.number),
+		Body:                 bytes.NewReader(p.data),
+		ContentLength:        pointers.Long64(int64(len(p.data))),
+		RequestPayer:         nilIfEmpty(u.req.RequestPayer),
+		SSECustomerAlgorithm: nilIfEmpty(u.req.SSECustomerAlgorithm),
+		SSECustomerKey:       nilIfEmpty(u.req.SSECustomerKey),
+		SSECustomerKeyMD5:    nilIfEmpty(u.req.SSECustomerKeyMD5),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.ETag != nil {
+		etag = *out.ETag
+	}
+	return etag, nil
+}
+
+// computeMultipartETag computes the ETag of multipart upload from the ETags of the parts.
+// ETag = MD5(MD5(part1) + MD5(part2) + ...) + "-" + number of parts
+func computeMultipartETag(parts []*SDK.CompletedPart) string {
+	h := md5.New()
+	for _, p := range parts {
+		b, err := hex.DecodeString(strings.Trim(*p.ETag, `"`))
+		if err != nil {
+			return ""
+		}
+		h.Write(b)
+	}
+	return fmt.Sprintf(`"%s-%d"`, hex.EncodeToString(h.Sum(nil)), len(parts))
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return pointers.String(s)
+}
@@ -0,0 +1,169 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
+)
+
+func testUploadData(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+// errorReader returns error after reading the data.
+type errorReader struct {
+	r io.Reader
+}
+
+func (r errorReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err == io.EOF {
+		return n, errors.New("read error")
+	}
+	return n, err
+}
+
+func TestUpload(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	// single upload
+	small := []byte("small content")
+	res, err := b.Upload(context.Background(), "upload_small", bytes.NewReader(small), UploadRequest{
+		ContentType: "text/plain",
+	})
+	assert.NoError(err)
+	assert.False(res.IsMultipart)
+	assert.Equal(res.ETag, res.ComputedETag)
+	assert.Equal(int64(len(small)), res.Size)
+	data, err := b.GetObjectByte("upload_small")
+	assert.NoError(err)
+	assert.Equal(small, data)
+
+	// multipart upload; the size is unknown.
+	large := testUploadData(11 * 1024 * 1024)
+	res, err = b.Upload(context.Background(), "upload_large", io.MultiReader(bytes.NewReader(large)), UploadRequest{
+		PartSize:    MinPartSize,
+		Concurrency: 2,
+	})
+	assert.NoError(err)
+	assert.True(res.IsMultipart)
+	assert.Equal(3, res.Parts)
+	assert.Equal(int64(len(large)), res.Size)
+	assert.Equal(res.ETag, res.ComputedETag)
+	sum := md5.Sum(large)
+	assert.Equal(hex.EncodeToString(sum[:]), res.Checksum)
+	data, err = b.GetObjectByte("upload_large")
+	assert.NoError(err)
+	assert.Equal(large, data)
+}
+
+func TestUploadAbort(t *testing.T) {
+	assert := assert.New(t)
+	createBucket(testPutBucketName)
+
+	svc := getTestClient(t)
+	b, err := svc.GetBucket(testPutBucketName)
+	assert.NoError(err)
+
+	countUploads := func(path string) int {
+		out, err := svc.client.ListMultipartUploads(&SDK.ListMultipartUploadsInput{
+			Bucket: pointers.String(testPutBucketName),
+			Prefix: pointers.String(path),
+		})
+		assert.NoError(err)
+		return len(out.Uploads)
+	}
+
+	data := testUploadData(11 * 1024 * 1024)
+
+	// the upload is aborted on failure.
+	res, err := b.Upload(context.Background(), "upload_abort", errorReader{bytes.NewReader(data)}, UploadRequest{
+		PartSize: MinPartSize,
+	})
+	assert.Error(err)
+	assert.NotEmpty(res.UploadID)
+	assert.Equal(0, countUploads("upload_abort"))
+
+	// the upload is left on failure and resumed.
+	res, err = b.Upload(context.Background(), "upload_resume", errorReader{bytes.NewReader(data)}, UploadRequest{
+		PartSize:          MinPartSize,
+		LeavePartsOnError: true,
+	})
+	assert.Error(err)
+	assert.NotEmpty(res.UploadID)
+	assert.Equal(1, countUploads("upload_resume"))
+
+	res, err = b.Upload(context.Background(), "upload_resume", bytes.NewReader(data), UploadRequest{
+		PartSize: MinPartSize,
+		UploadID: res.UploadID,
+	})
+	assert.NoError(err)
+	assert.Equal(3, res.Parts)
+	assert.Equal(res.ETag, res.ComputedETag)
+	assert.Equal(0, countUploads("upload_resume"))
+	result, err := b.GetObjectByte("upload_resume")
+	assert.NoError(err)
+	assert.Equal(data, result)
+}
+
+func TestComputeMultipartETag(t *testing.T) {
+	assert := assert.New(t)
+
+	sum1 := md5.Sum([]byte("a"))
+	sum2 := md5.Sum([]byte("b"))
+	expected := md5.Sum(append(sum1[:], sum2[:]...))
+
+	etag := computeMultipartETag([]*SDK.CompletedPart{
+		{ETag: pointers.String(`"` + hex.EncodeToString(sum1[:]) + `"`)},
+		{ETag: pointers.String(`"` + hex.EncodeToString(sum2[:]) + `"`)},
+	})
+	assert.Equal(`"`+hex.EncodeToString(expected[:])+`-2"`, etag)
+}
+
+func TestUploaderPartSize(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		partSize int64
+		reader   io.Reader
+		expected int64
+	}{
+		{0, nil, defaultUploadPartSize},
+		{1024, nil, MinPartSize},
+		{10 * 1024 * 1024, nil, 10 * 1024 * 1024},
+		{MinPartSize, bytes.NewReader(make([]byte, MinPartSize)), MinPartSize},
+		{MinPartSize, bytes.NewReader(make([]byte, 0)), MinPartSize},
+	}
+	for _, tt := range tests {
+		u := &uploader{req: UploadRequest{PartSize: tt.partSize}, reader: tt.reader}
+		u.init()
+		assert.Equal(tt.expected, u.partSize)
+		assert.Equal(defaultUploadConcurrency, u.concurrency)
+	}
+
+	// the part size is enlarged to keep the number of parts.
+	size, ok := readerSize(io.NewSectionReader(bytes.NewReader(nil), 0, MinPartSize*MaxUploadParts*2))
+	assert.True(ok)
+	assert.Equal(int64(MinPartSize*MaxUploadParts*2), size)
+	u := &uploader{reader: io.NewSectionReader(bytes.NewReader(nil), 0, MinPartSize*MaxUploadParts*2)}
+	u.init()
+	assert.True(u.partSize*MaxUploadParts >= size)
+}
@@ -165,6 +165,17 @@ func (svc *S3) ForceDeleteBucket(name string) error {
 	return nil
 }
 
+// PurgeAndDeleteBucket deletes S3 bucket by given name after emptying it by Bucket.PurgeAllVersions.
+// WARNING: all of the object versions and delete markers in the bucket are permanently deleted,
+// and they cannot be restored even if the bucket is versioned.
+func (svc *S3) PurgeAndDeleteBucket(name string) error {
+	if _, err := NewBucket(svc, name).PurgeAllVersions(); err != nil {
+		svc.Errorf("error on `PurgeAllVersions`; bucket=%s; error=%s;", name, err.Error())
+		return err
+	}
+	return svc.ForceDeleteBucket(name)
+}
+
 // CopyObject executes `CopyObject` operation.
 func (svc *S3) CopyObject(req CopyObjectRequest) (CopyObjectResponse, error) {
 	out, err := svc.copyObject(req.ToInput())
@@ -2,8 +2,15 @@ package s3
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/base64"
 	"io"
+	"net/url"
 	"os"
+
+	SDK "github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/evalphobia/aws-sdk-go-wrapper/private/pointers"
 )
 
 const (
@@ -17,6 +24,17 @@ type PutObject struct {
 	dataType string
 	dataByte []byte
 	size     int64
+
+	// optional attributes
+	metadata             map[string]string
+	tags                 url.Values
+	cacheControl         string
+	contentEncoding      string
+	contentDisposition   string
+	contentMD5           string
+	storageClass         string
+	serverSideEncryption string
+	sseKMSKeyID          string
 }
 
 // Create new PutObject
@@ -79,3 +97,123 @@ func (o *PutObject) FileType() string {
 func (o *PutObject) SetTypeAsText() {
 	o.dataType = mimeText
 }
+
+// SetFileType sets MIME type.
+func (o *PutObject) SetFileType(typ string) {
+	o.dataType = typ
+}
+
+// SetMetadata sets user-defined metadata. (x-amz-meta-*)
+func (o *PutObject) SetMetadata(key, value string) {
+	if o.metadata == nil {
+		o.metadata = make(map[string]string)
+	}
+	o.metadata[key] = value
+}
+
+// AddTag adds object tag.
+func (o *PutObject) AddTag(key, value string) {
+	if o.tags == nil {
+		o.tags = make(url.Values)
+	}
+	o.tags.Set(key, value)
+}
+
+// SetCacheControl sets Cache-Control.
+func (o *PutObject) SetCacheControl(v string) {
+	o.cacheControl = v
+}
+
+// SetContentEncoding sets Content-Encoding.
+func (o *PutObject) SetContentEncoding(v string) {
+	o.contentEncoding = v
+}
+
+// SetContentDisposition sets Content-Disposition.
+func (o *PutObject) SetContentDisposition(v string) {
+	o.contentDisposition = v
+}
+
+// SetStorageClass sets storage class. (e.g. STANDARD_IA, GLACIER)
+func (o *PutObject) SetStorageClass(v string) {
+	o.storageClass = v
+}
+
+// SetSSES3 sets server-side encryption with S3 managed keys.
+func (o *PutObject) SetSSES3() {
+	o.serverSideEncryption = SDK.ServerSideEncryptionAes256
+	o.sseKMSKeyID = ""
+}
+
+// SetSSEKMS sets server-side encryption with KMS key.
+// The default key is used when keyID is empty.
+func (o *PutObject) SetSSEKMS(keyID string) {
+	o.serverSideEncryption = SDK.ServerSideEncryptionAwsKms
+	o.sseKMSKeyID = keyID
+}
+
+// SetContentMD5 sets base64-encoded MD5 of the content, which is verified by S3 on upload.
+func (o *PutObject) SetContentMD5(v string) {
+	o.contentMD5 = v
+}
+
+// ComputeContentMD5 computes MD5 of the content and sets it as the expected checksum.
+func (o *PutObject) ComputeContentMD5() error {
+	h := md5.New()
+	if _, err := o.data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(h, o.data); err != nil {
+		return err
+	}
+	if _, err := o.data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	o.contentMD5 = base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// toInput creates *SDK.PutObjectInput from the object.
+func (o *PutObject) toInput(bucket, path, acl string) *SDK.PutObjectInput {
+	in := &SDK.PutObjectInput{
+		ACL:           pointers.String(acl),
+		Bucket:        pointers.String(bucket),
+		Body:          o.data,
+		ContentLength: pointers.Long64(o.Size()),
+		ContentType:   pointers.String(o.FileType()),
+		Key:           pointers.String(path),
+	}
+
+	if len(o.metadata) != 0 {
+		m := make(map[string]*string, len(o.metadata))
+		for k, v := range o.metadata {
+			m[k] = pointers.String(v)
+		}
+		in.SetMetadata(m)
+	}
+	if len(o.tags) != 0 {
+		in.SetTagging(o.tags.Encode())
+	}
+	if o.cacheControl != "" {
+		in.SetCacheControl(o.cacheControl)
+	}
+	if o.contentEncoding != "" {
+		in.SetContentEncoding(o.contentEncoding)
+	}
+	if o.contentDisposition != "" {
+		in.SetContentDisposition(o.contentDisposition)
+	}
+	if o.contentMD5 != "" {
+		in.SetContentMD5(o.contentMD5)
+	}
+	if o.storageClass != "" {
+		in.SetStorageClass(o.storageClass)
+	}
+	if o.serverSideEncryption != "" {
+		in.SetServerSideEncryption(o.serverSideEncryption)
+	}
+	if o.sseKMSKeyID != "" {
+		in.SetSSEKMSKeyId(o.sseKMSKeyID)
+	}
+	return in
+}
@@ -112,3 +112,44 @@ func TestSetTypeAsText(t *testing.T) {
 	obj.SetTypeAsText()
 	assert.Equal("text/plain", obj.FileType())
 }
+
+func TestPutObjectAttributes(t *testing.T) {
+	assert := assert.New(t)
+
+	obj := NewPutObjectString("testString")
+	obj.SetFileType("application/json")
+	obj.SetMetadata("owner", "test")
+	obj.AddTag("env", "dev")
+	obj.AddTag("team", "a&b")
+	obj.SetCacheControl("max-age=60")
+	obj.SetContentEncoding("gzip")
+	obj.SetContentDisposition("attachment")
+	obj.SetStorageClass("STANDARD_IA")
+	obj.SetSSEKMS("key-id")
+	assert.NoError(obj.ComputeContentMD5())
+
+	req := obj.toInput("bucket", "path", ACLPrivate)
+	assert.Equal("bucket", *req.Bucket)
+	assert.Equal("path", *req.Key)
+	assert.Equal("private", *req.ACL)
+	assert.Equal("application/json", *req.ContentType)
+	assert.Equal("test", *req.Metadata["owner"])
+	assert.Equal("env=dev&team=a%26b", *req.Tagging)
+	assert.Equal("max-age=60", *req.CacheControl)
+	assert.Equal("gzip", *req.ContentEncoding)
+	assert.Equal("attachment", *req.ContentDisposition)
+	assert.Equal("STANDARD_IA", *req.StorageClass)
+	assert.Equal("aws:kms", *req.ServerSideEncryption)
+	assert.Equal("key-id", *req.SSEKMSKeyId)
+	assert.Equal("U2eI9Nvf/uz7uPNQqUHuow==", *req.ContentMD5)
+
+	// the content can be read after computing MD5.
+	data, err := ioutil.ReadAll(obj.Content())
+	assert.NoError(err)
+	assert.Equal("testString", string(data))
+
+	obj.SetSSES3()
+	req = obj.toInput("bucket", "path", ACLPrivate)
+	assert.Equal("AES256", *req.ServerSideEncryption)
+	assert.Nil(req.SSEKMSKeyId)
+}
@@ -226,3 +226,402 @@ func (r ListObjectsRequest) ToInput() *SDK.ListObjectsV2Input {
 	}
 	return in
 }
+
+// UploadRequest has parameters for `Upload`.
+type UploadRequest struct {
+	Bucket string
+	Key    string
+
+	// PartSize is the size of each part on multipart upload. (default: 8MB, minimum: 5MB)
+	// The content smaller than PartSize is uploaded by single PutObject.
+	PartSize int64
+	// Concurrency is the number of parts uploaded concurrently. (default: 4)
+	Concurrency int
+	// UploadID is used to resume the interrupted multipart upload.
+	UploadID string
+	// LeavePartsOnError prevents aborting multipart upload on failure to resume it later.
+	LeavePartsOnError bool
+
+	// optional params
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_CreateMultipartUpload.html
+	ACL                     string
+	CacheControl            string
+	ContentDisposition      string
+	ContentEncoding         string
+	ContentLanguage         string
+	ContentType             string
+	Expires                 time.Time
+	Metadata                map[string]string
+	RequestPayer            string
+	SSECustomerAlgorithm    string
+	SSECustomerKey          string
+	SSECustomerKeyMD5       string
+	SSEKMSEncryptionContext string
+	SSEKMSKeyID             string
+	ServerSideEncryption    string
+	StorageClass            string
+	Tagging                 string
+}
+
+func (r UploadRequest) ToPutObjectInput() *SDK.PutObjectInput {
+	in := &SDK.PutObjectInput{}
+	if r.Bucket != "" {
+		in.SetBucket(r.Bucket)
+	}
+	if r.Key != "" {
+		in.SetKey(r.Key)
+	}
+
+	if r.ACL != "" {
+		in.SetACL(r.ACL)
+	}
+	if r.CacheControl != "" {
+		in.SetCacheControl(r.CacheControl)
+	}
+	if r.ContentDisposition != "" {
+		in.SetContentDisposition(r.ContentDisposition)
+	}
+	if r.ContentEncoding != "" {
+		in.SetContentEncoding(r.ContentEncoding)
+	}
+	if r.ContentLanguage != "" {
+		in.SetContentLanguage(r.ContentLanguage)
+	}
+	if r.ContentType != "" {
+		in.SetContentType(r.ContentType)
+	}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.SSECustomerAlgorithm != "" {
+		in.SetSSECustomerAlgorithm(r.SSECustomerAlgorithm)
+	}
+	if r.SSECustomerKey != "" {
+		in.SetSSECustomerKey(r.SSECustomerKey)
+	}
+	if r.SSECustomerKeyMD5 != "" {
+		in.SetSSECustomerKeyMD5(r.SSECustomerKeyMD5)
+	}
+	if r.SSEKMSEncryptionContext != "" {
+		in.SetSSEKMSEncryptionContext(r.SSEKMSEncryptionContext)
+	}
+	if r.SSEKMSKeyID != "" {
+		in.SetSSEKMSKeyId(r.SSEKMSKeyID)
+	}
+	if r.ServerSideEncryption != "" {
+		in.SetServerSideEncryption(r.ServerSideEncryption)
+	}
+	if r.StorageClass != "" {
+		in.SetStorageClass(r.StorageClass)
+	}
+	if r.Tagging != "" {
+		in.SetTagging(r.Tagging)
+	}
+
+	if !r.Expires.IsZero() {
+		in.SetExpires(r.Expires)
+	}
+	if len(r.Metadata) != 0 {
+		m := make(map[string]*string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			m[k] = pointers.String(v)
+		}
+		in.SetMetadata(m)
+	}
+	return in
+}
+
+func (r UploadRequest) ToCreateMultipartUploadInput() *SDK.CreateMultipartUploadInput {
+	p := r.ToPutObjectInput()
+	return &SDK.CreateMultipartUploadInput{
+		Bucket:                  p.Bucket,
+		Key:                     p.Key,
+		ACL:                     p.ACL,
+		CacheControl:            p.CacheControl,
+		ContentDisposition:      p.ContentDisposition,
+		ContentEncoding:         p.ContentEncoding,
+		ContentLanguage:         p.ContentLanguage,
+		ContentType:             p.ContentType,
+		Expires:                 p.Expires,
+		Metadata:                p.Metadata,
+		RequestPayer:            p.RequestPayer,
+		SSECustomerAlgorithm:    p.SSECustomerAlgorithm,
+		SSECustomerKey:          p.SSECustomerKey,
+		SSECustomerKeyMD5:       p.SSECustomerKeyMD5,
+		SSEKMSEncryptionContext: p.SSEKMSEncryptionContext,
+		SSEKMSKeyId:             p.SSEKMSKeyId,
+		ServerSideEncryption:    p.ServerSideEncryption,
+		StorageClass:            p.StorageClass,
+		Tagging:                 p.Tagging,
+	}
+}
+
+// DownloadRequest has parameters for `Download`.
+type DownloadRequest struct {
+	Bucket string
+	Key    string
+
+	// PartSize is the size of each ranged GET request. (default: 8MB)
+	PartSize int64
+	// Concurrency is the number of ranged GET requests executed concurrently. (default: 4)
+	Concurrency int
+
+	// optional params
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html
+	IfMatch              string
+	RequestPayer         string
+	SSECustomerAlgorithm string
+	SSECustomerKey       string
+	SSECustomerKeyMD5    string
+	VersionID            string
+}
+
+func (r DownloadRequest) ToHeadObjectInput() *SDK.HeadObjectInput {
+	in := &SDK.HeadObjectInput{}
+	if r.Bucket != "" {
+		in.SetBucket(r.Bucket)
+	}
+	if r.Key != "" {
+		in.SetKey(r.Key)
+	}
+
+	if r.IfMatch != "" {
+		in.SetIfMatch(r.IfMatch)
+	}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.SSECustomerAlgorithm != "" {
+		in.SetSSECustomerAlgorithm(r.SSECustomerAlgorithm)
+	}
+	if r.SSECustomerKey != "" {
+		in.SetSSECustomerKey(r.SSECustomerKey)
+	}
+	if r.SSECustomerKeyMD5 != "" {
+		in.SetSSECustomerKeyMD5(r.SSECustomerKeyMD5)
+	}
+	if r.VersionID != "" {
+		in.SetVersionId(r.VersionID)
+	}
+	return in
+}
+
+func (r DownloadRequest) ToGetObjectInput() *SDK.GetObjectInput {
+	in := &SDK.GetObjectInput{}
+	if r.Bucket != "" {
+		in.SetBucket(r.Bucket)
+	}
+	if r.Key != "" {
+		in.SetKey(r.Key)
+	}
+
+	if r.IfMatch != "" {
+		in.SetIfMatch(r.IfMatch)
+	}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.SSECustomerAlgorithm != "" {
+		in.SetSSECustomerAlgorithm(r.SSECustomerAlgorithm)
+	}
+	if r.SSECustomerKey != "" {
+		in.SetSSECustomerKey(r.SSECustomerKey)
+	}
+	if r.SSECustomerKeyMD5 != "" {
+		in.SetSSECustomerKeyMD5(r.SSECustomerKeyMD5)
+	}
+	if r.VersionID != "" {
+		in.SetVersionId(r.VersionID)
+	}
+	return in
+}
+
+// PresignRequest has parameters for presigned URL.
+type PresignRequest struct {
+	// Expire is the duration of the URL validity. (default: the expire setting of the Bucket)
+	Expire time.Duration
+	// Headers are the custom headers signed in the URL.
+	// The client must send the same headers along with the request.
+	Headers map[string]string
+
+	// common params
+	RequestPayer         string
+	SSECustomerAlgorithm string
+	SSECustomerKey       string
+	SSECustomerKeyMD5    string
+	VersionID            string
+
+	// params for GET
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html
+	ResponseCacheControl       string
+	ResponseContentDisposition string
+	ResponseContentEncoding    string
+	ResponseContentLanguage    string
+	ResponseContentType        string
+	ResponseExpires            time.Time
+
+	// params for PUT
+	// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObject.html
+	ACL                  string
+	CacheControl         string
+	ContentDisposition   string
+	ContentEncoding      string
+	ContentMD5           string
+	ContentType          string
+	Metadata             map[string]string
+	SSEKMSKeyID          string
+	ServerSideEncryption string
+	StorageClass         string
+	Tagging              string
+}
+
+func (r PresignRequest) ToGetObjectInput() *SDK.GetObjectInput {
+	in := &SDK.GetObjectInput{}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.SSECustomerAlgorithm != "" {
+		in.SetSSECustomerAlgorithm(r.SSECustomerAlgorithm)
+	}
+	if r.SSECustomerKey != "" {
+		in.SetSSECustomerKey(r.SSECustomerKey)
+	}
+	if r.SSECustomerKeyMD5 != "" {
+		in.SetSSECustomerKeyMD5(r.SSECustomerKeyMD5)
+	}
+	if r.VersionID != "" {
+		in.SetVersionId(r.VersionID)
+	}
+
+	if r.ResponseCacheControl != "" {
+		in.SetResponseCacheControl(r.ResponseCacheControl)
+	}
+	if r.ResponseContentDisposition != "" {
+		in.SetResponseContentDisposition(r.ResponseContentDisposition)
+	}
+	if r.ResponseContentEncoding != "" {
+		in.SetResponseContentEncoding(r.ResponseContentEncoding)
+	}
+	if r.ResponseContentLanguage != "" {
+		in.SetResponseContentLanguage(r.ResponseContentLanguage)
+	}
+	if r.ResponseContentType != "" {
+		in.SetResponseContentType(r.ResponseContentType)
+	}
+	if !r.ResponseExpires.IsZero() {
+		in.SetResponseExpires(r.ResponseExpires)
+	}
+	return in
+}
+
+func (r PresignRequest) ToPutObjectInput() *SDK.PutObjectInput {
+	in := &SDK.PutObjectInput{}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.SSECustomerAlgorithm != "" {
+		in.SetSSECustomerAlgorithm(r.SSECustomerAlgorithm)
+	}
+	if r.SSECustomerKey != "" {
+		in.SetSSECustomerKey(r.SSECustomerKey)
+	}
+	if r.SSECustomerKeyMD5 != "" {
+		in.SetSSECustomerKeyMD5(r.SSECustomerKeyMD5)
+	}
+
+	if r.ACL != "" {
+		in.SetACL(r.ACL)
+	}
+	if r.CacheControl != "" {
+		in.SetCacheControl(r.CacheControl)
+	}
+	if r.ContentDisposition != "" {
+		in.SetContentDisposition(r.ContentDisposition)
+	}
+	if r.ContentEncoding != "" {
+		in.SetContentEncoding(r.ContentEncoding)
+	}
+	if r.ContentMD5 != "" {
+		in.SetContentMD5(r.ContentMD5)
+	}
+	if r.ContentType != "" {
+		in.SetContentType(r.ContentType)
+	}
+	if r.SSEKMSKeyID != "" {
+		in.SetSSEKMSKeyId(r.SSEKMSKeyID)
+	}
+	if r.ServerSideEncryption != "" {
+		in.SetServerSideEncryption(r.ServerSideEncryption)
+	}
+	if r.StorageClass != "" {
+		in.SetStorageClass(r.StorageClass)
+	}
+	if r.Tagging != "" {
+		in.SetTagging(r.Tagging)
+	}
+	if len(r.Metadata) != 0 {
+		m := make(map[string]*string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			m[k] = pointers.String(v)
+		}
+		in.SetMetadata(m)
+	}
+	return in
+}
+
+func (r PresignRequest) ToDeleteObjectInput() *SDK.DeleteObjectInput {
+	in := &SDK.DeleteObjectInput{}
+	if r.RequestPayer != "" {
+		in.SetRequestPayer(r.RequestPayer)
+	}
+	if r.VersionID != "" {
+		in.SetVersionId(r.VersionID)
+	}
+	return in
+}
+
+// PostPolicyRequest has parameters for presigned POST form.
+// ref: https://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
+type PostPolicyRequest struct {
+	// Expire is the duration of the policy validity. (default: the expire setting of the Bucket)
+	Expire time.Duration
+	// KeyPrefix allows any key starting with the prefix instead of the exact key.
+	KeyPrefix string
+	// ContentLengthMin and ContentLengthMax restrict the size of the uploaded file. (used when ContentLengthMax > 0)
+	ContentLengthMin int64
+	ContentLengthMax int64
+	// ContentTypePrefix allows any Content-Type starting with the prefix. (e.g. "image/")
+	ContentTypePrefix string
+
+	// optional form fields
+	ACL                   string
+	CacheControl          string
+	ContentDisposition    string
+	ContentType           string
+	Metadata              map[string]string
+	SSEKMSKeyID           string
+	ServerSideEncryption  string
+	StorageClass          string
+	SuccessActionRedirect string
+	SuccessActionStatus   string
+}
+
+// SyncRequest has parameters for `SyncFromDir` and `SyncToDir`.
+type SyncRequest struct {
+	// CompareMode is the way to detect the changed files. (default: SyncCompareSizeAndModTime)
+	CompareMode string
+	// Concurrency is the number of files transferred concurrently. (default: 4)
+	Concurrency int
+	// Delete deletes the files in the destination which don't exist in the source.
+	Delete bool
+	// Include and Exclude are glob patterns of the relative paths. (e.g. "*.html", "assets/*.js", "tmp/")
+	// Excluded files are neither transferred nor deleted.
+	Include []string
+	Exclude []string
+	// DryRun reports the files to transfer and delete without changes.
+	DryRun bool
+
+	// params for upload
+	ACL          string
+	CacheControl string
+}
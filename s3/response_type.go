@@ -1,6 +1,10 @@
 package s3
 
 import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	SDK "github.com/aws/aws-sdk-go/service/s3"
@@ -181,3 +185,323 @@ func NewObject(d *SDK.Object) Object {
 	}
 	return o
 }
+
+// UploadResponse contains data from Upload.
+type UploadResponse struct {
+	// ETag is returned from S3.
+	ETag string
+	// ComputedETag is computed from the uploaded content to verify ETag.
+	// It's MD5 of the content on single upload, and MD5 of the MD5s of the parts with the number of parts on multipart upload.
+	ComputedETag string
+	// Checksum is hex encoded MD5 of the whole content.
+	Checksum    string
+	Size        int64
+	VersionID   string
+	IsMultipart bool
+	UploadID    string
+	Parts       int
+}
+
+// DownloadResponse contains data from Download.
+type DownloadResponse struct {
+	Size         int64
+	ETag         string
+	VersionID    string
+	ContentType  string
+	LastModified time.Time
+	// Parts is the number of ranged GET requests.
+	Parts int
+}
+
+func NewDownloadResponse(out *SDK.HeadObjectOutput) DownloadResponse {
+	r := DownloadResponse{}
+	if out == nil {
+		return r
+	}
+
+	if out.ContentLength != nil {
+		r.Size = *out.ContentLength
+	}
+	if out.ETag != nil {
+		r.ETag = *out.ETag
+	}
+	if out.VersionId != nil {
+		r.VersionID = *out.VersionId
+	}
+	if out.ContentType != nil {
+		r.ContentType = *out.ContentType
+	}
+	if out.LastModified != nil {
+		r.LastModified = *out.LastModified
+	}
+	return r
+}
+
+// PresignResponse contains presigned URL.
+type PresignResponse struct {
+	URL    string
+	Method string
+	// SignedHeader is the headers which must be sent along with the request.
+	SignedHeader http.Header
+}
+
+// PostPolicyResponse contains URL and form fields for presigned POST.
+type PostPolicyResponse struct {
+	URL        string
+	Fields     map[string]string
+	Expiration time.Time
+}
+
+// ObjectInfo contains the attributes of the object from HeadObject.
+type ObjectInfo struct {
+	Key                  string
+	Size                 int64
+	ETag                 string
+	LastModified         time.Time
+	VersionID            string
+	ContentType          string
+	ContentEncoding      string
+	ContentDisposition   string
+	ContentLanguage      string
+	CacheControl         string
+	Expires              string
+	StorageClass         string
+	ServerSideEncryption string
+	SSEKMSKeyID          string
+	Metadata             map[string]string
+}
+
+func NewObjectInfo(head *SDK.HeadObjectOutput) ObjectInfo {
+	r := ObjectInfo{}
+	if head != nil {
+		if head.ContentLength != nil {
+			r.Size = *head.ContentLength
+		}
+		if head.ETag != nil {
+			r.ETag = *head.ETag
+		}
+		if head.LastModified != nil {
+			r.LastModified = *head.LastModified
+		}
+		if head.VersionId != nil {
+			r.VersionID = *head.VersionId
+		}
+		if head.ContentType != nil {
+			r.ContentType = *head.ContentType
+		}
+		if head.ContentEncoding != nil {
+			r.ContentEncoding = *head.ContentEncoding
+		}
+		if head.ContentDisposition != nil {
+			r.ContentDisposition = *head.ContentDisposition
+		}
+		if head.ContentLanguage != nil {
+			r.ContentLanguage = *head.ContentLanguage
+		}
+		if head.CacheControl != nil {
+			r.CacheControl = *head.CacheControl
+		}
+		if head.Expires != nil {
+			r.Expires = *head.Expires
+		}
+		if head.StorageClass != nil {
+			r.StorageClass = *head.StorageClass
+		}
+		if head.ServerSideEncryption != nil {
+			r.ServerSideEncryption = *head.ServerSideEncryption
+		}
+		if head.SSEKMSKeyId != nil {
+			r.SSEKMSKeyID = *head.SSEKMSKeyId
+		}
+		if len(head.Metadata) != 0 {
+			r.Metadata = make(map[string]string, len(head.Metadata))
+			for k, v := range head.Metadata {
+				if v != nil {
+					r.Metadata[strings.ToLower(k)] = *v
+				}
+			}
+		}
+	}
+
+	return r
+}
+
+// DeleteObjectsResponse contains data from DeleteObjects.
+type DeleteObjectsResponse struct {
+	Deleted []DeletedObject
+	Errors  []DeleteObjectError
+}
+
+// DeletedObject is the deleted object in DeleteObjects operation.
+type DeletedObject struct {
+	Key                   string
+	VersionID             string
+	DeleteMarker          bool
+	DeleteMarkerVersionID string
+}
+
+// DeleteObjectError is the error of a key in DeleteObjects operation.
+type DeleteObjectError struct {
+	Key       string
+	VersionID string
+	Code      string
+	Message   string
+}
+
+func (e DeleteObjectError) Error() string {
+	return fmt.Sprintf("key=%s; version_id=%s; code=%s; message=%s;", e.Key, e.VersionID, e.Code, e.Message)
+}
+
+func NewDeleteObjectsResponse(out *SDK.DeleteObjectsOutput) DeleteObjectsResponse {
+	r := DeleteObjectsResponse{}
+	if out == nil {
+		return r
+	}
+
+	for _, d := range out.Deleted {
+		v := DeletedObject{}
+		if d.Key != nil {
+			v.Key = *d.Key
+		}
+		if d.VersionId != nil {
+			v.VersionID = *d.VersionId
+		}
+		if d.DeleteMarker != nil {
+			v.DeleteMarker = *d.DeleteMarker
+		}
+		if d.DeleteMarkerVersionId != nil {
+			v.DeleteMarkerVersionID = *d.DeleteMarkerVersionId
+		}
+		r.Deleted = append(r.Deleted, v)
+	}
+	for _, e := range out.Errors {
+		v := DeleteObjectError{}
+		if e.Key != nil {
+			v.Key = *e.Key
+		}
+		if e.VersionId != nil {
+			v.VersionID = *e.VersionId
+		}
+		if e.Code != nil {
+			v.Code = *e.Code
+		}
+		if e.Message != nil {
+			v.Message = *e.Message
+		}
+		r.Errors = append(r.Errors, v)
+	}
+	return r
+}
+
+// SyncResponse contains the result of SyncFromDir and SyncToDir.
+// The paths are relative to the local directory and the prefix.
+type SyncResponse struct {
+	DryRun          bool
+	Transferred     []string
+	Skipped         []string
+	Deleted         []string
+	TransferredSize int64
+}
+
+// ListDirResponse contains the result of ListDir.
+type ListDirResponse struct {
+	Prefix string
+	// Dirs are the common prefixes with the trailing slash.
+	Dirs    []string
+	Objects []Object
+}
+
+// ObjectVersion is a version or a delete marker of the object.
+type ObjectVersion struct {
+	Key            string
+	VersionID      string
+	IsLatest       bool
+	IsDeleteMarker bool
+	LastModified   time.Time
+
+	// empty on delete marker
+	ETag         string
+	Size         int64
+	StorageClass string
+
+	OwnerID          string
+	OwnerDisplayName string
+}
+
+// NewObjectVersions returns the versions and the delete markers from ListObjectVersions,
+// sorted in the order of the keys and from the latest version.
+func NewObjectVersions(out *SDK.ListObjectVersionsOutput) []ObjectVersion {
+	if out == nil {
+		return nil
+	}
+
+	list := make([]ObjectVersion, 0, len(out.Versions)+len(out.DeleteMarkers))
+	for _, v := range out.Versions {
+		o := ObjectVersion{}
+		if v.Key != nil {
+			o.Key = *v.Key
+		}
+		if v.VersionId != nil {
+			o.VersionID = *v.VersionId
+		}
+		if v.IsLatest != nil {
+			o.IsLatest = *v.IsLatest
+		}
+		if v.LastModified != nil {
+			o.LastModified = *v.LastModified
+		}
+		if v.ETag != nil {
+			o.ETag = *v.ETag
+		}
+		if v.Size != nil {
+			o.Size = *v.Size
+		}
+		if v.StorageClass != nil {
+			o.StorageClass = *v.StorageClass
+		}
+		if v.Owner != nil {
+			if v.Owner.ID != nil {
+				o.OwnerID = *v.Owner.ID
+			}
+			if v.Owner.DisplayName != nil {
+				o.OwnerDisplayName = *v.Owner.DisplayName
+			}
+		}
+		list = append(list, o)
+	}
+	for _, v := range out.DeleteMarkers {
+		o := ObjectVersion{IsDeleteMarker: true}
+		if v.Key != nil {
+			o.Key = *v.Key
+		}
+		if v.VersionId != nil {
+			o.VersionID = *v.VersionId
+		}
+		if v.IsLatest != nil {
+			o.IsLatest = *v.IsLatest
+		}
+		if v.LastModified != nil {
+			o.LastModified = *v.LastModified
+		}
+		if v.Owner != nil {
+			if v.Owner.ID != nil {
+				o.OwnerID = *v.Owner.ID
+			}
+			if v.Owner.DisplayName != nil {
+				o.OwnerDisplayName = *v.Owner.DisplayName
+			}
+		}
+		list = append(list, o)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Key != list[j].Key {
+			return list[i].Key < list[j].Key
+		}
+		if list[i].IsLatest != list[j].IsLatest {
+			return list[i].IsLatest
+		}
+		return list[i].LastModified.After(list[j].LastModified)
+	})
+	return list
+}